@@ -1,11 +1,120 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // the timezones of the temporal factors resolve on hosts without a zoneinfo database
+
 	"pricingengine/service"
+	"pricingengine/service/app"
+	"pricingengine/service/auth"
+	"pricingengine/service/certs"
+	"pricingengine/service/customers"
+	"pricingengine/service/grpcapi"
+	"pricingengine/service/logging"
+	"pricingengine/service/promo"
+	"pricingengine/service/ratelimit"
+	"pricingengine/service/settings"
+	"pricingengine/service/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-// Main method that invokes the service and starts it at default port
+// Main method that loads the settings, invokes the service and starts it at the configured address
+// The gRPC server is started alongside on its own address, sharing the same App
+// SIGINT and SIGTERM drain both servers and the running jobs together, for at most the shutdown timeout
 func main() {
-	service := service.Service{}
-	service.Start("")
+	config, err := settings.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		slog.Error("Invalid settings", "error", err)
+		os.Exit(2)
+	}
+	if err := logging.Setup(os.Stdout, config.LogLevel); err != nil {
+		slog.Error("Invalid settings", "error", err)
+		os.Exit(2)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:    config.Tracing.Exporter,
+		Endpoint:    config.Tracing.Endpoint,
+		Insecure:    config.Tracing.Insecure,
+		SampleRatio: config.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("Error while setting up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	pricingApp := &app.App{
+		ConfigPath:         config.ConfigPath(),
+		CacheTTL:           int64(config.CacheTTL.Seconds()),
+		MaxCoverStartAhead: config.MaxCoverStartAhead.Duration,
+		PromoUsage:         &promo.UsageStore{File: config.PromoUsageFile},
+		Customers:          &customers.Store{Dir: config.CustomersDir},
+	}
+	var authenticator *auth.Authenticator
+	if config.Auth.Enabled() {
+		if authenticator, err = auth.New(config.Auth.Options()); err != nil {
+			slog.Error("Error while loading the credentials", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("Authentication is disabled, every endpoint is open")
+	}
+	var limiter *ratelimit.Limiter
+	if len(config.RateLimitsFile) > 0 {
+		if limiter, err = ratelimit.Load(config.RateLimitsFile); err != nil {
+			slog.Error("Error while loading the rate limits", "error", err)
+			os.Exit(1)
+		}
+	}
+	var reloader *certs.Reloader
+	grpcOptions := []grpc.ServerOption{}
+	if config.TLS.Enabled() {
+		if reloader, err = certs.NewReloader(config.TLS.Options()); err != nil {
+			slog.Error("Error while loading the TLS certificates", "error", err)
+			os.Exit(1)
+		}
+		go reloader.Watch(ctx, config.TLS.ReloadInterval.Duration)
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(reloader.Config())))
+	}
+	grpcServer := grpcapi.NewServer(pricingApp, grpcOptions...)
+	grpcServer.Auth = authenticator
+	grpcServer.Limiter = limiter
+	if config.Features.GRPC {
+		listener, err := net.Listen("tcp", config.GRPCListenAddress)
+		if err != nil {
+			slog.Error("Error while starting gRPC Server", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Error("gRPC Server stopped", "error", err)
+				stop()
+			}
+		}()
+	}
+
+	// the gRPC server is drained by the service, along with the HTTP server and the jobs under the same deadline
+	drainGRPC := func(drain context.Context) {
+		if err := grpcServer.Shutdown(drain); err != nil {
+			slog.Error("Error while stopping gRPC Server", "error", err)
+		}
+	}
+	service := service.Service{App: pricingApp, Auth: authenticator, Limiter: limiter, TLS: reloader, Settings: *config, Drains: []func(context.Context){drainGRPC}}
+	err = service.Start(ctx)
+
+	drain, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout.Duration)
+	defer cancel()
+	shutdownTracing(drain)
+	if err != nil {
+		slog.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
 }
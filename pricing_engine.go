@@ -1,11 +1,47 @@
 package pricingengine
 
+import "encoding/json"
+
 // GeneratePricingRequest is used for generate pricing requests, it holds the
 // inputs that are used to provide pricing for a given user.
 type GeneratePricingRequest struct {
   DateOfBirth string `json:"date_of_birth"`
   InsuranceGroup int `json:"insurance_group"`
   LicenseHeldSince string `json:"license_held_since"`
+  Occupation string `json:"occupation,omitempty"` // optional, rated by the categorical factors
+  FuelType string `json:"fuel_type,omitempty"` // optional, e.g. petrol, diesel, electric
+  CoverPurpose string `json:"cover_purpose,omitempty"` // optional, e.g. social, commuting, business
+  Postcode string `json:"postcode,omitempty"` // optional UK postcode, rated by the postcode factor
+  VehicleRegistration string `json:"vehicle_registration,omitempty"` // optional, looked up in the vehicle table
+  VehicleCode string `json:"vehicle_code,omitempty"` // optional ABI vehicle code, looked up when there is no registration
+  Claims []Claim `json:"claims,omitempty"` // optional claims history, loaded by the history loadings
+  Convictions []Conviction `json:"convictions,omitempty"` // optional motoring convictions, loaded by the history loadings
+  CoverStart string `json:"cover_start,omitempty"` // optional RFC 3339 start of the cover, rated by the temporal factors
+  PromoCode string `json:"promo_code,omitempty"` // optional promotional code, discounted from the premiums it applies to
+  CustomerID string `json:"customer_id,omitempty"` // optional, its history in the customer store is rated by the loyalty factor
+  AdditionalDrivers []Driver `json:"additional_drivers,omitempty"` // optional, combined with the main driver by the driver combination rule
+}
+
+// Driver - an additional driver of the vehicle, the main driver being the one of the request
+// Name is optional, it tells the driver apart in the labels and the decline messages
+type Driver struct {
+  Name string `json:"name,omitempty"`
+  DateOfBirth string `json:"date_of_birth"`
+  LicenseHeldSince string `json:"license_held_since"`
+}
+
+// Claim - a claim of the driver, Date is when it happened
+type Claim struct {
+  Date string `json:"date"`
+  Type string `json:"type"`
+  Fault bool `json:"fault"`
+}
+
+// Conviction - a motoring conviction of the driver, e.g. SP30, Date is the date of the offence
+type Conviction struct {
+  Code string `json:"code"`
+  Date string `json:"date"`
+  Points int `json:"points"`
 }
 
 // GeneratePricingResponse - contains the list of all pricing generated for the request passed
@@ -17,6 +53,28 @@ type GeneratePricingResponse struct {
   IsEligible bool `json:"is-eligible"`
   Message string `json:"message"`
   PricingList []PricingItem `json:"pricing"`
+  Vehicle *Vehicle `json:"vehicle,omitempty"` // the vehicle resolved from the registration or code, if any
+  Promo *Promo `json:"promo,omitempty"` // the outcome of the promo code of the request, if any
+}
+
+// Promo - tells whether the promo code of the request was applied, and why not when it was rejected
+// A rejected code does not decline the quote, the prices are given without the discount
+type Promo struct {
+  Code string `json:"code"`
+  Applied bool `json:"applied"`
+  Reason string `json:"reason,omitempty"`
+}
+
+// Vehicle - the attributes of the vehicle resolved from the vehicle table, its InsuranceGroup
+// is the one the request is priced with
+type Vehicle struct {
+  Registration string `json:"registration,omitempty"`
+  Code string `json:"code,omitempty"`
+  Make string `json:"make,omitempty"`
+  Model string `json:"model,omitempty"`
+  InsuranceGroup int `json:"insurance_group"`
+  Value float64 `json:"value"`
+  Age int `json:"age"`
 }
 
 // PricingItem - contains the pricing data generated for partucular group based on the request passed
@@ -24,4 +82,84 @@ type PricingItem struct {
 	Premium float64 `json:"premium"`
   Currency string  `json:"currency"`
   FareGroup string `json:"fare_group"`
+  Breakdown []PricingStep `json:"breakdown,omitempty"` // every step of the computation, in order
+}
+
+// PricingStep - a step of the computation of a PricingItem, the base rate or a factor applied to it
+// Premium is the premium once the step is applied
+type PricingStep struct {
+  Label string `json:"label"`
+  Factor float64 `json:"factor,omitempty"`
+  Operation string `json:"operation,omitempty"` // how the factor was applied, multiplied when empty
+  Premium float64 `json:"premium"`
+}
+
+// ConfigUploadRequest - is used by the admin endpoint that replaces a whole config set
+// Files maps the factor document name (e.g. base-rate.json) to its new content
+// any document left out is carried over from the active version
+type ConfigUploadRequest struct {
+  Comment string `json:"comment"`
+  Files map[string]json.RawMessage `json:"files"`
+}
+
+// RepricingJob - describes an asynchronous repricing job and its progress
+// Processed counts every request handled so far, split into Priced (eligible),
+// Declined (not eligible) and Failed (could not be priced) requests
+// Owner is the client ID of the caller that submitted the job, only that client can see it
+type RepricingJob struct {
+  ID string `json:"id"`
+  Owner string `json:"owner,omitempty"`
+  Status string `json:"status"`
+  Total int `json:"total"`
+  Processed int `json:"processed"`
+  Priced int `json:"priced"`
+  Declined int `json:"declined"`
+  Failed int `json:"failed"`
+  Error string `json:"error,omitempty"`
+  CreatedAt string `json:"created_at"`
+  UpdatedAt string `json:"updated_at"`
+}
+
+// RepricingJobRequest - is used to submit a list of requests as an asynchronous repricing job
+type RepricingJobRequest struct {
+  Requests []GeneratePricingRequest `json:"requests"`
+}
+
+// PromoRedemption - is used to redeem a promo code against the quote it was applied to, Request is the
+// request of the quote and Duration the label of the base rate whose price was bound
+type PromoRedemption struct {
+  Request GeneratePricingRequest `json:"request"`
+  Duration string `json:"duration"`
+}
+
+// CustomerHistory - the policies bound by a returning customer and the claims they made,
+// as recorded in the customer history store
+type CustomerHistory struct {
+  CustomerID string `json:"customer_id"`
+  Policies []CustomerPolicy `json:"policies"`
+  Claims []Claim `json:"claims"`
+}
+
+// CustomerPolicy - a policy bound by a customer, BoundOn is the date it was bound on
+type CustomerPolicy struct {
+  BoundOn string `json:"bound_on"`
+  Reference string `json:"reference,omitempty"`
+  Premium float64 `json:"premium,omitempty"`
+}
+
+// AuthError - is the body of the 401 and 403 responses of the authenticated endpoints
+// Code is the RFC 6750 error code (invalid_request, invalid_token or insufficient_scope)
+// RequiredScope is only set on 403 responses, it names the scope the caller is missing
+type AuthError struct {
+  Code string `json:"error"`
+  Message string `json:"message"`
+  RequiredScope string `json:"required_scope,omitempty"`
+}
+
+// ErrorResponse - is the body of the 500 responses answered when a request failed unexpectedly
+// RequestID is the X-Request-Id of the request, to find it in the logs
+type ErrorResponse struct {
+  Code string `json:"error"`
+  Message string `json:"message"`
+  RequestID string `json:"request_id,omitempty"`
 }
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"pricingengine"
+	"pricingengine/service/config"
+	"pricingengine/service/logging"
+	"pricingengine/service/model"
+)
+
+// ListConfigVersions returns the version history of the factor documents
+// the currently live version is flagged as active
+func (a *App) ListConfigVersions(ctx context.Context) ([]models.ConfigVersion, error) {
+	a.initialiseCache(ctx)
+	return a.Cache.ListVersions()
+}
+
+// UploadConfigSet validates a replacement for one or more factor documents,
+// stores them as a new numbered version and activates it atomically
+// returns the metadata of the created version or a *config.ValidationError
+func (a *App) UploadConfigSet(ctx context.Context, request *pricingengine.ConfigUploadRequest) (*models.ConfigVersion, error) {
+	logging.FromContext(ctx).Debug("Entering UploadConfigSet")
+	a.initialiseCache(ctx)
+	if request == nil || len(request.Files) == 0 {
+		return nil, &config.ValidationError{File: "request", Reason: "no factor files uploaded"}
+	}
+	files := make(map[string][]byte)
+	for name, content := range request.Files {
+		files[FactorFileName(name)] = content
+	}
+	version, err := a.Cache.CreateVersion(ctx, files, request.Comment)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("Uploaded config set", "version", version.Version)
+	return version, nil
+}
+
+// UploadFactorTable replaces a single factor document, leaving every other table as it is
+// in the active version, and activates the result as a new numbered version
+func (a *App) UploadFactorTable(ctx context.Context, name string, content []byte, comment string) (*models.ConfigVersion, error) {
+	return a.UploadConfigSet(ctx, &pricingengine.ConfigUploadRequest{
+		Comment: comment,
+		Files:   map[string]json.RawMessage{FactorFileName(name): content},
+	})
+}
+
+// ActivateConfigVersion swaps the live config to any stored version, which is how a
+// rollback is performed, the previous version stays live if the stored one fails validation
+func (a *App) ActivateConfigVersion(ctx context.Context, version int) (*models.ConfigVersion, error) {
+	logging.FromContext(ctx).Debug("Entering ActivateConfigVersion", "version", version)
+	a.initialiseCache(ctx)
+	if err := a.Cache.ActivateVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	versions, err := a.Cache.ListVersions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+	return nil, config.ErrVersionNotFound
+}
+
+// FactorFileName normalises a factor table name so both "base-rate" and "base-rate.json" are accepted
+func FactorFileName(name string) string {
+	if strings.HasSuffix(name, ".json") {
+		return name
+	}
+	return name + ".json"
+}
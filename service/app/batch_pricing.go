@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+
+	"pricingengine"
+	"pricingengine/service/logging"
+)
+
+// GenerateBatchPricing prices every request of the batch through GeneratePricing
+// The responses keep the order of the requests, a declined request does not stop the batch
+// but an error or a cancelled context does
+func (a *App) GenerateBatchPricing(ctx context.Context, requests []*pricingengine.GeneratePricingRequest) ([]*pricingengine.GeneratePricingResponse, error) {
+	logging.FromContext(ctx).Debug("Entering GenerateBatchPricing", "requests", len(requests))
+	result := make([]*pricingengine.GeneratePricingResponse, 0, len(requests))
+	for _, request := range requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		response, err := a.GeneratePricing(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, response)
+	}
+	logging.FromContext(ctx).Debug("Leaving GenerateBatchPricing")
+	return result, nil
+}
@@ -2,17 +2,36 @@ package app
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"strings"
+	"time"
 
 	"pricingengine"
 	"pricingengine/service/strategy"
+	"pricingengine/service/tracing"
 	"pricingengine/service/config"
+	"pricingengine/service/customers"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+	"pricingengine/service/model"
+	"pricingengine/service/promo"
+	"pricingengine/service/util"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type App struct{
 	Cache config.ConfigCache
+	ConfigPath string // directory of the factor documents used when the cache was never loaded, "config" by default
+	CacheTTL int64 // seconds the factor documents are cached for, 100000 by default
+	MaxCoverStartAhead time.Duration // how far ahead the cover can start, 30 days by default
+	PromoUsage *promo.UsageStore // counts the uses of the promo codes, their max-uses are not enforced when nil
+	Customers *customers.Store // keeps the customer histories, the loyalty factor is not applied when nil
 }
 
+// coverStartGrace is how far in the past a cover start is still accepted, to absorb the clock skew of the callers
+const coverStartGrace = 5 * time.Minute
+
 
 // GeneratePricing will calculate how much a 'risk' be priced or if they should
 // be denied.
@@ -22,105 +41,471 @@ type App struct{
 // Inputs ==> ctx context.Context, request *pricingengine.GeneratePricingRequest
 // returns ==> *pricingengine.GeneratePricingResponse, error
 func (a *App) GeneratePricing(ctx context.Context, request *pricingengine.GeneratePricingRequest) (*pricingengine.GeneratePricingResponse, error) {
-	log.Println("Entering GeneratePricing")
-	// Initialise with actual path if not present
-	if a.Cache.TimeToLive == 0 {
-		a.Cache.Fetcher = config.ConfigFetcher{Path: "/config/"}
-	}
-	a.Cache.InitialiseWithRefresh(false, 100000) // time to re-Initialise and see if the cache works
+	ctx, span := tracing.Start(ctx, "app.GeneratePricing")
+	defer span.End()
+	logger := logging.FromContext(ctx)
+	logger.Debug("Entering GeneratePricing")
+	a.initialiseCache(ctx)
+	snapshot := a.Cache.Snapshot()
 
 	result := pricingengine.GeneratePricingResponse{}
+	if request == nil {
+		result.Message = "request cannot be empty"
+		return &result, nil
+	}
 	result.Input = *request
 
-	if len(request.DateOfBirth) == 0 {
-		result.Message = "DateOfBirth cannot be empty"
+	if message := a.validateRequest(ctx, request, coverStartLocation(snapshot)); len(message) > 0 {
+		result.Message = message
 		result.IsEligible = false
 		return &result, nil
 	}
 
-	if request.InsuranceGroup <= 0 {
-		result.Message = "InsuranceGroup should be a Positive number"
+	var strategies = strategy.Strategy{Context: ctx}
+	vehicle, err := strategies.FindMatchingVehicle(request, snapshot.VehicleList)
+	if(err != nil) {
+		logger.Info("rejected on vehicle", "reason", err)
+		metrics.ValidationFailures.WithLabelValues("vehicle").Inc()
+		result.Message = err.Error()
 		result.IsEligible = false
 		return &result, nil
 	}
-
-	if len(request.LicenseHeldSince) == 0 {
-		result.Message = "LicenseHeldSince Date cannot be empty"
+	if vehicle != nil {
+		// the looked up group replaces whatever the caller supplied
+		rated := *request
+		rated.InsuranceGroup = vehicle.InsuranceGroup
+		request = &rated
+		result.Vehicle = vehicle
+	} else if request.InsuranceGroup <= 0 {
+		metrics.ValidationFailures.WithLabelValues("insurance_group").Inc()
+		result.Message = "InsuranceGroup should be a Positive number"
 		result.IsEligible = false
 		return &result, nil
 	}
 
-	var strategies = strategy.Strategy{}
-	driver_factor_range, err := strategies.FindMatchingDriverAgeFactor(request, a.Cache.DriverAgeFactorList)
+	driver_factor_range, err := strategies.FindMatchingDriverAgeFactor(request, snapshot.DriverAgeFactorList)
 	if(err != nil) {
-		log.Printf("error finding driver_factor_range: %v", err)
+		logger.Info("rejected on driver_factor_range", "reason", err)
+		rejected("driver_age", "date_of_birth", driver_factor_range)
 		result.Message = err.Error()
 		result.IsEligible = false
 		return &result, nil
 	}
 
-	insurance_factor_range, err := strategies.FindMatchingInsuranceGroupFactor(request, a.Cache.InsuranceGroupFactorList)
+	insurance_factor_range, err := strategies.FindMatchingInsuranceGroupFactor(request, snapshot.InsuranceGroupFactorList)
 	if(err != nil) {
-		log.Printf("error finding insurance_factor_range: %v", err)
+		logger.Info("rejected on insurance_factor_range", "reason", err)
+		rejected("insurance_group", "insurance_group", insurance_factor_range)
 		result.Message = err.Error()
 		result.IsEligible = false
 		return &result, nil
 	}
 
-	licence_factor_range, err := strategies.FindMatchingLicenceValidityFactor(request, a.Cache.LicenceValidityFactorList)
+	licence_factor_range, err := strategies.FindMatchingLicenceValidityFactor(request, snapshot.LicenceValidityFactorList)
 	if(err != nil) {
-		log.Printf("error finding licence_factor_range: %v", err)
+		logger.Info("rejected on licence_factor_range", "reason", err)
+		rejected("licence_validity", "license_held_since", licence_factor_range)
 		result.Message = err.Error()
 		result.IsEligible = false
 		return &result, nil
 	}
 
-	var thirdStrategy = func( resp *pricingengine.PricingItem) (*pricingengine.PricingItem, error) {
-		log.Println("Processing LicenceValidityFactor Stragegy")
-		return strategies.ApplySubsecuentFactorsToPricing(request, resp, licence_factor_range, nil) // final call so no next strategy
+	drivers := []strategy.DriverBands{{Driver: strategy.MainDriver, Age: driver_factor_range, Licence: licence_factor_range}}
+	for i, additional := range request.AdditionalDrivers {
+		name := strategy.DriverName(i, additional)
+		rated := *request
+		rated.DateOfBirth, rated.LicenseHeldSince = additional.DateOfBirth, additional.LicenseHeldSince
+		age_range, err := strategies.FindMatchingDriverAgeFactor(&rated, snapshot.DriverAgeFactorList)
+		if(err != nil) {
+			logger.Info("rejected on driver_factor_range", "driver", name, "reason", err)
+			rejected("driver_age", "additional_drivers", age_range)
+			result.Message = name+": "+err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		licence_range, err := strategies.FindMatchingLicenceValidityFactor(&rated, snapshot.LicenceValidityFactorList)
+		if(err != nil) {
+			logger.Info("rejected on licence_factor_range", "driver", name, "reason", err)
+			rejected("licence_validity", "additional_drivers", licence_range)
+			result.Message = name+": "+err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		drivers = append(drivers, strategy.DriverBands{Driver: name, Age: age_range, Licence: licence_range})
 	}
-
-	var secondStrategy = func( resp *pricingengine.PricingItem) (*pricingengine.PricingItem, error) {
-		log.Println("Got Previous Strategy in InsuranceGroupFactor Stragegy data here::", resp)
-		return strategies.ApplySubsecuentFactorsToPricing(request, resp, insurance_factor_range, thirdStrategy)
+	combination := models.DriverCombination{Rule: strategy.DriverRuleHighestRisk}
+	if snapshot.DriverCombination != nil {
+		combination = *snapshot.DriverCombination
 	}
 
-	var firstStrategy = func( resp *pricingengine.PricingItem) (*pricingengine.PricingItem, error) {
-		log.Println("Got Previous Strategy in DriverAgeFactor Stragegy data here::", resp)
-		return strategies.ApplySubsecuentFactorsToPricing(request, resp, driver_factor_range, secondStrategy)
+	// the driver bands are combined for every base rate, the riskiest driver being the one raising its premium the most
+	factors := []*models.RangeConfig{}
+	postcode_factor_range, err := strategies.FindMatchingPostcodeFactor(request, snapshot.PostcodeFactorList)
+	if(err != nil) {
+		logger.Info("rejected on postcode_factor_range", "reason", err)
+		rejected("postcode", "postcode", postcode_factor_range)
+		result.Message = err.Error()
+		result.IsEligible = false
+		return &result, nil
+	}
+	if postcode_factor_range != nil {
+		factors = append(factors, postcode_factor_range)
+	}
+	for _, table := range snapshot.CategoricalFactorList {
+		categorical_range, err := strategies.FindMatchingCategoricalFactor(request, table)
+		if(err != nil) {
+			logger.Info("rejected on categorical_range", "categorical", table.Name, "reason", err)
+			rejected("categorical", table.Attribute, categorical_range)
+			result.Message = err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		if categorical_range != nil {
+			factors = append(factors, categorical_range)
+		}
+	}
+	for _, table := range snapshot.InteractionFactorList {
+		interaction_range, err := strategies.FindMatchingInteractionFactor(request, table)
+		if(err != nil) {
+			logger.Info("rejected on interaction_range", "interaction", table.Name, "reason", err)
+			rejected("interaction", "interaction", interaction_range)
+			result.Message = err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		if interaction_range != nil {
+			factors = append(factors, interaction_range)
+		}
+	}
+	if snapshot.HistoryLoadings != nil {
+		claim_loadings, err := strategies.FindMatchingClaimLoadings(request, snapshot.HistoryLoadings.Claims)
+		if(err != nil) {
+			logger.Info("rejected on claim_loadings", "reason", err)
+			rejected("claims", "claims", firstLoading(claim_loadings))
+			result.Message = err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		conviction_loadings, err := strategies.FindMatchingConvictionLoadings(request, snapshot.HistoryLoadings.Convictions)
+		if(err != nil) {
+			logger.Info("rejected on conviction_loadings", "reason", err)
+			rejected("convictions", "convictions", firstLoading(conviction_loadings))
+			result.Message = err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		factors = append(append(factors, claim_loadings...), conviction_loadings...)
+	}
+	if snapshot.TemporalFactors != nil {
+		temporal_factors, err := strategies.FindMatchingTemporalFactors(request, *snapshot.TemporalFactors, snapshot.HolidayList)
+		if(err != nil) {
+			logger.Info("rejected on temporal_factors", "reason", err)
+			rejected("cover_start", "cover_start", firstLoading(temporal_factors))
+			result.Message = err.Error()
+			result.IsEligible = false
+			return &result, nil
+		}
+		factors = append(factors, temporal_factors...)
+	}
+	if snapshot.LoyaltyFactor != nil && a.Customers != nil && len(request.CustomerID) > 0 {
+		history, err := a.Customers.Load(request.CustomerID)
+		if(err != nil) {
+			logger.Error("error loading the customer history", "customer_id", request.CustomerID, "error", err)
+			return &result, err
+		}
+		loyalty_range, err := strategies.FindMatchingLoyaltyFactor(request, history, *snapshot.LoyaltyFactor)
+		if(err != nil) {
+			logger.Error("error applying the loyalty factor", "customer_id", request.CustomerID, "error", err)
+			return &result, err
+		}
+		if loyalty_range != nil {
+			factors = append(factors, loyalty_range)
+		}
 	}
-
 	price_items := []pricingengine.PricingItem{}
-	for i:= 0; i < len(a.Cache.BaseRateList); i++ {
-			item, err := strategies.ApplyBasePricing(request, &a.Cache.BaseRateList[i], firstStrategy)
+	for i:= 0; i < len(snapshot.BaseRateList); i++ {
+			_, chain := tracing.Start(ctx, "app.PricingChain", attribute.String("pricing.base_rate", snapshot.BaseRateList[i].Label))
+			driver_factor_range, licence_factor_range, driver_loadings := strategies.CombineDrivers(drivers, combination, snapshot.BaseRateList[i].Value)
+			rated_factors := append(append([]*models.RangeConfig{driver_factor_range, insurance_factor_range, licence_factor_range}, driver_loadings...), factors...)
+			item, err := strategies.ApplyBasePricing(request, &snapshot.BaseRateList[i], strategies.ChainFactors(request, rated_factors))
 			if(err != nil) {
-				log.Printf("error finding ApplyBasePricing: %v", err)
+				logger.Error("error applying the base pricing", "error", err)
+				tracing.End(chain, err)
 				return &result, err
 			}
+			chain.SetAttributes(attribute.Float64("pricing.premium", item.Premium))
+			chain.End()
 			price_items = append(price_items, *item)
 	}
+	if len(util.NormalisePromoCode(request.PromoCode)) > 0 {
+		result.Promo, price_items = a.applyPromo(ctx, &strategies, request, snapshot, price_items)
+	}
 	result.Message = "Success"
 	result.IsEligible = true
 	result.PricingList = price_items
-	log.Println("Leaving GeneratePricing")
+	metrics.QuotesPriced.Inc()
+	span.SetAttributes(attribute.Bool("pricing.eligible", true), attribute.Int("pricing.prices", len(price_items)))
+	logger.Debug("Leaving GeneratePricing", "prices", len(price_items))
 	return &result, nil
 }
 
+// applyPromo applies the promo code of the request as the last step of every price it applies to, as long
+// as it has uses left, the use itself is only recorded by RedeemPromoCode when the quote is bound
+// returns the outcome of the code along with the prices, left unchanged when the code is rejected
+func (a *App) applyPromo(ctx context.Context, strategies *strategy.Strategy, request *pricingengine.GeneratePricingRequest, snapshot config.ConfigSnapshot, items []pricingengine.PricingItem) (*pricingengine.Promo, []pricingengine.PricingItem) {
+	logger := logging.FromContext(ctx)
+	outcome := &pricingengine.Promo{Code: util.NormalisePromoCode(request.PromoCode)}
+	rejectedPromo := func(reason string) (*pricingengine.Promo, []pricingengine.PricingItem) {
+		logger.Info("rejected promo code", "promo_code", outcome.Code, "reason", reason)
+		metrics.PromoCodes.WithLabelValues("rejected").Inc()
+		outcome.Reason = reason
+		return outcome, items
+	}
+	matching, err := strategies.FindMatchingPromoCode(request, snapshot.PromoCodeList)
+	if err != nil {
+		return rejectedPromo(err.Error())
+	}
+	discounted, applied := []pricingengine.PricingItem{}, 0
+	var reason error
+	for i := range items {
+		band, err := strategies.FindMatchingPromoDiscount(*matching, &snapshot.BaseRateList[i], items[i].Premium)
+		if err != nil {
+			if reason == nil {
+				reason = err
+			}
+			discounted = append(discounted, items[i])
+			continue
+		}
+		item, err := strategies.ApplySubsecuentFactorsToPricing(request, &items[i], band, nil)
+		if err != nil {
+			return rejectedPromo(err.Error())
+		}
+		discounted = append(discounted, *item)
+		applied++
+	}
+	if applied == 0 {
+		if reason == nil {
+			reason = fmt.Errorf("Promo code %s does not apply to any price", outcome.Code)
+		}
+		return rejectedPromo(reason.Error())
+	}
+	if a.PromoUsage != nil && matching.MaxUses > 0 {
+		uses, err := a.PromoUsage.Uses(matching.Code)
+		if err != nil {
+			logger.Error("error reading the promo code uses", "promo_code", outcome.Code, "error", err)
+			return rejectedPromo("Promo code "+outcome.Code+" could not be checked")
+		}
+		if uses >= matching.MaxUses {
+			return rejectedPromo("Promo code "+outcome.Code+" has reached its usage limit")
+		}
+	}
+	metrics.PromoCodes.WithLabelValues("applied").Inc()
+	outcome.Applied = true
+	return outcome, discounted
+}
+
+// validateRequest checks the mandatory fields of the request, a cover start without offset being
+// taken as a local time of the location
+// returns the reason the request is invalid, empty when it is valid
+func (a *App) validateRequest(ctx context.Context, request *pricingengine.GeneratePricingRequest, location *time.Location) string {
+	_, span := tracing.Start(ctx, "app.ValidateRequest")
+	defer span.End()
+	field, message := "", ""
+	switch {
+	case len(request.DateOfBirth) == 0:
+		field, message = "date_of_birth", "DateOfBirth cannot be empty"
+	case request.InsuranceGroup <= 0 && len(request.VehicleRegistration) == 0 && len(request.VehicleCode) == 0:
+		field, message = "insurance_group", "InsuranceGroup should be a Positive number"
+	case len(request.LicenseHeldSince) == 0:
+		field, message = "license_held_since", "LicenseHeldSince Date cannot be empty"
+	case len(request.Postcode) > 0 && !validPostcode(request.Postcode):
+		field, message = "postcode", "Postcode should be a valid UK postcode"
+	case len(request.CustomerID) > 0 && !customers.ValidID(request.CustomerID):
+		field, message = "customer_id", "CustomerID should be 1 to 64 letters, digits, '-' or '_'"
+	default:
+		field, message = validateDrivers(request)
+		if len(field) == 0 {
+			field, message = validateHistory(request)
+		}
+		if len(field) == 0 {
+			field, message = a.validateCoverStart(request, location)
+		}
+	}
+	if len(field) > 0 {
+		metrics.ValidationFailures.WithLabelValues(field).Inc()
+		span.SetAttributes(attribute.String("pricing.invalid_field", field))
+	}
+	return message
+}
+
+// validateDrivers checks every additional driver of the request has a date of birth and a licence date
+// returns the invalid field and the reason, naming the driver, empty when they are valid
+func validateDrivers(request *pricingengine.GeneratePricingRequest) (string, string) {
+	for i, driver := range request.AdditionalDrivers {
+		switch {
+		case len(driver.DateOfBirth) == 0:
+			return "additional_drivers", strategy.DriverName(i, driver)+" DateOfBirth cannot be empty"
+		case len(driver.LicenseHeldSince) == 0:
+			return "additional_drivers", strategy.DriverName(i, driver)+" LicenseHeldSince Date cannot be empty"
+		}
+	}
+	return "", ""
+}
+
+// validateHistory checks the claims and convictions of the request are dated, typed and not in the future
+// returns the invalid field and the reason, empty when they are valid
+func validateHistory(request *pricingengine.GeneratePricingRequest) (string, string) {
+	now := time.Now()
+	for i, claim := range request.Claims {
+		date, err := time.Parse("2006-01-02", claim.Date)
+		switch {
+		case err != nil:
+			return "claims", fmt.Sprintf("Claim %d date should be a date as YYYY-MM-DD", i+1)
+		case date.After(now):
+			return "claims", fmt.Sprintf("Claim %d date cannot be in the future", i+1)
+		case len(strings.TrimSpace(claim.Type)) == 0:
+			return "claims", fmt.Sprintf("Claim %d type cannot be empty", i+1)
+		}
+	}
+	for i, conviction := range request.Convictions {
+		date, err := time.Parse("2006-01-02", conviction.Date)
+		switch {
+		case err != nil:
+			return "convictions", fmt.Sprintf("Conviction %d date should be a date as YYYY-MM-DD", i+1)
+		case date.After(now):
+			return "convictions", fmt.Sprintf("Conviction %d date cannot be in the future", i+1)
+		case len(strings.TrimSpace(conviction.Code)) == 0:
+			return "convictions", fmt.Sprintf("Conviction %d code cannot be empty", i+1)
+		case conviction.Points < 0:
+			return "convictions", fmt.Sprintf("Conviction %d points cannot be negative", i+1)
+		}
+	}
+	return "", ""
+}
+
+// validateCoverStart checks the optional cover start is a timestamp that is neither in the past
+// nor further ahead than MaxCoverStartAhead
+// returns the invalid field and the reason, empty when it is valid
+func (a *App) validateCoverStart(request *pricingengine.GeneratePricingRequest, location *time.Location) (string, string) {
+	if len(request.CoverStart) == 0 {
+		return "", ""
+	}
+	start, err := strategy.ParseCoverStart(request.CoverStart, location)
+	if err != nil {
+		return "cover_start", "CoverStart should be a timestamp as YYYY-MM-DDTHH:MM:SS with an optional offset"
+	}
+	maxAhead := a.MaxCoverStartAhead
+	if maxAhead <= 0 {
+		maxAhead = 30 * 24 * time.Hour
+	}
+	now := time.Now()
+	switch {
+	case start.Before(now.Add(-coverStartGrace)):
+		return "cover_start", "CoverStart cannot be in the past"
+	case start.After(now.Add(maxAhead)):
+		return "cover_start", fmt.Sprintf("CoverStart cannot be more than %g days ahead", maxAhead.Hours()/24)
+	}
+	return "", ""
+}
+
+// coverStartLocation returns the timezone of the temporal factors, UTC when there are none
+func coverStartLocation(snapshot config.ConfigSnapshot) *time.Location {
+	if snapshot.TemporalFactors == nil {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(snapshot.TemporalFactors.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// firstLoading returns the declining loading of a loadings lookup, nil when it failed for another reason
+func firstLoading(loadings []*models.RangeConfig) *models.RangeConfig {
+	if len(loadings) == 0 {
+		return nil
+	}
+	return loadings[0]
+}
+
+// validPostcode tells whether the postcode follows the UK postcode syntax, whatever its case and spacing
+func validPostcode(postcode string) bool {
+	_, ok := util.NormalisePostcode(postcode)
+	return ok
+}
+
+// rejected records why GeneratePricing answered without a price, a matched band that is not
+// eligible is a decline by that factor, anything else is a validation failure of the field
+func rejected(factor string, field string, band *models.RangeConfig) {
+	if band != nil {
+		metrics.QuotesDeclined.WithLabelValues(factor, band.Label).Inc()
+		return
+	}
+	metrics.ValidationFailures.WithLabelValues(field).Inc()
+}
+
 // GeneratePricingConfig fetch and cache the configs related to pricing computations
 // Just forms a map[]{} based on the config in the cache
 func (a *App) GeneratePricingConfig(ctx context.Context) (interface{}, error) {
-	log.Println("Entering GeneratePricingConfig")
-	// Initialise with actual path if not present
-	if a.Cache.TimeToLive == 0 {
-		a.Cache.Fetcher = config.ConfigFetcher{Path: "/config/"}
-	}
-	a.Cache.InitialiseWithRefresh(false, 100000) // time to live 100000s
+	logging.FromContext(ctx).Debug("Entering GeneratePricingConfig")
+	snapshot := a.PricingConfigSnapshot(ctx)
 	var result map[string]interface{} = make(map[string]interface{})
 
-	result["base-rate"] = a.Cache.BaseRateList
-	result["driver-age-factor"] = a.Cache.DriverAgeFactorList
-	result["insurance-group-factor"] = a.Cache.InsuranceGroupFactorList
-	result["licence-validity-factor"] = a.Cache.LicenceValidityFactorList
-	log.Println("Leaving GeneratePricingConfig")
+	result["version"] = snapshot.Version
+	result["base-rate"] = snapshot.BaseRateList
+	result["driver-age-factor"] = snapshot.DriverAgeFactorList
+	result["insurance-group-factor"] = snapshot.InsuranceGroupFactorList
+	result["licence-validity-factor"] = snapshot.LicenceValidityFactorList
+	result["interaction-factors"] = snapshot.InteractionFactorList
+	result["categorical-factors"] = snapshot.CategoricalFactorList
+	result["postcode-factor"] = snapshot.PostcodeFactorList
+	result["history-loadings"] = snapshot.HistoryLoadings
+	result["temporal-factors"] = snapshot.TemporalFactors
+	result["holidays"] = snapshot.HolidayList
+	result["promo-codes"] = snapshot.PromoCodeList
+	result["loyalty-factor"] = snapshot.LoyaltyFactor
+	result["driver-combination"] = snapshot.DriverCombination
+	logging.FromContext(ctx).Debug("Leaving GeneratePricingConfig")
 	return result, nil
 }
+
+// PricingConfigSnapshot returns the factor ranges the computations are currently based on
+// as one consistent view, reloading the cache first if its TTL has expired
+func (a *App) PricingConfigSnapshot(ctx context.Context) config.ConfigSnapshot {
+	a.initialiseCache(ctx)
+	return a.Cache.Snapshot()
+}
+
+// ConfigStatus returns the load state of the factor documents, loading them first
+// if they were never loaded or their TTL has expired
+func (a *App) ConfigStatus(ctx context.Context) models.ConfigStatus {
+	a.initialiseCache(ctx)
+	return a.Cache.Status()
+}
+
+// LoadedConfigStatus returns the load state of the factor documents as they currently are,
+// without loading or reloading them, for the probes to read
+func (a *App) LoadedConfigStatus() models.ConfigStatus {
+	return a.Cache.Status()
+}
+
+// initialiseCache points the cache to the actual config path if it was never loaded
+// and reloads it when the TTL has expired
+func (a *App) initialiseCache(ctx context.Context) {
+	// Initialise with actual path if not present
+	if a.Cache.TimeToLive == 0 {
+		path := a.ConfigPath
+		if len(path) == 0 {
+			path = "config"
+		}
+		a.Cache.Fetcher = config.ConfigFetcher{Path: path}
+	}
+	ttl := a.CacheTTL
+	if ttl <= 0 {
+		ttl = 100000 // time to live 100000s
+	}
+	err := a.Cache.InitialiseWithRefreshContext(ctx, false, ttl)
+	if err != nil {
+		logging.FromContext(ctx).Error("error initialising the config cache", "error", err)
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"pricingengine"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+	"pricingengine/service/strategy"
+	"pricingengine/service/util"
+)
+
+// ErrPromoCodeNotFound is wrapped by the PromoError of a code missing from the promo codes
+var ErrPromoCodeNotFound = errors.New("promo code not found")
+
+// ErrPromoCodeUsedUp is wrapped by the PromoError of a code that has reached its max uses
+var ErrPromoCodeUsedUp = errors.New("promo code used up")
+
+// PromoError is returned when a promo code can not be redeemed, Reason tells why as in the promo of a quote
+// Err is ErrPromoCodeNotFound or ErrPromoCodeUsedUp when that is why, nil when the code does not apply to the quote
+type PromoError struct {
+	Code   string
+	Reason string
+	Err    error
+}
+
+func (e *PromoError) Error() string {
+	return e.Reason
+}
+
+func (e *PromoError) Unwrap() error {
+	return e.Err
+}
+
+// RedeemPromoCode records a use of the promo code once the quote it was applied to is bound,
+// quoting only checks the uses left so that a customer can quote again with the same code
+// The request of the quote is priced again without the code, and the code has to be valid today and
+// apply to the price of the bound Duration, as it did when quoting, before its use is counted
+// returns the redeemed promo, or a *PromoError when the code is not known, does not apply to the
+// quote or has reached its usage limit
+func (a *App) RedeemPromoCode(ctx context.Context, code string, redemption *pricingengine.PromoRedemption) (*pricingengine.Promo, error) {
+	logger := logging.FromContext(ctx)
+	a.initialiseCache(ctx)
+	snapshot := a.Cache.Snapshot()
+	outcome := &pricingengine.Promo{Code: util.NormalisePromoCode(code)}
+	known := false
+	for _, promo := range snapshot.PromoCodeList {
+		known = known || promo.Code == outcome.Code
+	}
+	if !known {
+		return nil, &PromoError{Code: outcome.Code, Reason: "Promo code " + outcome.Code + " is not known", Err: ErrPromoCodeNotFound}
+	}
+
+	strategies := strategy.Strategy{Context: ctx}
+	matching, err := strategies.FindMatchingPromoCode(&pricingengine.GeneratePricingRequest{PromoCode: code}, snapshot.PromoCodeList)
+	if err != nil {
+		return nil, &PromoError{Code: outcome.Code, Reason: err.Error()}
+	}
+	request := redemption.Request
+	request.PromoCode = ""
+	quote, err := a.GeneratePricing(ctx, &request)
+	if err != nil {
+		return nil, err
+	}
+	if !quote.IsEligible {
+		return nil, &PromoError{Code: outcome.Code, Reason: "Quote can not be priced: " + quote.Message}
+	}
+	bound := -1
+	for i := 0; i < len(snapshot.BaseRateList) && i < len(quote.PricingList) && bound < 0; i++ {
+		if util.NormaliseCategory(snapshot.BaseRateList[i].Label) == util.NormaliseCategory(redemption.Duration) {
+			bound = i
+		}
+	}
+	if bound < 0 {
+		return nil, &PromoError{Code: outcome.Code, Reason: "Quote has no price for " + redemption.Duration}
+	}
+	if _, err := strategies.FindMatchingPromoDiscount(*matching, &snapshot.BaseRateList[bound], quote.PricingList[bound].Premium); err != nil {
+		return nil, &PromoError{Code: outcome.Code, Reason: err.Error()}
+	}
+
+	if a.PromoUsage != nil {
+		redeemed, err := a.PromoUsage.Redeem(matching.Code, matching.MaxUses)
+		if err != nil {
+			logger.Error("error recording the promo code use", "promo_code", outcome.Code, "error", err)
+			return nil, err
+		}
+		if !redeemed {
+			return nil, &PromoError{Code: outcome.Code, Reason: "Promo code " + outcome.Code + " has reached its usage limit", Err: ErrPromoCodeUsedUp}
+		}
+	}
+	metrics.PromoCodes.WithLabelValues("redeemed").Inc()
+	logger.Info("Redeemed promo code", "promo_code", outcome.Code, "duration", redemption.Duration)
+	outcome.Applied = true
+	return outcome, nil
+}
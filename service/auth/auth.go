@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// The scopes a client can be granted, every endpoint but the probes, metrics and
+// the OpenAPI document requires one of them
+const (
+	ScopeQuote       = "pricing:quote"     // price single requests, streams, batches and repricing jobs
+	ScopeConfigRead  = "config:read"       // read the factor ranges and the config status
+	ScopeConfigAdmin = "config:admin"      // upload and activate factor tables
+	ScopeCustomers   = "customers:history" // read the customer histories and record their policies and claims
+)
+
+// Scopes lists every known scope
+var Scopes = []string{ScopeQuote, ScopeConfigRead, ScopeConfigAdmin, ScopeCustomers}
+
+// The authentication methods a Principal can come from
+const (
+	MethodAPIKey      = "api_key"
+	MethodJWT         = "jwt"
+	MethodCertificate = "mtls"
+)
+
+// ErrNoCredentials is returned when the request carries neither an API key nor a bearer token
+var ErrNoCredentials = errors.New("missing credentials")
+
+// jwtLeeway is the clock skew tolerated when checking the exp, nbf and iat claims of a token
+const jwtLeeway = 30 * time.Second
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	ClientID string
+	Scopes   []string
+	Method   string // MethodAPIKey, MethodJWT or MethodCertificate
+}
+
+// HasScope method reports whether the principal was granted the scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is an entry of the API key file, the key is given either in clear or as the
+// hex encoded SHA-256 digest of it so the file does not have to hold the secret itself
+type APIKey struct {
+	ClientID  string   `json:"client_id"`
+	Key       string   `json:"key,omitempty"`
+	KeySHA256 string   `json:"key_sha256,omitempty"`
+	Scopes    []string `json:"scopes"`
+}
+
+// ClientCertificate maps the identity of a verified client certificate to a client, the
+// subject is matched against the URI and DNS names of the certificate, then its common name
+type ClientCertificate struct {
+	ClientID string   `json:"client_id"`
+	Subject  string   `json:"subject"`
+	Scopes   []string `json:"scopes"`
+}
+
+// APIKeyFile is the content of the API key file
+type APIKeyFile struct {
+	Keys         []APIKey            `json:"keys"`
+	Certificates []ClientCertificate `json:"certificates"`
+}
+
+// Options locates the local files holding the credentials accepted by the Authenticator
+type Options struct {
+	APIKeysFile      string // JSON APIKeyFile
+	JWTSecretFile    string // shared secret of the HS256 tokens
+	JWTPublicKeyFile string // PEM encoded RSA public key of the RS256 tokens
+	JWTIssuer        string // expected iss claim, not checked when empty
+	JWTAudience      string // expected aud claim, not checked when empty
+}
+
+// Authenticator resolves the API keys and JWTs sent by the callers into a Principal
+type Authenticator struct {
+	keys         map[[sha256.Size]byte]*Principal
+	certificates map[string]*Principal // by subject
+	jwtSecret    []byte
+	jwtKey       *rsa.PublicKey
+	jwtParser    *jwt.Parser
+}
+
+// New method loads the API keys and the JWT keys of the options
+// returns an error when a file can not be read or holds an invalid entry
+func New(options Options) (*Authenticator, error) {
+	a := &Authenticator{keys: map[[sha256.Size]byte]*Principal{}, certificates: map[string]*Principal{}}
+	if len(options.APIKeysFile) > 0 {
+		if err := a.loadAPIKeys(options.APIKeysFile); err != nil {
+			return nil, err
+		}
+	}
+
+	methods := []string{}
+	if len(options.JWTSecretFile) > 0 {
+		secret, err := ioutil.ReadFile(options.JWTSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwtSecret = []byte(strings.TrimSpace(string(secret)))
+		if len(a.jwtSecret) < 32 {
+			return nil, errors.New("JWT secret should be at least 32 bytes long: " + options.JWTSecretFile)
+		}
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(options.JWTPublicKeyFile) > 0 {
+		pem, err := ioutil.ReadFile(options.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if a.jwtKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return nil, fmt.Errorf("invalid JWT public key %s: %v", options.JWTPublicKeyFile, err)
+		}
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	parserOptions := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired(), jwt.WithLeeway(jwtLeeway)}
+	if len(options.JWTIssuer) > 0 {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.JWTIssuer))
+	}
+	if len(options.JWTAudience) > 0 {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.JWTAudience))
+	}
+	a.jwtParser = jwt.NewParser(parserOptions...)
+	return a, nil
+}
+
+// loadAPIKeys reads the API key file, indexing every client by the digest of its key
+func (a *Authenticator) loadAPIKeys(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var file APIKeyFile
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return fmt.Errorf("invalid API key file %s: %v", filename, err)
+	}
+	for i, key := range file.Keys {
+		var digest [sha256.Size]byte
+		switch {
+		case len(key.ClientID) == 0:
+			return fmt.Errorf("invalid API key file %s: keys[%d].client_id cannot be empty", filename, i)
+		case len(key.Key) > 0 && len(key.KeySHA256) > 0:
+			return fmt.Errorf("invalid API key file %s: keys[%d] should set either key or key_sha256", filename, i)
+		case len(key.Key) > 0:
+			digest = sha256.Sum256([]byte(key.Key))
+		default:
+			decoded, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return fmt.Errorf("invalid API key file %s: keys[%d].key_sha256 should be a hex encoded SHA-256 digest", filename, i)
+			}
+			copy(digest[:], decoded)
+		}
+		if err := checkScopes(key.Scopes); err != nil {
+			return fmt.Errorf("invalid API key file %s: keys[%d].%v", filename, i, err)
+		}
+		if _, ok := a.keys[digest]; ok {
+			return fmt.Errorf("invalid API key file %s: keys[%d] is a duplicate key", filename, i)
+		}
+		a.keys[digest] = &Principal{ClientID: key.ClientID, Scopes: key.Scopes, Method: MethodAPIKey}
+	}
+	for i, certificate := range file.Certificates {
+		switch {
+		case len(certificate.ClientID) == 0:
+			return fmt.Errorf("invalid API key file %s: certificates[%d].client_id cannot be empty", filename, i)
+		case len(certificate.Subject) == 0:
+			return fmt.Errorf("invalid API key file %s: certificates[%d].subject cannot be empty", filename, i)
+		}
+		if err := checkScopes(certificate.Scopes); err != nil {
+			return fmt.Errorf("invalid API key file %s: certificates[%d].%v", filename, i, err)
+		}
+		if _, ok := a.certificates[certificate.Subject]; ok {
+			return fmt.Errorf("invalid API key file %s: certificates[%d] is a duplicate subject", filename, i)
+		}
+		a.certificates[certificate.Subject] = &Principal{ClientID: certificate.ClientID, Scopes: certificate.Scopes, Method: MethodCertificate}
+	}
+	return nil
+}
+
+// AuthenticateCertificate method resolves a client certificate, verified by the TLS handshake,
+// into the Principal of the client it is mapped to
+// returns an error when none of its names is mapped
+func (a *Authenticator) AuthenticateCertificate(certificate *x509.Certificate) (*Principal, error) {
+	names := []string{}
+	for _, uri := range certificate.URIs {
+		names = append(names, uri.String())
+	}
+	names = append(append(names, certificate.DNSNames...), certificate.Subject.CommonName)
+	for _, name := range names {
+		if principal, ok := a.certificates[name]; ok && len(name) > 0 {
+			return principal, nil
+		}
+	}
+	return nil, errors.New("client certificate " + certificate.Subject.String() + " is not mapped to a client")
+}
+
+// Authenticate method resolves the credentials of a request into its Principal
+// authorization is the value of the Authorization header and apiKey the one of the
+// X-API-Key header, a bearer token is read as a JWT when it has three segments and
+// as an API key otherwise
+// returns ErrNoCredentials when both are empty
+func (a *Authenticator) Authenticate(authorization string, apiKey string) (*Principal, error) {
+	if len(apiKey) > 0 {
+		return a.authenticateAPIKey(apiKey)
+	}
+	if len(authorization) == 0 {
+		return nil, ErrNoCredentials
+	}
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || len(strings.TrimSpace(token)) == 0 {
+		return nil, errors.New("the Authorization header should be a bearer token")
+	}
+	token = strings.TrimSpace(token)
+	if strings.Count(token, ".") == 2 {
+		return a.authenticateJWT(token)
+	}
+	return a.authenticateAPIKey(token)
+}
+
+func (a *Authenticator) authenticateAPIKey(key string) (*Principal, error) {
+	if principal, ok := a.keys[sha256.Sum256([]byte(key))]; ok {
+		return principal, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+// authenticateJWT verifies the signature and the registered claims of the token
+// The client is identified by the client_id claim, or sub when it has none, and its
+// scopes are read from the space separated scope claim or the scopes array claim
+func (a *Authenticator) authenticateJWT(token string) (*Principal, error) {
+	claims := jwtClaims{}
+	_, err := a.jwtParser.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			return a.jwtSecret, nil
+		case jwt.SigningMethodRS256.Alg():
+			return a.jwtKey, nil
+		}
+		return nil, errors.New("unexpected signing method " + token.Method.Alg())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %v", err)
+	}
+
+	principal := &Principal{ClientID: claims.ClientID, Scopes: claims.Scopes, Method: MethodJWT}
+	if len(principal.ClientID) == 0 {
+		principal.ClientID = claims.Subject
+	}
+	if len(principal.ClientID) == 0 {
+		return nil, errors.New("invalid token: the token has neither a client_id nor a sub claim")
+	}
+	if len(claims.Scope) > 0 {
+		principal.Scopes = append(principal.Scopes, strings.Fields(claims.Scope)...)
+	}
+	return principal, nil
+}
+
+// jwtClaims are the claims read from the tokens on top of the registered ones
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	ClientID string   `json:"client_id,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+func checkScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New("scopes cannot be empty")
+	}
+	for _, scope := range scopes {
+		known := false
+		for _, candidate := range Scopes {
+			known = known || candidate == scope
+		}
+		if !known {
+			return errors.New("scopes: unknown scope " + scope + ", should be one of " + strings.Join(Scopes, ", "))
+		}
+	}
+	return nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal method returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext method returns the authenticated caller of the context, nil when there is none
+func FromContext(ctx context.Context) *Principal {
+	if ctx == nil {
+		return nil
+	}
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+
+	"pricingengine"
+	"pricingengine/service/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader is the header an API key can be sent in, next to the Authorization header
+const APIKeyHeader = "X-API-Key"
+
+// The RFC 6750 error codes of the AuthError bodies
+const (
+	CodeInvalidRequest    = "invalid_request"
+	CodeInvalidToken      = "invalid_token"
+	CodeInsufficientScope = "insufficient_scope"
+)
+
+// Error is an authentication (401) or authorisation (403) failure
+type Error struct {
+	Status int
+	Body   pricingengine.AuthError
+}
+
+func (e *Error) Error() string {
+	return e.Body.Message
+}
+
+// unauthenticated returns the 401 answered to a request without valid credentials
+func unauthenticated(err error) *Error {
+	code := CodeInvalidToken
+	if err == ErrNoCredentials {
+		code = CodeInvalidRequest
+	}
+	return &Error{Status: http.StatusUnauthorized, Body: pricingengine.AuthError{Code: code, Message: err.Error()}}
+}
+
+// forbidden returns the 403 answered to a caller missing the scope
+func forbidden(principal *Principal, scope string) *Error {
+	return &Error{Status: http.StatusForbidden, Body: pricingengine.AuthError{
+		Code:          CodeInsufficientScope,
+		Message:       "client " + principal.ClientID + " is not granted the " + scope + " scope",
+		RequiredScope: scope,
+	}}
+}
+
+// Authorize method checks the principal of the context was granted the scope
+// returns an *Error to answer with when it was not, or when there is no principal
+func Authorize(ctx context.Context, scope string) error {
+	principal := FromContext(ctx)
+	if principal == nil {
+		return unauthenticated(ErrNoCredentials)
+	}
+	if !principal.HasScope(scope) {
+		return forbidden(principal, scope)
+	}
+	return nil
+}
+
+// WriteError method answers the failure as a JSON pricingengine.AuthError along with
+// the WWW-Authenticate challenge of RFC 6750
+func WriteError(w http.ResponseWriter, err *Error) {
+	challenge := `Bearer error="` + err.Body.Code + `"`
+	if len(err.Body.RequiredScope) > 0 {
+		challenge += `, scope="` + err.Body.RequiredScope + `"`
+	}
+	body, _ := json.Marshal(err.Body)
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.Status)
+	w.Write(body)
+}
+
+// Middleware authenticates the requests carrying credentials and puts their Principal in
+// the request context, the client ID is added to the logs and the span of the request
+// A client certificate verified by the TLS handshake and mapped to a client is used first,
+// the API key and bearer token are only read when there is none.
+// Requests with invalid credentials are answered with a 401, requests without any are
+// passed through anonymously and it is up to Require to reject them
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if FromContext(r.Context()) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if principal := a.verifiedCertificate(r.Context(), r.TLS); principal != nil {
+			next.ServeHTTP(w, r.WithContext(authenticated(r.Context(), principal)))
+			return
+		}
+		principal, err := a.Authenticate(r.Header.Get("Authorization"), r.Header.Get(APIKeyHeader))
+		if err == ErrNoCredentials {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).Info("Rejected credentials", "error", err)
+			WriteError(w, unauthenticated(err))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(authenticated(r.Context(), principal)))
+	})
+}
+
+// Require returns a middleware answering 401 to the anonymous requests and 403 to the
+// requests whose principal was not granted the scope
+func Require(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := Authorize(r.Context(), scope); err != nil {
+				WriteError(w, err.(*Error))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor authenticating every gRPC call with the
+// authorization or x-api-key metadata and checking it was granted the scope of its method
+// Methods missing from scopes are served without authentication
+func (a *Authenticator) UnaryServerInterceptor(scopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, ok := scopes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if p, ok := peer.FromContext(ctx); ok && FromContext(ctx) == nil {
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				if principal := a.verifiedCertificate(ctx, &info.State); principal != nil {
+					ctx = authenticated(ctx, principal)
+				}
+			}
+		}
+		if FromContext(ctx) == nil {
+			md, _ := metadata.FromIncomingContext(ctx)
+			principal, err := a.Authenticate(first(md.Get("authorization")), first(md.Get("x-api-key")))
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			ctx = authenticated(ctx, principal)
+		}
+		if err := Authorize(ctx, scope); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// verifiedCertificate returns the Principal the client certificate verified by the handshake
+// is mapped to, nil when there is no such certificate or it is not mapped to a client
+func (a *Authenticator) verifiedCertificate(ctx context.Context, state *tls.ConnectionState) *Principal {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	principal, err := a.AuthenticateCertificate(state.VerifiedChains[0][0])
+	if err != nil {
+		logging.FromContext(ctx).Debug("Ignoring the client certificate", "error", err)
+		return nil
+	}
+	return principal
+}
+
+// authenticated returns the context carrying the principal, with its client ID on the logs and span
+func authenticated(ctx context.Context, principal *Principal) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("auth.client_id", principal.ClientID), attribute.String("auth.method", principal.Method))
+	return logging.With(WithPrincipal(ctx, principal), "client_id", principal.ClientID)
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
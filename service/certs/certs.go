@@ -0,0 +1,180 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// The client certificate policies of ClientAuth
+const (
+	ClientAuthNone     = "none"     // no client certificate is asked for
+	ClientAuthOptional = "optional" // a client certificate is verified when one is sent
+	ClientAuthRequire  = "require"  // every client has to send a valid certificate
+)
+
+// ClientAuthModes lists the accepted values of Options.ClientAuth
+var ClientAuthModes = []string{ClientAuthNone, ClientAuthOptional, ClientAuthRequire}
+
+// Options locates the certificate served and the CA bundle the client certificates are verified against
+type Options struct {
+	CertFile     string // PEM certificate chain served
+	KeyFile      string // PEM private key of the certificate
+	ClientCAFile string // PEM bundle of the CAs the client certificates have to chain to
+	ClientAuth   string // one of ClientAuthModes, ClientAuthNone when empty
+}
+
+// Reloader serves the certificate and client CA bundle of its files and reloads them once they
+// change on disk, so a renewed certificate is picked up without restarting the service
+// The handshakes in flight keep the files they started with.
+type Reloader struct {
+	options Options
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// NewReloader method loads the files of the options
+// returns an error when one of them can not be read or parsed
+func NewReloader(options Options) (*Reloader, error) {
+	if len(options.ClientAuth) == 0 {
+		options.ClientAuth = ClientAuthNone
+	}
+	r := &Reloader{options: options}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload method reads the certificate, its key and the client CA bundle again
+// The ones served are left untouched when one of the files is invalid, e.g. half written
+func (r *Reloader) Reload() error {
+	modTimes := map[string]time.Time{}
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	certificate, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAs *x509.CertPool
+	if len(r.options.ClientCAFile) > 0 {
+		bundle, err := ioutil.ReadFile(r.options.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			return errors.New("no CA certificate found in " + r.options.ClientCAFile)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return nil
+}
+
+// Watch method checks the files every interval and reloads them when one changed, until the context is done
+// A failed reload is logged and retried on the next change, the previous files keep being served meanwhile
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			slog.Error("Error while reloading the TLS certificates, serving the previous ones", "error", err)
+			r.mutex.Lock()
+			r.modTimes = r.currentModTimes()
+			r.mutex.Unlock()
+			continue
+		}
+		slog.Info("Reloaded the TLS certificates", "cert_file", r.options.CertFile)
+	}
+}
+
+// Certificate method returns the leaf certificate currently served
+func (r *Reloader) Certificate() *x509.Certificate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	leaf, _ := x509.ParseCertificate(r.certificate.Certificate[0])
+	return leaf
+}
+
+// Config method returns the TLS config serving the current certificate, and asking for and
+// verifying the client certificates against the current CA bundle according to ClientAuth
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mutex.RLock()
+			defer r.mutex.RUnlock()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.certificate},
+				ClientCAs:    r.clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			switch r.options.ClientAuth {
+			case ClientAuthOptional:
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+			case ClientAuthRequire:
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return config, nil
+		},
+	}
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.options.CertFile, r.options.KeyFile}
+	if len(r.options.ClientCAFile) > 0 {
+		files = append(files, r.options.ClientCAFile)
+	}
+	return files
+}
+
+// changed reports whether one of the files was modified since it was last loaded
+func (r *Reloader) changed() bool {
+	current := r.currentModTimes()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for file, modTime := range current {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) currentModTimes() map[string]time.Time {
+	modTimes := map[string]time.Time{}
+	for _, file := range r.files() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
@@ -1,45 +1,96 @@
 package config
 import (
- "log"
+ "context"
+ "sync"
  "time"
 
+ "pricingengine/service/logging"
+ "pricingengine/service/metrics"
  "pricingengine/service/model"
- "pricingengine/service/util"
+ "pricingengine/service/tracing"
+
+ "go.opentelemetry.io/otel/attribute"
 )
 
 type ConfigCache struct{
   Fetcher ConfigFetcher
   TimeToLive int64
+  Version int // active config version, 0 being the documents directly under the Fetcher path
   BaseRateList []models.RangeConfig // all converted range list
   DriverAgeFactorList []models.RangeConfig
   InsuranceGroupFactorList []models.RangeConfig
   LicenceValidityFactorList []models.RangeConfig
+  InteractionFactorList []models.InteractionTable
+  CategoricalFactorList []models.CategoricalTable
+  PostcodeFactorList []models.PostcodeRange
+  VehicleList []models.Vehicle
+  HistoryLoadings *models.HistoryLoadings
+  TemporalFactors *models.TemporalFactors
+  HolidayList []models.Holiday
+  PromoCodeList []models.PromoCode
+  LoyaltyFactor *models.LoyaltyFactor
+  DriverCombination *models.DriverCombination
+
+  loadedAt map[string]time.Time // load time of every factor document of the active version
+  lastReloadAt time.Time
+  lastReloadErr error // error of the last reload, nil when it succeeded
+
+  mutex sync.RWMutex // guards the lists so that a reload or activation is swapped in at once
+  adminMutex sync.Mutex // serialises writes to the versioned ConfigStore
+}
+
+// ConfigSnapshot is a consistent view of all the converted factor lists of one config version
+type ConfigSnapshot struct{
+  Version int
+  BaseRateList []models.RangeConfig
+  DriverAgeFactorList []models.RangeConfig
+  InsuranceGroupFactorList []models.RangeConfig
+  LicenceValidityFactorList []models.RangeConfig
+  InteractionFactorList []models.InteractionTable // empty when the config set has no interaction tables
+  CategoricalFactorList []models.CategoricalTable // empty when the config set has no categorical tables
+  PostcodeFactorList []models.PostcodeRange // empty when the config set has no postcode factor
+  VehicleList []models.Vehicle // empty when the config set has no vehicle table
+  HistoryLoadings *models.HistoryLoadings // nil when the config set has no history loadings
+  TemporalFactors *models.TemporalFactors // nil when the config set has no temporal factors
+  HolidayList []models.Holiday // empty when the config set has no holiday calendar
+  PromoCodeList []models.PromoCode // empty when the config set has no promo codes
+  LoyaltyFactor *models.LoyaltyFactor // nil when the config set has no loyalty factor
+  DriverCombination *models.DriverCombination // nil when the config set has no driver combination, the highest-risk driver is then rated
+  LoadedAt map[string]time.Time // time each factor document was loaded at
 }
 
 // Initialise method force Initialises the cache data based on hte Fetcher config that is applied in it
-// It sequentially fetches and applies all the 5 config data
+// It loads the active version of all the config data
 // BaseFare, DriverAgeFactor, InsuranceGroupFactor, LicenceValidityFactor
+// and swaps them in together only when every document was loaded and validated
 // inputs TTL ==> number of seconds the cache should be valid
 // Returns error based on operation
 func (c *ConfigCache) Initialise(TTL int64) (error) {
-  log.Println("Initialising ConfigCache with new TTL: +v", TTL)
-  err := c.FetchAndConvertBaseFareList()
-  if err != nil {
-    return err
-  }
-  err = c.FetchAndConvertDriverAgeFactorList()
-  if err != nil {
-    return err
-  }
-  err = c.FetchAndConvertInsuranceGroupFactorList()
+  return c.InitialiseContext(context.Background(), TTL)
+}
+
+// InitialiseContext method is like Initialise, logging and tracing within the given context
+func (c *ConfigCache) InitialiseContext(ctx context.Context, TTL int64) (err error) {
+  ctx, span := tracing.Start(ctx, "config.Initialise")
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx)
+  logger.Info("Initialising ConfigCache", "ttl", TTL)
+  store := c.Store()
+  version, err := store.ActiveVersion()
   if err != nil {
+    logger.Error("error reading the active config version", "error", err)
+    c.reloaded(err)
     return err
   }
-  err = c.FetchAndConvertLicenceValidityFactorList()
+  snapshot, err := LoadConfigSnapshot(ctx, store.FetcherFor(version))
   if err != nil {
+    c.reloaded(err)
     return err
   }
-  c.TimeToLive = time.Now().Unix() + TTL // time to live in epoch seconds
+  snapshot.Version = version
+  span.SetAttributes(attribute.Int("config.version", version))
+  c.apply(snapshot, TTL)
+  c.reloaded(nil)
   return nil
 }
 
@@ -48,87 +99,502 @@ func (c *ConfigCache) Initialise(TTL int64) (error) {
 // It then applies the decision and invokes the Initialise method with TTL passed
 // Returns error based on operation
 func (c *ConfigCache) InitialiseWithRefresh(refresh_cache bool, TTL int64) (error) {
+  return c.InitialiseWithRefreshContext(context.Background(), refresh_cache, TTL)
+}
+
+// InitialiseWithRefreshContext method is like InitialiseWithRefresh, logging with the request ID of the context
+func (c *ConfigCache) InitialiseWithRefreshContext(ctx context.Context, refresh_cache bool, TTL int64) (error) {
   now := time.Now().Unix()
-  log.Println("Initialising ConfigCache with Refresh:", refresh_cache, "TimeToLive: ", c.TimeToLive, " Now: ", now)
-  if(refresh_cache || c.TimeToLive == 0 || now > c.TimeToLive) {
-    return c.Initialise(TTL) // reload all the file if it is fresh or TTL is expired
+  c.mutex.RLock()
+  timeToLive := c.TimeToLive
+  c.mutex.RUnlock()
+  logging.FromContext(ctx).Debug("Initialising ConfigCache with Refresh", "refresh", refresh_cache, "time_to_live", timeToLive, "now", now)
+  if(refresh_cache || timeToLive == 0 || now > timeToLive) {
+    return c.InitialiseContext(ctx, TTL) // reload all the file if it is fresh or TTL is expired
   }
   return nil
 }
 
-// FetchAndConvertBaseFareList method fetches the BaseFare config, converts to RangeConfig and sets it to the cache
-// All operations are internal and selfcontained withing the cache config instance
-// returns error if any caused during fetching or conversion
-func (c *ConfigCache) FetchAndConvertBaseFareList() (error) {
-  log.Println("In FetchAndConvertBaseFareList ")
-  var temp []models.BaseRate
-	res, err := c.Fetcher.ReadFileAndGetAsObject("base-rate.json" , temp)
-	if err != nil {
-		log.Printf("error reading the config file:", err)
-		return err
-	}
-	log.Printf("List : %+v", res)
-	temp = res.([]models.BaseRate)
-  factorMapper := util.FactorMapper{}
-  c.BaseRateList = factorMapper.BaseRateToRangeConfig(temp)
-  log.Println("Mapped range config from file: +v", c.BaseRateList)
-  return nil
+// Snapshot method returns the currently cached factor lists as one consistent view
+func (c *ConfigCache) Snapshot() ConfigSnapshot {
+  c.mutex.RLock()
+  defer c.mutex.RUnlock()
+  return ConfigSnapshot{
+    Version: c.Version,
+    BaseRateList: c.BaseRateList,
+    DriverAgeFactorList: c.DriverAgeFactorList,
+    InsuranceGroupFactorList: c.InsuranceGroupFactorList,
+    LicenceValidityFactorList: c.LicenceValidityFactorList,
+    InteractionFactorList: c.InteractionFactorList,
+    CategoricalFactorList: c.CategoricalFactorList,
+    PostcodeFactorList: c.PostcodeFactorList,
+    VehicleList: c.VehicleList,
+    HistoryLoadings: c.HistoryLoadings,
+    TemporalFactors: c.TemporalFactors,
+    HolidayList: c.HolidayList,
+    PromoCodeList: c.PromoCodeList,
+    LoyaltyFactor: c.LoyaltyFactor,
+    DriverCombination: c.DriverCombination,
+  }
 }
 
-// FetchAndConvertDriverAgeFactorList method fetches the DriverAgeFactor config, converts to RangeConfig and sets it to the cache
-// All operations are internal and selfcontained withing the cache config instance
-// returns error if any caused during fetching or conversion
-func (c *ConfigCache) FetchAndConvertDriverAgeFactorList() (error) {
-  log.Println("In FetchAndConvertDriverAgeFactorList ")
-  var temp []models.DriverAgeFactor
-	res, err := c.Fetcher.ReadFileAndGetAsObject("driver-age-factor.json" , temp)
-	if err != nil {
-		log.Println("error reading the config file:", err)
-		return err
-	}
-	log.Printf("List : %+v", res)
-	temp = res.([]models.DriverAgeFactor)
-  factorMapper := util.FactorMapper{}
-  c.DriverAgeFactorList = factorMapper.DriverAgeFactorToRangeConfig(temp)
-  log.Println("Mapped range config from file: +v", c.DriverAgeFactorList)
-  return nil
+// Status method reports whether the factor documents were loaded, along with the active
+// version, the outcome of the last reload and the load time of every document
+func (c *ConfigCache) Status() models.ConfigStatus {
+  c.mutex.RLock()
+  defer c.mutex.RUnlock()
+  status := models.ConfigStatus{
+    Ready: c.loadedAt != nil,
+    Version: c.Version,
+  }
+  if !c.lastReloadAt.IsZero() {
+    status.LastReloadAt = c.lastReloadAt.UTC().Format(time.RFC3339)
+  }
+  if c.lastReloadErr != nil {
+    status.LastReloadError = c.lastReloadErr.Error()
+  }
+  var lastLoadedAt time.Time
+  for _, file := range AllFactorFiles() {
+    loadedAt, ok := c.loadedAt[file]
+    if !ok {
+      continue
+    }
+    if loadedAt.After(lastLoadedAt) {
+      lastLoadedAt = loadedAt
+    }
+    status.Files = append(status.Files, models.ConfigFileStatus{File: file, LoadedAt: loadedAt.UTC().Format(time.RFC3339)})
+  }
+  if !lastLoadedAt.IsZero() {
+    status.LoadedAt = lastLoadedAt.UTC().Format(time.RFC3339)
+  }
+  return status
 }
 
-// FetchAndConvertInsuranceGroupFactorList method fetches the InsuranceGroupFactor config, converts to RangeConfig and sets it to the cache
-// All operations are internal and selfcontained withing the cache config instance
-// returns error if any caused during fetching or conversion
-func (c *ConfigCache) FetchAndConvertInsuranceGroupFactorList() (error) {
-  log.Println("In FetchAndConvertInsuranceGroupFactorList ")
-  var temp []models.InsuranceGroupFactor
-	res, err := c.Fetcher.ReadFileAndGetAsObject("insurance-group-factor.json" , temp)
-	if err != nil {
-		log.Println("error reading the config file: ", err)
-		return err
-	}
-	log.Printf("List : %+v", res)
-	temp = res.([]models.InsuranceGroupFactor)
-  factorMapper := util.FactorMapper{}
- 	c.InsuranceGroupFactorList = factorMapper.InsuranceGroupFactorToRangeConfig(temp)
-  log.Println("Mapped range config from file: +v", c.InsuranceGroupFactorList)
-  return nil
+// Store method returns the versioned ConfigStore rooted at the Fetcher path
+func (c *ConfigCache) Store() *ConfigStore {
+  return &ConfigStore{Path: c.Fetcher.Path}
 }
 
-// FetchAndConvertLicenceValidityFactorList method fetches the LicenceValidityFactor config, converts to RangeConfig and sets it to the cache
-// All operations are internal and selfcontained withing the cache config instance
-// returns error if any caused during fetching or conversion
-func (c *ConfigCache) FetchAndConvertLicenceValidityFactorList() (error) {
-  log.Println("In FetchAndConvertLicenceValidityFactorList ")
-  var temp []models.LicenceValidityFactor
-  res, err := c.Fetcher.ReadFileAndGetAsObject("licence-validity-factor.json" , temp)
+// CreateVersion method validates the uploaded factor documents, stores them as a new
+// numbered version and activates it, the active version stays untouched if any step fails
+// returns the metadata of the created version
+func (c *ConfigCache) CreateVersion(ctx context.Context, files map[string][]byte, comment string) (*models.ConfigVersion, error) {
+  for name, data := range files {
+    if err := ValidateDocument(name, data); err != nil {
+      return nil, err
+    }
+  }
+  c.adminMutex.Lock()
+  defer c.adminMutex.Unlock()
+  version, err := c.Store().CreateVersion(files, comment)
   if err != nil {
-    log.Println("error reading the config file: ", err)
-    return err
+    logging.FromContext(ctx).Error("error storing the config version", "error", err)
+    return nil, err
+  }
+  if err = c.activate(ctx, version.Version); err != nil {
+    return nil, err
   }
+  version.Active = true
+  return version, nil
+}
 
-  log.Printf("List : %+v", res)
-  temp = res.([]models.LicenceValidityFactor)
-  factorMapper := util.FactorMapper{}
-  c.LicenceValidityFactorList = factorMapper.LicenceValidityFactorToRangeConfig(temp)
-  log.Println("Mapped range config from file: +v", c.LicenceValidityFactorList)
+// ActivateVersion method loads and validates a stored version and swaps it in as the live config
+// It is used both to roll forward and to roll back to any prior version
+// Returns error based on operation, in which case the previous version stays active
+func (c *ConfigCache) ActivateVersion(ctx context.Context, version int) (error) {
+  c.adminMutex.Lock()
+  defer c.adminMutex.Unlock()
+  return c.activate(ctx, version)
+}
+
+// ListVersions method lists the version history of the ConfigStore
+func (c *ConfigCache) ListVersions() ([]models.ConfigVersion, error) {
+  return c.Store().ListVersions()
+}
+
+// activate loads the given version, points the store to it and then swaps the cache data
+// keeping the current TTL window
+func (c *ConfigCache) activate(ctx context.Context, version int) (error) {
+  store := c.Store()
+  if !store.exists(version) {
+    return ErrVersionNotFound
+  }
+  snapshot, err := LoadConfigSnapshot(ctx, store.FetcherFor(version))
+  if err != nil {
+    return err
+  }
+  if err = store.SetActiveVersion(version); err != nil {
+    logging.FromContext(ctx).Error("error activating the config version", "version", version, "error", err)
+    return err
+  }
+  snapshot.Version = version
+  c.mutex.Lock()
+  ttl := c.TimeToLive - time.Now().Unix()
+  c.mutex.Unlock()
+  if ttl < 0 {
+    ttl = 0
+  }
+  c.apply(snapshot, ttl)
+  logging.FromContext(ctx).Info("Activated config version", "version", version)
   return nil
 }
+
+// apply swaps the cache data with the given snapshot under the write lock
+func (c *ConfigCache) apply(snapshot *ConfigSnapshot, TTL int64) {
+  c.mutex.Lock()
+  defer c.mutex.Unlock()
+  c.Version = snapshot.Version
+  c.BaseRateList = snapshot.BaseRateList
+  c.DriverAgeFactorList = snapshot.DriverAgeFactorList
+  c.InsuranceGroupFactorList = snapshot.InsuranceGroupFactorList
+  c.LicenceValidityFactorList = snapshot.LicenceValidityFactorList
+  c.InteractionFactorList = snapshot.InteractionFactorList
+  c.CategoricalFactorList = snapshot.CategoricalFactorList
+  c.PostcodeFactorList = snapshot.PostcodeFactorList
+  c.VehicleList = snapshot.VehicleList
+  c.HistoryLoadings = snapshot.HistoryLoadings
+  c.TemporalFactors = snapshot.TemporalFactors
+  c.HolidayList = snapshot.HolidayList
+  c.PromoCodeList = snapshot.PromoCodeList
+  c.LoyaltyFactor = snapshot.LoyaltyFactor
+  c.DriverCombination = snapshot.DriverCombination
+  c.loadedAt = snapshot.LoadedAt
+  metrics.SetConfigVersion(snapshot.Version)
+  c.TimeToLive = time.Now().Unix() + TTL // time to live in epoch seconds
+}
+
+// reloaded records the outcome of a reload, a failed one keeps the previous data in place
+func (c *ConfigCache) reloaded(err error) {
+  c.mutex.Lock()
+  defer c.mutex.Unlock()
+  c.lastReloadAt = time.Now()
+  c.lastReloadErr = err
+  if err != nil {
+    metrics.ConfigReloads.WithLabelValues(metrics.ReloadFailure).Inc()
+  } else {
+    metrics.ConfigReloads.WithLabelValues(metrics.ReloadSuccess).Inc()
+  }
+}
+
+// LoadConfigSnapshot method fetches every factor document through the given fetcher
+// and converts them to RangeConfig with the validation that is applied on uploads
+// The optional documents are only fetched when they are present
+// returns the loaded snapshot or error if any document could not be fetched or is invalid
+func LoadConfigSnapshot(ctx context.Context, fetcher ConfigFetcher) (*ConfigSnapshot, error) {
+  snapshot := ConfigSnapshot{LoadedAt: map[string]time.Time{}, InteractionFactorList: []models.InteractionTable{}, CategoricalFactorList: []models.CategoricalTable{}, PostcodeFactorList: []models.PostcodeRange{}, VehicleList: []models.Vehicle{}, HolidayList: []models.Holiday{}, PromoCodeList: []models.PromoCode{}}
+  lists := map[string]*[]models.RangeConfig{
+    BaseRateFile: &snapshot.BaseRateList,
+    DriverAgeFactorFile: &snapshot.DriverAgeFactorList,
+    InsuranceGroupFactorFile: &snapshot.InsuranceGroupFactorList,
+    LicenceValidityFactorFile: &snapshot.LicenceValidityFactorList,
+  }
+  for _, file := range FactorFiles {
+    list, err := FetchAndConvert(ctx, fetcher, file)
+    if err != nil {
+      return nil, err
+    }
+    *lists[file] = list
+    snapshot.LoadedAt[file] = time.Now()
+  }
+  if fetcher.Exists(InteractionFactorFile) {
+    tables, err := FetchAndConvertInteractions(ctx, fetcher, InteractionFactorFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.InteractionFactorList = tables
+    snapshot.LoadedAt[InteractionFactorFile] = time.Now()
+  }
+  if fetcher.Exists(CategoricalFactorFile) {
+    tables, err := FetchAndConvertCategoricals(ctx, fetcher, CategoricalFactorFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.CategoricalFactorList = tables
+    snapshot.LoadedAt[CategoricalFactorFile] = time.Now()
+  }
+  if fetcher.Exists(PostcodeFactorFile) {
+    postcodes, err := FetchAndConvertPostcodes(ctx, fetcher, PostcodeFactorFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.PostcodeFactorList = postcodes
+    snapshot.LoadedAt[PostcodeFactorFile] = time.Now()
+  }
+  if fetcher.Exists(VehicleFile) {
+    vehicles, err := FetchAndConvertVehicles(ctx, fetcher, VehicleFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.VehicleList = vehicles
+    snapshot.LoadedAt[VehicleFile] = time.Now()
+  }
+  if fetcher.Exists(HistoryLoadingsFile) {
+    loadings, err := FetchAndConvertHistoryLoadings(ctx, fetcher, HistoryLoadingsFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.HistoryLoadings = loadings
+    snapshot.LoadedAt[HistoryLoadingsFile] = time.Now()
+  }
+  if fetcher.Exists(TemporalFactorFile) {
+    factors, err := FetchAndConvertTemporalFactors(ctx, fetcher, TemporalFactorFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.TemporalFactors = factors
+    snapshot.LoadedAt[TemporalFactorFile] = time.Now()
+  }
+  if fetcher.Exists(HolidayFile) {
+    holidays, err := FetchAndConvertHolidays(ctx, fetcher, HolidayFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.HolidayList = holidays
+    snapshot.LoadedAt[HolidayFile] = time.Now()
+  }
+  if fetcher.Exists(PromoCodeFile) {
+    promos, err := FetchAndConvertPromoCodes(ctx, fetcher, PromoCodeFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.PromoCodeList = promos
+    snapshot.LoadedAt[PromoCodeFile] = time.Now()
+  }
+  if fetcher.Exists(LoyaltyFactorFile) {
+    loyalty, err := FetchAndConvertLoyaltyFactor(ctx, fetcher, LoyaltyFactorFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.LoyaltyFactor = loyalty
+    snapshot.LoadedAt[LoyaltyFactorFile] = time.Now()
+  }
+  if fetcher.Exists(DriverCombinationFile) {
+    combination, err := FetchAndConvertDriverCombination(ctx, fetcher, DriverCombinationFile)
+    if err != nil {
+      return nil, err
+    }
+    snapshot.DriverCombination = combination
+    snapshot.LoadedAt[DriverCombinationFile] = time.Now()
+  }
+  return &snapshot, nil
+}
+
+// FetchAndConvertDriverCombination method fetches the named driver combination document and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertDriverCombination(ctx context.Context, fetcher ConfigFetcher, filename string) (result *models.DriverCombination, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateDriverCombinationFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped driver combination", "rule", result.Rule)
+  return result, nil
+}
+
+// FetchAndConvertLoyaltyFactor method fetches the named loyalty factor document and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertLoyaltyFactor(ctx context.Context, fetcher ConfigFetcher, filename string) (result *models.LoyaltyFactor, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateLoyaltyFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped loyalty factor", "basis", result.Basis, "bands", len(result.Bands))
+  return result, nil
+}
+
+// FetchAndConvertPromoCodes method fetches the named promo codes document and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertPromoCodes(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.PromoCode, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidatePromoCodeFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped promo codes", "promo_codes", len(result))
+  return result, nil
+}
+
+// FetchAndConvertTemporalFactors method fetches the named temporal factors document and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertTemporalFactors(ctx context.Context, fetcher ConfigFetcher, filename string) (result *models.TemporalFactors, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateTemporalFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped temporal factors", "factors", result)
+  return result, nil
+}
+
+// FetchAndConvertHolidays method fetches the named holiday calendar
+// returns error if any caused during fetching or validation
+func FetchAndConvertHolidays(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.Holiday, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateHolidayFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped holiday calendar", "holidays", len(result))
+  return result, nil
+}
+
+// FetchAndConvertHistoryLoadings method fetches the named history loadings document and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertHistoryLoadings(ctx context.Context, fetcher ConfigFetcher, filename string) (result *models.HistoryLoadings, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateHistoryLoadingsFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped history loadings", "loadings", result)
+  return result, nil
+}
+
+// FetchAndConvertVehicles method fetches the named vehicle table and normalises it
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertVehicles(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.Vehicle, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateVehicleFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped vehicle table", "vehicles", len(result))
+  return result, nil
+}
+
+// FetchAndConvertPostcodes method fetches the named postcode document and converts it to PostcodeRange
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertPostcodes(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.PostcodeRange, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidatePostcodeFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped postcode factor", "prefixes", len(result))
+  return result, nil
+}
+
+// FetchAndConvertCategoricals method fetches the named categorical document and converts it to CategoricalTable
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertCategoricals(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.CategoricalTable, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateCategoricalFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped categorical tables", "tables", result)
+  return result, nil
+}
+
+// FetchAndConvertInteractions method fetches the named interaction document and converts it to InteractionTable
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvertInteractions(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.InteractionTable, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateInteractionFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped interaction tables", "tables", result)
+  return result, nil
+}
+
+// FetchAndConvert method fetches the named factor document and converts it to RangeConfig
+// returns error if any caused during fetching, conversion or validation
+func FetchAndConvert(ctx context.Context, fetcher ConfigFetcher, filename string) (result []models.RangeConfig, err error) {
+  ctx, span := tracing.Start(ctx, "config.FetchAndConvert", attribute.String("config.file", filename))
+  defer func() { tracing.End(span, err) }()
+  logger := logging.FromContext(ctx).With("file", filename)
+  data, err := fetcher.ReadFile(filename)
+  if err != nil {
+    logger.Error("error reading the config file", "error", err)
+    return nil, err
+  }
+  result, err = ValidateFactorFile(filename, data)
+  if err != nil {
+    logger.Error("error converting the config file", "error", err)
+    return nil, err
+  }
+  logger.Debug("Mapped range config", "ranges", result)
+  return result, nil
+}
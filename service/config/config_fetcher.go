@@ -1,42 +1,68 @@
 package config
 
 import (
+	"context"
 	"os"
 	"io/ioutil"
 	"encoding/json"
+	"path/filepath"
 	"reflect"
-  "log"
+
+	"pricingengine/service/logging"
 )
 
+// ConfigFetcher reads the factor documents of the directory Path, an absolute one or one relative to the working directory
 type ConfigFetcher struct{
   Path string
 }
 
+// ReadFile method reads the raw content of the file in the mentioned path
+// returns the file bytes or error if any caused during reading the document
+func (c *ConfigFetcher) ReadFile(filename string) ([]byte, error) {
+	return ioutil.ReadFile(c.file(filename))
+}
+
+// Exists method tells whether the file is present in the mentioned path
+func (c *ConfigFetcher) Exists(filename string) bool {
+	info, err := os.Stat(c.file(filename))
+	return err == nil && !info.IsDir()
+}
+
+// file returns the path of the document in the directory of the fetcher
+func (c *ConfigFetcher) file(filename string) string {
+	return filepath.Join(c.Path, filename)
+}
+
 // ReadFileAndGetAsObject method reads the file in the mentioned path
 // Dynamic conversion of the data fetched to a generic interface helps
 // runtime conversion of the fetched object in a genreic way
 // The casting decision is upto the calling method
+// The errors are logged with the logger of the context, carrying the request ID of the caller
 // returns the resultant object or error if any caused during fetching the data document
-func (c *ConfigFetcher) ReadFileAndGetAsObject(filename string, class interface{}) (interface{}, error) {
-  log.Println("Entering ReadFileAndGetAsObject")
-	pwd, _ := os.Getwd()
-	log.Println("Current Working Directory: ", pwd)
-  jsonFile, err := os.Open(pwd+c.Path+filename)
-	// txt, _ := ioutil.ReadFile(pwd+"/path/to/file.txt")
+func (c *ConfigFetcher) ReadFileAndGetAsObject(ctx context.Context, filename string, class interface{}) (interface{}, error) {
+	logger := logging.FromContext(ctx).With("file", filename)
+	logger.Debug("Entering ReadFileAndGetAsObject", "path", c.Path)
+  jsonFile, err := os.Open(c.file(filename))
   // if we os.Open returns an error then handle it
 
   if err != nil {
-			log.Printf("error opening file: %v", err)
+			logger.Error("error opening file", "error", err)
       return nil, err
   }
-  log.Println("Successfully Opened ", filename)
   // defer the closing of our jsonFile so that we can parse it later on
   defer jsonFile.Close()
-	byteValue, _ := ioutil.ReadAll(jsonFile)
+	byteValue, err := ioutil.ReadAll(jsonFile)
+	if err != nil {
+		logger.Error("error reading file", "error", err)
+		return nil, err
+	}
 
 	command := reflect.New(reflect.TypeOf(class))
-  json.Unmarshal([]byte(byteValue), command.Interface())
+	err = json.Unmarshal([]byte(byteValue), command.Interface())
+	if err != nil {
+		logger.Error("error parsing file", "error", err)
+		return nil, err
+	}
 	result := command.Elem().Interface()
-  log.Println("Leaving ReadFileAndGetAsObject")
 	return result, nil
 }
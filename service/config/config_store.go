@@ -0,0 +1,235 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pricingengine/service/model"
+)
+
+// ErrVersionNotFound is returned when a config version that was never stored is requested
+var ErrVersionNotFound = errors.New("config version not found")
+
+const (
+	versionsDir       = "versions/"
+	activeVersionFile = "ACTIVE"
+	versionMetaFile   = "version.json"
+)
+
+// ConfigStore keeps numbered versions of the factor documents next to the base config
+// Version 0 is the set of files directly under Path, every uploaded set lives under
+// Path/versions/<number>/ along with its metadata, and Path/versions/ACTIVE points to the live one
+// Path is an absolute directory or one relative to the working directory, as the Path of a ConfigFetcher
+// The store does not lock by itself, callers are expected to serialise the writes
+type ConfigStore struct {
+	Path string
+}
+
+// FetcherFor method returns the ConfigFetcher reading the documents of the given version
+func (s *ConfigStore) FetcherFor(version int) ConfigFetcher {
+	if version == 0 {
+		return ConfigFetcher{Path: s.Path}
+	}
+	return ConfigFetcher{Path: filepath.Join(s.Path, versionsDir, strconv.Itoa(version))}
+}
+
+// ActiveVersion method reads the currently active version number
+// returns 0 when no version was ever activated
+func (s *ConfigStore) ActiveVersion() (int, error) {
+	data, err := ioutil.ReadFile(s.dir() + activeVersionFile)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// SetActiveVersion method atomically points the store to an existing version
+func (s *ConfigStore) SetActiveVersion(version int) error {
+	if !s.exists(version) {
+		return ErrVersionNotFound
+	}
+	if err := os.MkdirAll(s.dir(), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.dir()+activeVersionFile, []byte(strconv.Itoa(version)))
+}
+
+// ListVersions method returns the metadata of every stored version, oldest first
+func (s *ConfigStore) ListVersions() ([]models.ConfigVersion, error) {
+	active, err := s.ActiveVersion()
+	if err != nil {
+		return nil, err
+	}
+	result := []models.ConfigVersion{
+		models.ConfigVersion{Version: 0, Comment: "base config", Files: s.baseFiles()},
+	}
+	numbers, err := s.versionNumbers()
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range numbers {
+		meta, err := s.readMeta(number)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *meta)
+	}
+	for i := range result {
+		result[i].Active = result[i].Version == active
+	}
+	return result, nil
+}
+
+// CreateVersion method stores a new numbered version made of the uploaded documents
+// Any factor document missing from the upload is carried over from the active version
+// The documents are expected to be validated already, the version is not activated here
+// returns the metadata of the created version
+func (s *ConfigStore) CreateVersion(files map[string][]byte, comment string) (*models.ConfigVersion, error) {
+	active, err := s.ActiveVersion()
+	if err != nil {
+		return nil, err
+	}
+	numbers, err := s.versionNumbers()
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if len(numbers) > 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+
+	// write everything to a temporary directory first so a half written version is never visible
+	staging := s.dir() + ".staging-" + strconv.Itoa(next) + "/"
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	activeFetcher := s.FetcherFor(active)
+	uploaded := []string{}
+	for _, name := range AllFactorFiles() {
+		data, ok := files[name]
+		if ok {
+			uploaded = append(uploaded, name)
+		} else if isOptional(name) && !activeFetcher.Exists(name) {
+			continue
+		} else {
+			data, err = activeFetcher.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := ioutil.WriteFile(staging+name, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	meta := models.ConfigVersion{
+		Version:   next,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Comment:   comment,
+		Files:     uploaded,
+		Parent:    active,
+	}
+	metaBytes, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(staging+versionMetaFile, metaBytes, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, s.dir()+strconv.Itoa(next)); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// baseFiles returns the factor documents present directly under Path, the optional ones included
+func (s *ConfigStore) baseFiles() []string {
+	fetcher := s.FetcherFor(0)
+	files := []string{}
+	for _, name := range AllFactorFiles() {
+		if fetcher.Exists(name) {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// isOptional tells whether the document is one of the OptionalFactorFiles
+func isOptional(filename string) bool {
+	for _, name := range OptionalFactorFiles {
+		if name == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// dir returns the directory holding the numbered versions, ending with a separator
+func (s *ConfigStore) dir() string {
+	return filepath.Join(s.Path, versionsDir) + string(filepath.Separator)
+}
+
+// exists tells whether the given version has been stored
+func (s *ConfigStore) exists(version int) bool {
+	if version == 0 {
+		return true
+	}
+	_, err := os.Stat(s.dir() + strconv.Itoa(version) + "/" + versionMetaFile)
+	return err == nil
+}
+
+// versionNumbers returns the stored version numbers in ascending order
+func (s *ConfigStore) versionNumbers() ([]int, error) {
+	entries, err := ioutil.ReadDir(s.dir())
+	if os.IsNotExist(err) {
+		return []int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	numbers := []int{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if number, err := strconv.Atoi(entry.Name()); err == nil && number > 0 {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// readMeta reads the metadata document of a stored version
+func (s *ConfigStore) readMeta(version int) (*models.ConfigVersion, error) {
+	data, err := ioutil.ReadFile(s.dir() + strconv.Itoa(version) + "/" + versionMetaFile)
+	if err != nil {
+		return nil, err
+	}
+	meta := models.ConfigVersion{}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// writeFileAtomic writes the data to a sibling temporary file and renames it in place
+func writeFileAtomic(filename string, data []byte) error {
+	tmp := filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
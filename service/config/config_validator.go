@@ -0,0 +1,735 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pricingengine/service/model"
+	"pricingengine/service/strategy"
+	"pricingengine/service/util"
+)
+
+// Names of the factor documents that together make up one config set
+const (
+	BaseRateFile              = "base-rate.json"
+	DriverAgeFactorFile       = "driver-age-factor.json"
+	InsuranceGroupFactorFile  = "insurance-group-factor.json"
+	LicenceValidityFactorFile = "licence-validity-factor.json"
+	InteractionFactorFile     = "interaction-factors.json"
+	CategoricalFactorFile     = "categorical-factors.json"
+	PostcodeFactorFile        = "postcode-factor.json"
+	VehicleFile               = "vehicles.json"
+	HistoryLoadingsFile       = "history-loadings.json"
+	TemporalFactorFile        = "temporal-factors.json"
+	HolidayFile               = "holidays.json"
+	PromoCodeFile             = "promo-codes.json"
+	LoyaltyFactorFile         = "loyalty-factor.json"
+	DriverCombinationFile     = "driver-combination.json"
+)
+
+// FactorFiles lists every document a config set is expected to contain
+var FactorFiles = []string{
+	BaseRateFile,
+	DriverAgeFactorFile,
+	InsuranceGroupFactorFile,
+	LicenceValidityFactorFile,
+}
+
+// OptionalFactorFiles lists the documents a config set may contain, the factors they hold are
+// only applied when they are present
+var OptionalFactorFiles = []string{
+	InteractionFactorFile,
+	CategoricalFactorFile,
+	PostcodeFactorFile,
+	VehicleFile,
+	HistoryLoadingsFile,
+	TemporalFactorFile,
+	HolidayFile,
+	PromoCodeFile,
+	LoyaltyFactorFile,
+	DriverCombinationFile,
+}
+
+// ValidationError is returned when a factor document can not be decoded,
+// mapped or does not hold sensible ranges
+type ValidationError struct {
+	File   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config " + e.File + ": " + e.Reason
+}
+
+// IsFactorFile method tells whether the given name is one of the known factor documents
+func IsFactorFile(filename string) bool {
+	for _, name := range AllFactorFiles() {
+		if name == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// AllFactorFiles method returns the FactorFiles followed by the OptionalFactorFiles
+func AllFactorFiles() []string {
+	return append(append([]string{}, FactorFiles...), OptionalFactorFiles...)
+}
+
+// ValidateDocument method validates any of the factor documents, with ValidateInteractionFile
+// for the interaction tables and ValidateFactorFile for the others
+// returns a *ValidationError describing the first problem found
+func ValidateDocument(filename string, data []byte) error {
+	switch filename {
+	case InteractionFactorFile:
+		_, err := ValidateInteractionFile(filename, data)
+		return err
+	case CategoricalFactorFile:
+		_, err := ValidateCategoricalFile(filename, data)
+		return err
+	case PostcodeFactorFile:
+		_, err := ValidatePostcodeFile(filename, data)
+		return err
+	case VehicleFile:
+		_, err := ValidateVehicleFile(filename, data)
+		return err
+	case HistoryLoadingsFile:
+		_, err := ValidateHistoryLoadingsFile(filename, data)
+		return err
+	case TemporalFactorFile:
+		_, err := ValidateTemporalFile(filename, data)
+		return err
+	case HolidayFile:
+		_, err := ValidateHolidayFile(filename, data)
+		return err
+	case PromoCodeFile:
+		_, err := ValidatePromoCodeFile(filename, data)
+		return err
+	case LoyaltyFactorFile:
+		_, err := ValidateLoyaltyFile(filename, data)
+		return err
+	case DriverCombinationFile:
+		_, err := ValidateDriverCombinationFile(filename, data)
+		return err
+	}
+	_, err := ValidateFactorFile(filename, data)
+	return err
+}
+
+// ValidateTemporalFile method decodes the temporal factors strictly, checks the timezone is known, the hour bands
+// are within the day and the day bands name days of the week listed once, and normalises them with the same
+// FactorMapper used when the cache is loaded
+// returns the normalised TemporalFactors or a *ValidationError describing the first problem found
+func ValidateTemporalFile(filename string, data []byte) (*models.TemporalFactors, error) {
+	var factors models.TemporalFactors
+	if err := decodeStrict(data, &factors); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	factorMapper := util.FactorMapper{}
+	factors = factorMapper.NormaliseTemporalFactors(factors)
+	if err := validateTemporal(factors); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	return &factors, nil
+}
+
+// ValidateHolidayFile method decodes the holiday calendar strictly and checks every holiday has a date
+// as YYYY-MM-DD listed once
+// returns the Holiday list or a *ValidationError describing the first problem found
+func ValidateHolidayFile(filename string, data []byte) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	if err := decodeStrict(data, &holidays); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	seen := map[string]bool{}
+	for i, holiday := range holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("holiday %d should have a date as YYYY-MM-DD", i)}
+		}
+		if seen[holiday.Date] {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("holiday date %s is listed twice", holiday.Date)}
+		}
+		seen[holiday.Date] = true
+	}
+	return holidays, nil
+}
+
+// ValidatePromoCodeFile method decodes the promo codes strictly, checks every code is listed once with a
+// discount, validity dates and limits that can be applied, and normalises them with the same FactorMapper
+// used when the cache is loaded
+// returns the normalised PromoCode list or a *ValidationError describing the first problem found
+func ValidatePromoCodeFile(filename string, data []byte) ([]models.PromoCode, error) {
+	var promos []models.PromoCode
+	if err := decodeStrict(data, &promos); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	factorMapper := util.FactorMapper{}
+	result := factorMapper.NormalisePromoCodes(promos)
+	codes := map[string]bool{}
+	for _, promo := range result {
+		if err := validatePromo(promo); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		if codes[promo.Code] {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("promo code %q is listed twice", promo.Code)}
+		}
+		codes[promo.Code] = true
+	}
+	return result, nil
+}
+
+// ValidateLoyaltyFile method decodes the loyalty factor strictly, checks its basis is known and its bands
+// start from distinct runs that are not negative, and normalises it with the same FactorMapper used when
+// the cache is loaded
+// returns the normalised LoyaltyFactor or a *ValidationError describing the first problem found
+func ValidateLoyaltyFile(filename string, data []byte) (*models.LoyaltyFactor, error) {
+	var loyalty models.LoyaltyFactor
+	if err := decodeStrict(data, &loyalty); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	factorMapper := util.FactorMapper{}
+	loyalty = factorMapper.NormaliseLoyaltyFactor(loyalty)
+	if err := validateLoyalty(loyalty); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	return &loyalty, nil
+}
+
+// ValidateDriverCombinationFile method decodes the driver combination strictly, checks its rule is known, the weight
+// of the main driver is within 0 and 1 and the main-plus-loading rule has a loading that can be applied, and normalises
+// it with the same FactorMapper used when the cache is loaded
+// returns the normalised DriverCombination or a *ValidationError describing the first problem found
+func ValidateDriverCombinationFile(filename string, data []byte) (*models.DriverCombination, error) {
+	var combination models.DriverCombination
+	if err := decodeStrict(data, &combination); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	factorMapper := util.FactorMapper{}
+	combination = factorMapper.NormaliseDriverCombination(combination)
+	if err := validateDriverCombination(combination); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	return &combination, nil
+}
+
+// ValidateHistoryLoadingsFile method decodes the claims and convictions loadings strictly, checks their lookback
+// windows and caps, that every loading is at least 1 and every claim type and conviction code is listed once,
+// and normalises them with the same FactorMapper used when the cache is loaded
+// returns the normalised HistoryLoadings or a *ValidationError describing the first problem found
+func ValidateHistoryLoadingsFile(filename string, data []byte) (*models.HistoryLoadings, error) {
+	var loadings models.HistoryLoadings
+	if err := decodeStrict(data, &loadings); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	factorMapper := util.FactorMapper{}
+	loadings = factorMapper.NormaliseHistoryLoadings(loadings)
+	if err := validateHistoryLoadings(loadings); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	return &loadings, nil
+}
+
+// ValidateVehicleFile method decodes the vehicle table strictly, checks every vehicle can be found by a
+// registration or code listed once and has an insurance group, a value and a registration date,
+// and normalises it with the same FactorMapper used when the cache is loaded
+// returns the normalised Vehicle list or a *ValidationError describing the first problem found
+func ValidateVehicleFile(filename string, data []byte) ([]models.Vehicle, error) {
+	var vehicles []models.Vehicle
+	if err := decodeStrict(data, &vehicles); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	if len(vehicles) == 0 {
+		return nil, &ValidationError{File: filename, Reason: "the vehicle table cannot be empty"}
+	}
+	factorMapper := util.FactorMapper{}
+	result := factorMapper.NormaliseVehicles(vehicles)
+	seen := map[string]bool{}
+	for i, vehicle := range result {
+		if len(vehicle.Registration) == 0 && len(vehicle.Code) == 0 {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("vehicle %d should have a registration or an abi-code", i)}
+		}
+		for _, id := range [][2]string{{"registration", vehicle.Registration}, {"abi-code", vehicle.Code}} {
+			key := id[0] + " " + id[1]
+			if len(id[1]) == 0 {
+				continue
+			}
+			if seen[key] {
+				return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("%s is listed twice", key)}
+			}
+			seen[key] = true
+		}
+		if vehicle.InsuranceGroup <= 0 {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("vehicle %d should have a positive insurance-group", i)}
+		}
+		if vehicle.Value < 0 {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("vehicle %d has a negative value", i)}
+		}
+		if _, err := time.Parse("2006-01-02", vehicle.Registered); err != nil {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("vehicle %d should have a registered date as YYYY-MM-DD", i)}
+		}
+	}
+	return result, nil
+}
+
+// ValidatePostcodeFile method decodes the postcode factor strictly, checks every prefix is a postcode area,
+// district, sector or full postcode listed once, and converts it with the same FactorMapper used when
+// the cache is loaded
+// returns the converted PostcodeRange list or a *ValidationError describing the first problem found
+func ValidatePostcodeFile(filename string, data []byte) ([]models.PostcodeRange, error) {
+	var postcodes []models.PostcodeFactor
+	if err := decodeStrict(data, &postcodes); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	if len(postcodes) == 0 {
+		return nil, &ValidationError{File: filename, Reason: "the postcode factor cannot be empty"}
+	}
+	prefixes := map[string]bool{}
+	for _, postcode := range postcodes {
+		prefix, ok := util.NormalisePostcodePrefix(postcode.Prefix)
+		if !ok {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("prefix %q should be a postcode area, district, sector or full postcode", postcode.Prefix)}
+		}
+		if prefixes[prefix] {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("prefix %q is listed twice", prefix)}
+		}
+		prefixes[prefix] = true
+		if err := validateFactor(fmt.Sprintf("prefix %q", prefix), postcode.Operation, postcode.IsEligible, postcode.Factor); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+	}
+	factorMapper := util.FactorMapper{}
+	return factorMapper.PostcodeFactorToPostcodeRange(postcodes), nil
+}
+
+// ValidateCategoricalFile method decodes the categorical tables strictly, checks every table is keyed
+// on a known attribute and every entry matches exactly one way, and converts them with the same
+// FactorMapper used when the cache is loaded
+// returns the converted CategoricalTable list or a *ValidationError describing the first problem found
+func ValidateCategoricalFile(filename string, data []byte) ([]models.CategoricalTable, error) {
+	var categoricals []models.CategoricalFactor
+	if err := decodeStrict(data, &categoricals); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	names := map[string]bool{}
+	for _, categorical := range categoricals {
+		if err := validateCategorical(categorical); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		if names[categorical.Name] {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("categorical %q is defined twice", categorical.Name)}
+		}
+		names[categorical.Name] = true
+	}
+	factorMapper := util.FactorMapper{}
+	return factorMapper.CategoricalFactorToCategoricalTable(categoricals), nil
+}
+
+// ValidateInteractionFile method decodes the interaction tables strictly, checks every table is
+// keyed on known and distinct dimensions and every cell holds a valid band per dimension, and
+// converts them with the same FactorMapper used when the cache is loaded
+// returns the converted InteractionTable list or a *ValidationError describing the first problem found
+func ValidateInteractionFile(filename string, data []byte) ([]models.InteractionTable, error) {
+	var interactions []models.InteractionFactor
+	if err := decodeStrict(data, &interactions); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	names := map[string]bool{}
+	for _, interaction := range interactions {
+		if err := validateInteraction(interaction); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		if names[interaction.Name] {
+			return nil, &ValidationError{File: filename, Reason: fmt.Sprintf("interaction %q is defined twice", interaction.Name)}
+		}
+		names[interaction.Name] = true
+	}
+	factorMapper := util.FactorMapper{}
+	return factorMapper.InteractionFactorToInteractionTable(interactions), nil
+}
+
+// ValidateFactorFile method decodes the given factor document strictly, converts it
+// with the same FactorMapper used when the cache is loaded and checks the resulting ranges
+// returns the converted RangeConfig list or a *ValidationError describing the first problem found
+func ValidateFactorFile(filename string, data []byte) ([]models.RangeConfig, error) {
+	factorMapper := util.FactorMapper{}
+	var result []models.RangeConfig
+	switch filename {
+	case BaseRateFile:
+		var rates []models.BaseRate
+		if err := decodeStrict(data, &rates); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		result = factorMapper.BaseRateToRangeConfig(rates)
+	case DriverAgeFactorFile:
+		var factors []models.DriverAgeFactor
+		if err := decodeStrict(data, &factors); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		result = factorMapper.DriverAgeFactorToRangeConfig(factors)
+	case InsuranceGroupFactorFile:
+		var factors []models.InsuranceGroupFactor
+		if err := decodeStrict(data, &factors); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		for _, factor := range factors {
+			if err := validateBand(factor.Group); err != nil {
+				return nil, &ValidationError{File: filename, Reason: err.Error()}
+			}
+		}
+		result = factorMapper.InsuranceGroupFactorToRangeConfig(factors)
+	case LicenceValidityFactorFile:
+		var factors []models.LicenceValidityFactor
+		if err := decodeStrict(data, &factors); err != nil {
+			return nil, &ValidationError{File: filename, Reason: err.Error()}
+		}
+		for _, factor := range factors {
+			if err := validateBand(factor.Length); err != nil {
+				return nil, &ValidationError{File: filename, Reason: err.Error()}
+			}
+		}
+		result = factorMapper.LicenceValidityFactorToRangeConfig(factors)
+	default:
+		return nil, &ValidationError{File: filename, Reason: "unknown factor file"}
+	}
+	if err := validateRanges(result); err != nil {
+		return nil, &ValidationError{File: filename, Reason: err.Error()}
+	}
+	return result, nil
+}
+
+// decodeStrict unmarshals a JSON document rejecting unknown fields and trailing data
+func decodeStrict(data []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the factor list")
+	}
+	return nil
+}
+
+// validateBand checks a band in the "start-end" or "start" format used by the factor files
+func validateBand(band string) error {
+	for _, part := range strings.Split(band, "-") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return fmt.Errorf("band %q is not in the start-end format", band)
+		}
+	}
+	return nil
+}
+
+// validateInteraction checks an interaction table is usable by the pricing strategies
+func validateInteraction(interaction models.InteractionFactor) error {
+	if len(interaction.Name) == 0 {
+		return errors.New("interaction name cannot be empty")
+	}
+	if len(interaction.Dimensions) < 2 {
+		return fmt.Errorf("interaction %q should have at least two dimensions", interaction.Name)
+	}
+	seen := map[string]bool{}
+	for _, dimension := range interaction.Dimensions {
+		known := false
+		for _, candidate := range strategy.Dimensions {
+			known = known || candidate == dimension
+		}
+		if !known {
+			return fmt.Errorf("interaction %q has an unknown dimension %q, should be one of %s", interaction.Name, dimension, strings.Join(strategy.Dimensions, ", "))
+		}
+		if seen[dimension] {
+			return fmt.Errorf("interaction %q has the dimension %q twice", interaction.Name, dimension)
+		}
+		seen[dimension] = true
+	}
+	if len(interaction.Cells) == 0 {
+		return fmt.Errorf("interaction %q cannot have empty cells", interaction.Name)
+	}
+	for i, cell := range interaction.Cells {
+		if len(cell.Bands) != len(interaction.Dimensions) {
+			return fmt.Errorf("interaction %q cell %d should have %d bands, one per dimension", interaction.Name, i, len(interaction.Dimensions))
+		}
+		for _, band := range cell.Bands {
+			if band == "*" {
+				continue
+			}
+			if err := validateBand(band); err != nil {
+				return fmt.Errorf("interaction %q cell %d: %v", interaction.Name, i, err)
+			}
+		}
+		if err := validateFactor(fmt.Sprintf("interaction %q cell %d", interaction.Name, i), cell.Operation, cell.IsEligible, cell.Factor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePromo checks a normalised promo code is usable by the pricing strategies
+func validatePromo(promo models.PromoCode) error {
+	if len(promo.Code) == 0 {
+		return errors.New("promo code cannot be empty")
+	}
+	if promo.Type != strategy.OperationPercentDiscount && promo.Type != strategy.OperationSubtract {
+		return fmt.Errorf("promo code %q has an unknown type %q, should be %s or %s", promo.Code, promo.Type, strategy.OperationPercentDiscount, strategy.OperationSubtract)
+	}
+	if promo.Value <= 0 {
+		return fmt.Errorf("promo code %q should have a positive value", promo.Code)
+	}
+	if err := validateFactor(fmt.Sprintf("promo code %q", promo.Code), promo.Type, true, promo.Value); err != nil {
+		return err
+	}
+	for _, date := range []string{promo.ValidFrom, promo.ValidTo} {
+		if _, err := time.Parse("2006-01-02", date); len(date) > 0 && err != nil {
+			return fmt.Errorf("promo code %q validity dates should be dates as YYYY-MM-DD", promo.Code)
+		}
+	}
+	if len(promo.ValidFrom) > 0 && len(promo.ValidTo) > 0 && promo.ValidFrom > promo.ValidTo {
+		return fmt.Errorf("promo code %q is valid to a date before its valid-from", promo.Code)
+	}
+	for _, duration := range promo.Durations {
+		if len(duration) == 0 {
+			return fmt.Errorf("promo code %q has an empty duration", promo.Code)
+		}
+	}
+	if promo.MinPremium < 0 {
+		return fmt.Errorf("promo code %q has a negative min-premium", promo.Code)
+	}
+	if promo.MaxUses < 0 {
+		return fmt.Errorf("promo code %q has a negative max-uses", promo.Code)
+	}
+	return nil
+}
+
+// validateDriverCombination checks a normalised driver combination is usable by the pricing strategies
+func validateDriverCombination(combination models.DriverCombination) error {
+	known := false
+	for _, rule := range strategy.DriverRules {
+		known = known || rule == combination.Rule
+	}
+	if !known {
+		return fmt.Errorf("unknown rule %q, should be one of %s", combination.Rule, strings.Join(strategy.DriverRules, ", "))
+	}
+	if combination.MainWeight < 0 || combination.MainWeight > 1 {
+		return errors.New("main-weight should be within 0 and 1")
+	}
+	if combination.Rule == strategy.DriverRuleMainPlusLoading && combination.Loading == nil {
+		return fmt.Errorf("rule %s needs a loading", combination.Rule)
+	}
+	if combination.Loading != nil {
+		return validateFactor("additional driver loading", combination.Loading.Operation, true, combination.Loading.Factor)
+	}
+	return nil
+}
+
+// validateLoyalty checks a normalised loyalty factor is usable by the pricing strategies
+func validateLoyalty(loyalty models.LoyaltyFactor) error {
+	known := false
+	for _, basis := range strategy.LoyaltyBases {
+		known = known || basis == loyalty.Basis
+	}
+	if !known {
+		return fmt.Errorf("unknown basis %q, should be one of %s", loyalty.Basis, strings.Join(strategy.LoyaltyBases, ", "))
+	}
+	if len(loyalty.Bands) == 0 {
+		return errors.New("bands cannot be empty")
+	}
+	seen := map[int]bool{}
+	for _, band := range loyalty.Bands {
+		if band.From < 0 {
+			return fmt.Errorf("loyalty band %q cannot start from a negative run", band.Label)
+		}
+		if seen[band.From] {
+			return fmt.Errorf("loyalty bands start from %d twice", band.From)
+		}
+		seen[band.From] = true
+		if err := validateFactor(fmt.Sprintf("loyalty band %q", band.Label), band.Operation, true, band.Factor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTemporal checks the normalised temporal factors are usable by the pricing strategies
+func validateTemporal(factors models.TemporalFactors) error {
+	if len(factors.Timezone) == 0 {
+		return errors.New("timezone cannot be empty")
+	}
+	if _, err := time.LoadLocation(factors.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", factors.Timezone)
+	}
+	for i, hour := range factors.Hours {
+		if hour.From < 0 || hour.From > 23 || hour.To < 0 || hour.To > 24 || hour.From == hour.To {
+			return fmt.Errorf("hour band %d should go from an hour 0-23 to a different hour 0-24", i)
+		}
+		if err := validateFactor(fmt.Sprintf("hour band %d", i), hour.Operation, hour.IsEligible, hour.Factor); err != nil {
+			return err
+		}
+	}
+	weekdays := map[string]bool{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		weekdays[strings.ToLower(day.String())] = false
+	}
+	for i, band := range factors.Days {
+		if len(band.Days) == 0 {
+			return fmt.Errorf("day band %d cannot have empty days", i)
+		}
+		for _, day := range band.Days {
+			listed, known := weekdays[day]
+			if !known {
+				return fmt.Errorf("day band %d has an unknown day %q", i, day)
+			}
+			if listed {
+				return fmt.Errorf("day %q is listed twice", day)
+			}
+			weekdays[day] = true
+		}
+		if err := validateFactor(fmt.Sprintf("day band %d", i), band.Operation, band.IsEligible, band.Factor); err != nil {
+			return err
+		}
+	}
+	if factors.Holiday != nil {
+		return validateFactor("holiday", factors.Holiday.Operation, factors.Holiday.IsEligible, factors.Holiday.Factor)
+	}
+	return nil
+}
+
+// validateFactor checks the operation of a band is known and its factor is not negative, a percent-discount
+// is at most 100 and an eligible band multiplying or overriding the premium does not make it 0
+func validateFactor(name string, operation string, eligible bool, factor float64) error {
+	known := len(operation) == 0
+	for _, candidate := range strategy.Operations {
+		known = known || candidate == operation
+	}
+	if !known {
+		return fmt.Errorf("%s has an unknown operation %q, should be one of %s", name, operation, strings.Join(strategy.Operations, ", "))
+	}
+	if factor < 0 {
+		return fmt.Errorf("%s has a negative factor", name)
+	}
+	if operation == strategy.OperationPercentDiscount && factor > 100 {
+		return fmt.Errorf("%s cannot discount more than 100 percent", name)
+	}
+	zeroes := operation == strategy.OperationMultiply || operation == strategy.OperationOverride || len(operation) == 0
+	if eligible && zeroes && factor == 0 {
+		return fmt.Errorf("%s eligible band has a zero factor", name)
+	}
+	return nil
+}
+
+// validateHistoryLoadings checks the normalised claims and convictions loadings are usable by the pricing strategies
+func validateHistoryLoadings(loadings models.HistoryLoadings) error {
+	claims, convictions := loadings.Claims, loadings.Convictions
+	for _, section := range []struct {
+		name     string
+		lookback int
+		max      float64
+	}{{"claims", claims.LookbackYears, claims.MaxLoading}, {"convictions", convictions.LookbackYears, convictions.MaxLoading}} {
+		if section.lookback <= 0 {
+			return fmt.Errorf("%s should have a positive lookback-years", section.name)
+		}
+		if section.max != 0 && section.max < 1 {
+			return fmt.Errorf("%s max-loading should be at least 1", section.name)
+		}
+	}
+	if convictions.MaxPoints < 0 {
+		return errors.New("convictions max-points cannot be negative")
+	}
+	types := map[string]bool{}
+	for _, claim := range claims.Types {
+		if len(claim.Type) == 0 {
+			return errors.New("claim type cannot be empty")
+		}
+		if types[claim.Type] {
+			return fmt.Errorf("claim type %q is listed twice", claim.Type)
+		}
+		types[claim.Type] = true
+		if claim.LookbackYears < 0 {
+			return fmt.Errorf("claim type %q has a negative lookback-years", claim.Type)
+		}
+		if !claim.Decline && (claim.FaultFactor < 1 || claim.NonFaultFactor < 1) {
+			return fmt.Errorf("claim type %q loadings should be at least 1", claim.Type)
+		}
+	}
+	codes := map[string]bool{}
+	for _, conviction := range convictions.Codes {
+		if len(conviction.Code) == 0 {
+			return errors.New("conviction code cannot be empty")
+		}
+		if codes[conviction.Code] {
+			return fmt.Errorf("conviction code %q is listed twice", conviction.Code)
+		}
+		codes[conviction.Code] = true
+		if conviction.LookbackYears < 0 {
+			return fmt.Errorf("conviction code %q has a negative lookback-years", conviction.Code)
+		}
+		if !conviction.Decline && conviction.Factor < 1 {
+			return fmt.Errorf("conviction code %q loading should be at least 1", conviction.Code)
+		}
+	}
+	return nil
+}
+
+// validateCategorical checks a categorical table is usable by the pricing strategies
+func validateCategorical(categorical models.CategoricalFactor) error {
+	if len(categorical.Name) == 0 {
+		return errors.New("categorical name cannot be empty")
+	}
+	known := false
+	for _, candidate := range strategy.Attributes {
+		known = known || candidate == categorical.Attribute
+	}
+	if !known {
+		return fmt.Errorf("categorical %q has an unknown attribute %q, should be one of %s", categorical.Name, categorical.Attribute, strings.Join(strategy.Attributes, ", "))
+	}
+	if len(categorical.Entries) == 0 {
+		return fmt.Errorf("categorical %q cannot have empty entries", categorical.Name)
+	}
+	defaults := 0
+	for i, entry := range categorical.Entries {
+		ways := 0
+		for _, set := range []bool{len(entry.Value) > 0, len(entry.Values) > 0, len(entry.Pattern) > 0, entry.Default} {
+			if set {
+				ways++
+			}
+		}
+		if ways != 1 {
+			return fmt.Errorf("categorical %q entry %d should set exactly one of value, values, pattern or default", categorical.Name, i)
+		}
+		for _, value := range entry.Values {
+			if len(util.NormaliseCategory(value)) == 0 {
+				return fmt.Errorf("categorical %q entry %d has an empty value", categorical.Name, i)
+			}
+		}
+		if entry.Default {
+			defaults++
+		}
+		if err := validateFactor(fmt.Sprintf("categorical %q entry %d", categorical.Name, i), entry.Operation, entry.IsEligible, entry.Factor); err != nil {
+			return err
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("categorical %q can have only one default entry", categorical.Name)
+	}
+	return nil
+}
+
+// validateRanges checks the mapped ranges are usable by the pricing strategies
+func validateRanges(ranges []models.RangeConfig) error {
+	if len(ranges) == 0 {
+		return errors.New("factor list cannot be empty")
+	}
+	for _, r := range ranges {
+		if r.Start > r.End {
+			return fmt.Errorf("range %q starts after it ends", r.Label)
+		}
+		if err := validateFactor(fmt.Sprintf("range %q", r.Label), r.Operation, r.IsEligible, r.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
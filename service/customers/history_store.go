@@ -0,0 +1,133 @@
+package customers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"pricingengine"
+)
+
+// ErrInvalidCustomerID is returned for the customer IDs that can not name a history file
+var ErrInvalidCustomerID = errors.New("customer ID should be 1 to 64 letters, digits, '-' or '_'")
+
+// ErrInvalidRecord is wrapped by the errors returned for the policies and claims that can not be recorded
+var ErrInvalidRecord = errors.New("invalid customer record")
+
+var customerIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidID tells whether the customer ID can be stored, i.e. is made of 1 to 64 letters, digits, '-' or '_'
+func ValidID(id string) bool {
+	return customerIDPattern.MatchString(id)
+}
+
+// Store keeps the history of every customer in its own JSON file under Dir so it survives a restart
+// The files are written atomically, and the records of a same store are serialised
+type Store struct {
+	Dir string
+
+	mutex sync.Mutex
+}
+
+// Load method reads the history of the customer, an unknown customer having an empty history
+func (s *Store) Load(id string) (*pricingengine.CustomerHistory, error) {
+	if !ValidID(id) {
+		return nil, ErrInvalidCustomerID
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.load(id)
+}
+
+// RecordPolicy method adds a bound policy to the history of the customer
+// returns the updated history, or an error wrapping ErrInvalidRecord when the policy is not dated or in the future
+func (s *Store) RecordPolicy(id string, policy pricingengine.CustomerPolicy) (*pricingengine.CustomerHistory, error) {
+	date, err := time.Parse("2006-01-02", policy.BoundOn)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("%w: bound_on should be a date as YYYY-MM-DD", ErrInvalidRecord)
+	case date.After(time.Now()):
+		return nil, fmt.Errorf("%w: bound_on cannot be in the future", ErrInvalidRecord)
+	case policy.Premium < 0:
+		return nil, fmt.Errorf("%w: premium cannot be negative", ErrInvalidRecord)
+	}
+	return s.update(id, func(history *pricingengine.CustomerHistory) {
+		history.Policies = append(history.Policies, policy)
+	})
+}
+
+// RecordClaim method adds a claim to the history of the customer
+// returns the updated history, or an error wrapping ErrInvalidRecord when the claim is not dated, in the future or not typed
+func (s *Store) RecordClaim(id string, claim pricingengine.Claim) (*pricingengine.CustomerHistory, error) {
+	date, err := time.Parse("2006-01-02", claim.Date)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("%w: date should be a date as YYYY-MM-DD", ErrInvalidRecord)
+	case date.After(time.Now()):
+		return nil, fmt.Errorf("%w: date cannot be in the future", ErrInvalidRecord)
+	case len(strings.TrimSpace(claim.Type)) == 0:
+		return nil, fmt.Errorf("%w: type cannot be empty", ErrInvalidRecord)
+	}
+	return s.update(id, func(history *pricingengine.CustomerHistory) {
+		history.Claims = append(history.Claims, claim)
+	})
+}
+
+// update applies the change to the history of the customer and saves it
+func (s *Store) update(id string, change func(*pricingengine.CustomerHistory)) (*pricingengine.CustomerHistory, error) {
+	if !ValidID(id) {
+		return nil, ErrInvalidCustomerID
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	history, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	change(history)
+	return history, s.save(history)
+}
+
+// load reads the history file of the customer
+func (s *Store) load(id string) (*pricingengine.CustomerHistory, error) {
+	history := &pricingengine.CustomerHistory{CustomerID: id, Policies: []pricingengine.CustomerPolicy{}, Claims: []pricingengine.Claim{}}
+	data, err := ioutil.ReadFile(s.historyFile(id))
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// save atomically writes the history file of the customer
+func (s *Store) save(history *pricingengine.CustomerHistory) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	filename := s.historyFile(history.CustomerID)
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// historyFile returns the path of the history file of the customer
+func (s *Store) historyFile(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
@@ -0,0 +1,2843 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: service/grpcapi/pricingpb/pricing.proto
+
+package pricingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GeneratePricingRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	DateOfBirth         string                 `protobuf:"bytes,1,opt,name=date_of_birth,json=dateOfBirth,proto3" json:"date_of_birth,omitempty"`
+	InsuranceGroup      int32                  `protobuf:"varint,2,opt,name=insurance_group,json=insuranceGroup,proto3" json:"insurance_group,omitempty"`
+	LicenseHeldSince    string                 `protobuf:"bytes,3,opt,name=license_held_since,json=licenseHeldSince,proto3" json:"license_held_since,omitempty"`
+	Occupation          string                 `protobuf:"bytes,4,opt,name=occupation,proto3" json:"occupation,omitempty"`
+	FuelType            string                 `protobuf:"bytes,5,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	CoverPurpose        string                 `protobuf:"bytes,6,opt,name=cover_purpose,json=coverPurpose,proto3" json:"cover_purpose,omitempty"`
+	Postcode            string                 `protobuf:"bytes,7,opt,name=postcode,proto3" json:"postcode,omitempty"`
+	VehicleRegistration string                 `protobuf:"bytes,8,opt,name=vehicle_registration,json=vehicleRegistration,proto3" json:"vehicle_registration,omitempty"`
+	VehicleCode         string                 `protobuf:"bytes,9,opt,name=vehicle_code,json=vehicleCode,proto3" json:"vehicle_code,omitempty"`
+	Claims              []*Claim               `protobuf:"bytes,10,rep,name=claims,proto3" json:"claims,omitempty"`
+	Convictions         []*Conviction          `protobuf:"bytes,11,rep,name=convictions,proto3" json:"convictions,omitempty"`
+	CoverStart          string                 `protobuf:"bytes,12,opt,name=cover_start,json=coverStart,proto3" json:"cover_start,omitempty"`
+	PromoCode           string                 `protobuf:"bytes,13,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	CustomerId          string                 `protobuf:"bytes,14,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	AdditionalDrivers   []*Driver              `protobuf:"bytes,15,rep,name=additional_drivers,json=additionalDrivers,proto3" json:"additional_drivers,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GeneratePricingRequest) Reset() {
+	*x = GeneratePricingRequest{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePricingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePricingRequest) ProtoMessage() {}
+
+func (x *GeneratePricingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePricingRequest.ProtoReflect.Descriptor instead.
+func (*GeneratePricingRequest) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GeneratePricingRequest) GetDateOfBirth() string {
+	if x != nil {
+		return x.DateOfBirth
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetInsuranceGroup() int32 {
+	if x != nil {
+		return x.InsuranceGroup
+	}
+	return 0
+}
+
+func (x *GeneratePricingRequest) GetLicenseHeldSince() string {
+	if x != nil {
+		return x.LicenseHeldSince
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetOccupation() string {
+	if x != nil {
+		return x.Occupation
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetCoverPurpose() string {
+	if x != nil {
+		return x.CoverPurpose
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetPostcode() string {
+	if x != nil {
+		return x.Postcode
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetVehicleRegistration() string {
+	if x != nil {
+		return x.VehicleRegistration
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetVehicleCode() string {
+	if x != nil {
+		return x.VehicleCode
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetClaims() []*Claim {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+func (x *GeneratePricingRequest) GetConvictions() []*Conviction {
+	if x != nil {
+		return x.Convictions
+	}
+	return nil
+}
+
+func (x *GeneratePricingRequest) GetCoverStart() string {
+	if x != nil {
+		return x.CoverStart
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *GeneratePricingRequest) GetAdditionalDrivers() []*Driver {
+	if x != nil {
+		return x.AdditionalDrivers
+	}
+	return nil
+}
+
+type Driver struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DateOfBirth      string                 `protobuf:"bytes,2,opt,name=date_of_birth,json=dateOfBirth,proto3" json:"date_of_birth,omitempty"`
+	LicenseHeldSince string                 `protobuf:"bytes,3,opt,name=license_held_since,json=licenseHeldSince,proto3" json:"license_held_since,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Driver) Reset() {
+	*x = Driver{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Driver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Driver) ProtoMessage() {}
+
+func (x *Driver) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Driver.ProtoReflect.Descriptor instead.
+func (*Driver) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Driver) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Driver) GetDateOfBirth() string {
+	if x != nil {
+		return x.DateOfBirth
+	}
+	return ""
+}
+
+func (x *Driver) GetLicenseHeldSince() string {
+	if x != nil {
+		return x.LicenseHeldSince
+	}
+	return ""
+}
+
+type Claim struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Fault         bool                   `protobuf:"varint,3,opt,name=fault,proto3" json:"fault,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Claim) Reset() {
+	*x = Claim{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Claim) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Claim) ProtoMessage() {}
+
+func (x *Claim) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Claim.ProtoReflect.Descriptor instead.
+func (*Claim) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Claim) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Claim) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Claim) GetFault() bool {
+	if x != nil {
+		return x.Fault
+	}
+	return false
+}
+
+type Conviction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Points        int32                  `protobuf:"varint,3,opt,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conviction) Reset() {
+	*x = Conviction{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conviction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conviction) ProtoMessage() {}
+
+func (x *Conviction) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conviction.ProtoReflect.Descriptor instead.
+func (*Conviction) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Conviction) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Conviction) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Conviction) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+type PricingItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Premium       float64                `protobuf:"fixed64,1,opt,name=premium,proto3" json:"premium,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	FareGroup     string                 `protobuf:"bytes,3,opt,name=fare_group,json=fareGroup,proto3" json:"fare_group,omitempty"`
+	Breakdown     []*PricingStep         `protobuf:"bytes,4,rep,name=breakdown,proto3" json:"breakdown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricingItem) Reset() {
+	*x = PricingItem{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricingItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricingItem) ProtoMessage() {}
+
+func (x *PricingItem) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricingItem.ProtoReflect.Descriptor instead.
+func (*PricingItem) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PricingItem) GetPremium() float64 {
+	if x != nil {
+		return x.Premium
+	}
+	return 0
+}
+
+func (x *PricingItem) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PricingItem) GetFareGroup() string {
+	if x != nil {
+		return x.FareGroup
+	}
+	return ""
+}
+
+func (x *PricingItem) GetBreakdown() []*PricingStep {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+type PricingStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Factor        float64                `protobuf:"fixed64,2,opt,name=factor,proto3" json:"factor,omitempty"`
+	Premium       float64                `protobuf:"fixed64,3,opt,name=premium,proto3" json:"premium,omitempty"`
+	Operation     string                 `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricingStep) Reset() {
+	*x = PricingStep{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricingStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricingStep) ProtoMessage() {}
+
+func (x *PricingStep) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricingStep.ProtoReflect.Descriptor instead.
+func (*PricingStep) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PricingStep) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *PricingStep) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *PricingStep) GetPremium() float64 {
+	if x != nil {
+		return x.Premium
+	}
+	return 0
+}
+
+func (x *PricingStep) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type GeneratePricingResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Input         *GeneratePricingRequest `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	IsEligible    bool                    `protobuf:"varint,2,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Message       string                  `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Pricing       []*PricingItem          `protobuf:"bytes,4,rep,name=pricing,proto3" json:"pricing,omitempty"`
+	Vehicle       *Vehicle                `protobuf:"bytes,5,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	Promo         *Promo                  `protobuf:"bytes,6,opt,name=promo,proto3" json:"promo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratePricingResponse) Reset() {
+	*x = GeneratePricingResponse{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePricingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePricingResponse) ProtoMessage() {}
+
+func (x *GeneratePricingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePricingResponse.ProtoReflect.Descriptor instead.
+func (*GeneratePricingResponse) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GeneratePricingResponse) GetInput() *GeneratePricingRequest {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *GeneratePricingResponse) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *GeneratePricingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GeneratePricingResponse) GetPricing() []*PricingItem {
+	if x != nil {
+		return x.Pricing
+	}
+	return nil
+}
+
+func (x *GeneratePricingResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *GeneratePricingResponse) GetPromo() *Promo {
+	if x != nil {
+		return x.Promo
+	}
+	return nil
+}
+
+type Promo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Applied       bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Promo) Reset() {
+	*x = Promo{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Promo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Promo) ProtoMessage() {}
+
+func (x *Promo) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Promo.ProtoReflect.Descriptor instead.
+func (*Promo) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Promo) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Promo) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *Promo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Vehicle struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Registration   string                 `protobuf:"bytes,1,opt,name=registration,proto3" json:"registration,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Make           string                 `protobuf:"bytes,3,opt,name=make,proto3" json:"make,omitempty"`
+	Model          string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
+	InsuranceGroup int32                  `protobuf:"varint,5,opt,name=insurance_group,json=insuranceGroup,proto3" json:"insurance_group,omitempty"`
+	Value          float64                `protobuf:"fixed64,6,opt,name=value,proto3" json:"value,omitempty"`
+	Age            int32                  `protobuf:"varint,7,opt,name=age,proto3" json:"age,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Vehicle) GetRegistration() string {
+	if x != nil {
+		return x.Registration
+	}
+	return ""
+}
+
+func (x *Vehicle) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetInsuranceGroup() int32 {
+	if x != nil {
+		return x.InsuranceGroup
+	}
+	return 0
+}
+
+func (x *Vehicle) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Vehicle) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+type GenerateBatchPricingRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Requests      []*GeneratePricingRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchPricingRequest) Reset() {
+	*x = GenerateBatchPricingRequest{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchPricingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchPricingRequest) ProtoMessage() {}
+
+func (x *GenerateBatchPricingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchPricingRequest.ProtoReflect.Descriptor instead.
+func (*GenerateBatchPricingRequest) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GenerateBatchPricingRequest) GetRequests() []*GeneratePricingRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type GenerateBatchPricingResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Responses     []*GeneratePricingResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchPricingResponse) Reset() {
+	*x = GenerateBatchPricingResponse{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchPricingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchPricingResponse) ProtoMessage() {}
+
+func (x *GenerateBatchPricingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchPricingResponse.ProtoReflect.Descriptor instead.
+func (*GenerateBatchPricingResponse) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GenerateBatchPricingResponse) GetResponses() []*GeneratePricingResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type GetPricingConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPricingConfigRequest) Reset() {
+	*x = GetPricingConfigRequest{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPricingConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPricingConfigRequest) ProtoMessage() {}
+
+func (x *GetPricingConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPricingConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetPricingConfigRequest) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{11}
+}
+
+type RangeConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,3,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeConfig) Reset() {
+	*x = RangeConfig{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeConfig) ProtoMessage() {}
+
+func (x *RangeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeConfig.ProtoReflect.Descriptor instead.
+func (*RangeConfig) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RangeConfig) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *RangeConfig) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *RangeConfig) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *RangeConfig) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *RangeConfig) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *RangeConfig) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type PricingConfig struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Version               int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	BaseRate              []*RangeConfig         `protobuf:"bytes,2,rep,name=base_rate,json=baseRate,proto3" json:"base_rate,omitempty"`
+	DriverAgeFactor       []*RangeConfig         `protobuf:"bytes,3,rep,name=driver_age_factor,json=driverAgeFactor,proto3" json:"driver_age_factor,omitempty"`
+	InsuranceGroupFactor  []*RangeConfig         `protobuf:"bytes,4,rep,name=insurance_group_factor,json=insuranceGroupFactor,proto3" json:"insurance_group_factor,omitempty"`
+	LicenceValidityFactor []*RangeConfig         `protobuf:"bytes,5,rep,name=licence_validity_factor,json=licenceValidityFactor,proto3" json:"licence_validity_factor,omitempty"`
+	InteractionFactors    []*InteractionTable    `protobuf:"bytes,6,rep,name=interaction_factors,json=interactionFactors,proto3" json:"interaction_factors,omitempty"`
+	CategoricalFactors    []*CategoricalTable    `protobuf:"bytes,7,rep,name=categorical_factors,json=categoricalFactors,proto3" json:"categorical_factors,omitempty"`
+	PostcodeFactor        []*PostcodeRange       `protobuf:"bytes,8,rep,name=postcode_factor,json=postcodeFactor,proto3" json:"postcode_factor,omitempty"`
+	HistoryLoadings       *HistoryLoadings       `protobuf:"bytes,9,opt,name=history_loadings,json=historyLoadings,proto3" json:"history_loadings,omitempty"`
+	TemporalFactors       *TemporalFactors       `protobuf:"bytes,10,opt,name=temporal_factors,json=temporalFactors,proto3" json:"temporal_factors,omitempty"`
+	Holidays              []*Holiday             `protobuf:"bytes,11,rep,name=holidays,proto3" json:"holidays,omitempty"`
+	PromoCodes            []*PromoCode           `protobuf:"bytes,12,rep,name=promo_codes,json=promoCodes,proto3" json:"promo_codes,omitempty"`
+	LoyaltyFactor         *LoyaltyFactor         `protobuf:"bytes,13,opt,name=loyalty_factor,json=loyaltyFactor,proto3" json:"loyalty_factor,omitempty"`
+	DriverCombination     *DriverCombination     `protobuf:"bytes,14,opt,name=driver_combination,json=driverCombination,proto3" json:"driver_combination,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *PricingConfig) Reset() {
+	*x = PricingConfig{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricingConfig) ProtoMessage() {}
+
+func (x *PricingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricingConfig.ProtoReflect.Descriptor instead.
+func (*PricingConfig) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PricingConfig) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PricingConfig) GetBaseRate() []*RangeConfig {
+	if x != nil {
+		return x.BaseRate
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetDriverAgeFactor() []*RangeConfig {
+	if x != nil {
+		return x.DriverAgeFactor
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetInsuranceGroupFactor() []*RangeConfig {
+	if x != nil {
+		return x.InsuranceGroupFactor
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetLicenceValidityFactor() []*RangeConfig {
+	if x != nil {
+		return x.LicenceValidityFactor
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetInteractionFactors() []*InteractionTable {
+	if x != nil {
+		return x.InteractionFactors
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetCategoricalFactors() []*CategoricalTable {
+	if x != nil {
+		return x.CategoricalFactors
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetPostcodeFactor() []*PostcodeRange {
+	if x != nil {
+		return x.PostcodeFactor
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetHistoryLoadings() *HistoryLoadings {
+	if x != nil {
+		return x.HistoryLoadings
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetTemporalFactors() *TemporalFactors {
+	if x != nil {
+		return x.TemporalFactors
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetHolidays() []*Holiday {
+	if x != nil {
+		return x.Holidays
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetPromoCodes() []*PromoCode {
+	if x != nil {
+		return x.PromoCodes
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetLoyaltyFactor() *LoyaltyFactor {
+	if x != nil {
+		return x.LoyaltyFactor
+	}
+	return nil
+}
+
+func (x *PricingConfig) GetDriverCombination() *DriverCombination {
+	if x != nil {
+		return x.DriverCombination
+	}
+	return nil
+}
+
+type DriverCombination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	MainWeight    float64                `protobuf:"fixed64,2,opt,name=main_weight,json=mainWeight,proto3" json:"main_weight,omitempty"`
+	Loading       *DriverLoading         `protobuf:"bytes,3,opt,name=loading,proto3" json:"loading,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverCombination) Reset() {
+	*x = DriverCombination{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverCombination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverCombination) ProtoMessage() {}
+
+func (x *DriverCombination) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverCombination.ProtoReflect.Descriptor instead.
+func (*DriverCombination) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DriverCombination) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *DriverCombination) GetMainWeight() float64 {
+	if x != nil {
+		return x.MainWeight
+	}
+	return 0
+}
+
+func (x *DriverCombination) GetLoading() *DriverLoading {
+	if x != nil {
+		return x.Loading
+	}
+	return nil
+}
+
+type DriverLoading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Factor        float64                `protobuf:"fixed64,1,opt,name=factor,proto3" json:"factor,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverLoading) Reset() {
+	*x = DriverLoading{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverLoading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverLoading) ProtoMessage() {}
+
+func (x *DriverLoading) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverLoading.ProtoReflect.Descriptor instead.
+func (*DriverLoading) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DriverLoading) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *DriverLoading) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *DriverLoading) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type LoyaltyFactor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Basis         string                 `protobuf:"bytes,1,opt,name=basis,proto3" json:"basis,omitempty"`
+	Bands         []*LoyaltyBand         `protobuf:"bytes,2,rep,name=bands,proto3" json:"bands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoyaltyFactor) Reset() {
+	*x = LoyaltyFactor{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoyaltyFactor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoyaltyFactor) ProtoMessage() {}
+
+func (x *LoyaltyFactor) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoyaltyFactor.ProtoReflect.Descriptor instead.
+func (*LoyaltyFactor) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LoyaltyFactor) GetBasis() string {
+	if x != nil {
+		return x.Basis
+	}
+	return ""
+}
+
+func (x *LoyaltyFactor) GetBands() []*LoyaltyBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+type LoyaltyBand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int32                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Factor        float64                `protobuf:"fixed64,2,opt,name=factor,proto3" json:"factor,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoyaltyBand) Reset() {
+	*x = LoyaltyBand{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoyaltyBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoyaltyBand) ProtoMessage() {}
+
+func (x *LoyaltyBand) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoyaltyBand.ProtoReflect.Descriptor instead.
+func (*LoyaltyBand) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LoyaltyBand) GetFrom() int32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *LoyaltyBand) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *LoyaltyBand) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *LoyaltyBand) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type PromoCode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	ValidFrom     string                 `protobuf:"bytes,4,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo       string                 `protobuf:"bytes,5,opt,name=valid_to,json=validTo,proto3" json:"valid_to,omitempty"`
+	Durations     []string               `protobuf:"bytes,6,rep,name=durations,proto3" json:"durations,omitempty"`
+	MinPremium    float64                `protobuf:"fixed64,7,opt,name=min_premium,json=minPremium,proto3" json:"min_premium,omitempty"`
+	MaxUses       int32                  `protobuf:"varint,8,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	Label         string                 `protobuf:"bytes,9,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoCode) Reset() {
+	*x = PromoCode{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoCode) ProtoMessage() {}
+
+func (x *PromoCode) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoCode.ProtoReflect.Descriptor instead.
+func (*PromoCode) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PromoCode) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PromoCode) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PromoCode) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *PromoCode) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *PromoCode) GetValidTo() string {
+	if x != nil {
+		return x.ValidTo
+	}
+	return ""
+}
+
+func (x *PromoCode) GetDurations() []string {
+	if x != nil {
+		return x.Durations
+	}
+	return nil
+}
+
+func (x *PromoCode) GetMinPremium() float64 {
+	if x != nil {
+		return x.MinPremium
+	}
+	return 0
+}
+
+func (x *PromoCode) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *PromoCode) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type TemporalFactors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timezone      string                 `protobuf:"bytes,1,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Hours         []*HourFactor          `protobuf:"bytes,2,rep,name=hours,proto3" json:"hours,omitempty"`
+	Days          []*DayFactor           `protobuf:"bytes,3,rep,name=days,proto3" json:"days,omitempty"`
+	Holiday       *HolidayFactor         `protobuf:"bytes,4,opt,name=holiday,proto3" json:"holiday,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemporalFactors) Reset() {
+	*x = TemporalFactors{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemporalFactors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemporalFactors) ProtoMessage() {}
+
+func (x *TemporalFactors) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemporalFactors.ProtoReflect.Descriptor instead.
+func (*TemporalFactors) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TemporalFactors) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *TemporalFactors) GetHours() []*HourFactor {
+	if x != nil {
+		return x.Hours
+	}
+	return nil
+}
+
+func (x *TemporalFactors) GetDays() []*DayFactor {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *TemporalFactors) GetHoliday() *HolidayFactor {
+	if x != nil {
+		return x.Holiday
+	}
+	return nil
+}
+
+type HourFactor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int32                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            int32                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,3,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Factor        float64                `protobuf:"fixed64,4,opt,name=factor,proto3" json:"factor,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HourFactor) Reset() {
+	*x = HourFactor{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HourFactor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HourFactor) ProtoMessage() {}
+
+func (x *HourFactor) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HourFactor.ProtoReflect.Descriptor instead.
+func (*HourFactor) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HourFactor) GetFrom() int32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *HourFactor) GetTo() int32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *HourFactor) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *HourFactor) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *HourFactor) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *HourFactor) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type DayFactor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Days          []string               `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,2,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Factor        float64                `protobuf:"fixed64,3,opt,name=factor,proto3" json:"factor,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayFactor) Reset() {
+	*x = DayFactor{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayFactor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayFactor) ProtoMessage() {}
+
+func (x *DayFactor) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayFactor.ProtoReflect.Descriptor instead.
+func (*DayFactor) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DayFactor) GetDays() []string {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *DayFactor) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *DayFactor) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *DayFactor) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *DayFactor) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type HolidayFactor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsEligible    bool                   `protobuf:"varint,1,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Factor        float64                `protobuf:"fixed64,2,opt,name=factor,proto3" json:"factor,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HolidayFactor) Reset() {
+	*x = HolidayFactor{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HolidayFactor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HolidayFactor) ProtoMessage() {}
+
+func (x *HolidayFactor) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HolidayFactor.ProtoReflect.Descriptor instead.
+func (*HolidayFactor) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HolidayFactor) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *HolidayFactor) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *HolidayFactor) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *HolidayFactor) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type Holiday struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Holiday) Reset() {
+	*x = Holiday{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Holiday) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Holiday) ProtoMessage() {}
+
+func (x *Holiday) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Holiday.ProtoReflect.Descriptor instead.
+func (*Holiday) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Holiday) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Holiday) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type HistoryLoadings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Claims        *ClaimLoadings         `protobuf:"bytes,1,opt,name=claims,proto3" json:"claims,omitempty"`
+	Convictions   *ConvictionLoadings    `protobuf:"bytes,2,opt,name=convictions,proto3" json:"convictions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryLoadings) Reset() {
+	*x = HistoryLoadings{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryLoadings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryLoadings) ProtoMessage() {}
+
+func (x *HistoryLoadings) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryLoadings.ProtoReflect.Descriptor instead.
+func (*HistoryLoadings) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *HistoryLoadings) GetClaims() *ClaimLoadings {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+func (x *HistoryLoadings) GetConvictions() *ConvictionLoadings {
+	if x != nil {
+		return x.Convictions
+	}
+	return nil
+}
+
+type ClaimLoadings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LookbackYears int32                  `protobuf:"varint,1,opt,name=lookback_years,json=lookbackYears,proto3" json:"lookback_years,omitempty"`
+	MaxLoading    float64                `protobuf:"fixed64,2,opt,name=max_loading,json=maxLoading,proto3" json:"max_loading,omitempty"`
+	Types         []*ClaimLoading        `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimLoadings) Reset() {
+	*x = ClaimLoadings{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimLoadings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimLoadings) ProtoMessage() {}
+
+func (x *ClaimLoadings) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimLoadings.ProtoReflect.Descriptor instead.
+func (*ClaimLoadings) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ClaimLoadings) GetLookbackYears() int32 {
+	if x != nil {
+		return x.LookbackYears
+	}
+	return 0
+}
+
+func (x *ClaimLoadings) GetMaxLoading() float64 {
+	if x != nil {
+		return x.MaxLoading
+	}
+	return 0
+}
+
+func (x *ClaimLoadings) GetTypes() []*ClaimLoading {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type ClaimLoading struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	LookbackYears  int32                  `protobuf:"varint,2,opt,name=lookback_years,json=lookbackYears,proto3" json:"lookback_years,omitempty"`
+	FaultFactor    float64                `protobuf:"fixed64,3,opt,name=fault_factor,json=faultFactor,proto3" json:"fault_factor,omitempty"`
+	NonFaultFactor float64                `protobuf:"fixed64,4,opt,name=non_fault_factor,json=nonFaultFactor,proto3" json:"non_fault_factor,omitempty"`
+	Decline        bool                   `protobuf:"varint,5,opt,name=decline,proto3" json:"decline,omitempty"`
+	Label          string                 `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClaimLoading) Reset() {
+	*x = ClaimLoading{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimLoading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimLoading) ProtoMessage() {}
+
+func (x *ClaimLoading) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimLoading.ProtoReflect.Descriptor instead.
+func (*ClaimLoading) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ClaimLoading) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ClaimLoading) GetLookbackYears() int32 {
+	if x != nil {
+		return x.LookbackYears
+	}
+	return 0
+}
+
+func (x *ClaimLoading) GetFaultFactor() float64 {
+	if x != nil {
+		return x.FaultFactor
+	}
+	return 0
+}
+
+func (x *ClaimLoading) GetNonFaultFactor() float64 {
+	if x != nil {
+		return x.NonFaultFactor
+	}
+	return 0
+}
+
+func (x *ClaimLoading) GetDecline() bool {
+	if x != nil {
+		return x.Decline
+	}
+	return false
+}
+
+func (x *ClaimLoading) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ConvictionLoadings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LookbackYears int32                  `protobuf:"varint,1,opt,name=lookback_years,json=lookbackYears,proto3" json:"lookback_years,omitempty"`
+	MaxLoading    float64                `protobuf:"fixed64,2,opt,name=max_loading,json=maxLoading,proto3" json:"max_loading,omitempty"`
+	MaxPoints     int32                  `protobuf:"varint,3,opt,name=max_points,json=maxPoints,proto3" json:"max_points,omitempty"`
+	Codes         []*ConvictionLoading   `protobuf:"bytes,4,rep,name=codes,proto3" json:"codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvictionLoadings) Reset() {
+	*x = ConvictionLoadings{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvictionLoadings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvictionLoadings) ProtoMessage() {}
+
+func (x *ConvictionLoadings) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvictionLoadings.ProtoReflect.Descriptor instead.
+func (*ConvictionLoadings) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ConvictionLoadings) GetLookbackYears() int32 {
+	if x != nil {
+		return x.LookbackYears
+	}
+	return 0
+}
+
+func (x *ConvictionLoadings) GetMaxLoading() float64 {
+	if x != nil {
+		return x.MaxLoading
+	}
+	return 0
+}
+
+func (x *ConvictionLoadings) GetMaxPoints() int32 {
+	if x != nil {
+		return x.MaxPoints
+	}
+	return 0
+}
+
+func (x *ConvictionLoadings) GetCodes() []*ConvictionLoading {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+type ConvictionLoading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	LookbackYears int32                  `protobuf:"varint,2,opt,name=lookback_years,json=lookbackYears,proto3" json:"lookback_years,omitempty"`
+	Factor        float64                `protobuf:"fixed64,3,opt,name=factor,proto3" json:"factor,omitempty"`
+	Decline       bool                   `protobuf:"varint,4,opt,name=decline,proto3" json:"decline,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvictionLoading) Reset() {
+	*x = ConvictionLoading{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvictionLoading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvictionLoading) ProtoMessage() {}
+
+func (x *ConvictionLoading) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvictionLoading.ProtoReflect.Descriptor instead.
+func (*ConvictionLoading) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ConvictionLoading) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ConvictionLoading) GetLookbackYears() int32 {
+	if x != nil {
+		return x.LookbackYears
+	}
+	return 0
+}
+
+func (x *ConvictionLoading) GetFactor() float64 {
+	if x != nil {
+		return x.Factor
+	}
+	return 0
+}
+
+func (x *ConvictionLoading) GetDecline() bool {
+	if x != nil {
+		return x.Decline
+	}
+	return false
+}
+
+func (x *ConvictionLoading) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type PostcodeRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,2,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostcodeRange) Reset() {
+	*x = PostcodeRange{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostcodeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostcodeRange) ProtoMessage() {}
+
+func (x *PostcodeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostcodeRange.ProtoReflect.Descriptor instead.
+func (*PostcodeRange) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PostcodeRange) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *PostcodeRange) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *PostcodeRange) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *PostcodeRange) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *PostcodeRange) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type InteractionTable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Dimensions    []string               `protobuf:"bytes,2,rep,name=dimensions,proto3" json:"dimensions,omitempty"`
+	Cells         []*InteractionRange    `protobuf:"bytes,3,rep,name=cells,proto3" json:"cells,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InteractionTable) Reset() {
+	*x = InteractionTable{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InteractionTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InteractionTable) ProtoMessage() {}
+
+func (x *InteractionTable) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InteractionTable.ProtoReflect.Descriptor instead.
+func (*InteractionTable) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *InteractionTable) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InteractionTable) GetDimensions() []string {
+	if x != nil {
+		return x.Dimensions
+	}
+	return nil
+}
+
+func (x *InteractionTable) GetCells() []*InteractionRange {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+type InteractionRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         []int64                `protobuf:"varint,1,rep,packed,name=start,proto3" json:"start,omitempty"`
+	End           []int64                `protobuf:"varint,2,rep,packed,name=end,proto3" json:"end,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,3,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InteractionRange) Reset() {
+	*x = InteractionRange{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InteractionRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InteractionRange) ProtoMessage() {}
+
+func (x *InteractionRange) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InteractionRange.ProtoReflect.Descriptor instead.
+func (*InteractionRange) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *InteractionRange) GetStart() []int64 {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *InteractionRange) GetEnd() []int64 {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *InteractionRange) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *InteractionRange) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *InteractionRange) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *InteractionRange) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+type CategoricalTable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Attribute     string                 `protobuf:"bytes,2,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	Entries       []*CategoricalRange    `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	Default       *CategoricalRange      `protobuf:"bytes,4,opt,name=default,proto3" json:"default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoricalTable) Reset() {
+	*x = CategoricalTable{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoricalTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoricalTable) ProtoMessage() {}
+
+func (x *CategoricalTable) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoricalTable.ProtoReflect.Descriptor instead.
+func (*CategoricalTable) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CategoricalTable) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoricalTable) GetAttribute() string {
+	if x != nil {
+		return x.Attribute
+	}
+	return ""
+}
+
+func (x *CategoricalTable) GetEntries() []*CategoricalRange {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *CategoricalTable) GetDefault() *CategoricalRange {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+type CategoricalRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	Pattern       string                 `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	IsEligible    bool                   `protobuf:"varint,3,opt,name=is_eligible,json=isEligible,proto3" json:"is_eligible,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Label         string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`
+	Operation     string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoricalRange) Reset() {
+	*x = CategoricalRange{}
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoricalRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoricalRange) ProtoMessage() {}
+
+func (x *CategoricalRange) ProtoReflect() protoreflect.Message {
+	mi := &file_service_grpcapi_pricingpb_pricing_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoricalRange.ProtoReflect.Descriptor instead.
+func (*CategoricalRange) Descriptor() ([]byte, []int) {
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CategoricalRange) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *CategoricalRange) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *CategoricalRange) GetIsEligible() bool {
+	if x != nil {
+		return x.IsEligible
+	}
+	return false
+}
+
+func (x *CategoricalRange) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CategoricalRange) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *CategoricalRange) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+var File_service_grpcapi_pricingpb_pricing_proto protoreflect.FileDescriptor
+
+const file_service_grpcapi_pricingpb_pricing_proto_rawDesc = "" +
+	"\n" +
+	"'service/grpcapi/pricingpb/pricing.proto\x12\x10pricingengine.v1\"\x82\x05\n" +
+	"\x16GeneratePricingRequest\x12\"\n" +
+	"\rdate_of_birth\x18\x01 \x01(\tR\vdateOfBirth\x12'\n" +
+	"\x0finsurance_group\x18\x02 \x01(\x05R\x0einsuranceGroup\x12,\n" +
+	"\x12license_held_since\x18\x03 \x01(\tR\x10licenseHeldSince\x12\x1e\n" +
+	"\n" +
+	"occupation\x18\x04 \x01(\tR\n" +
+	"occupation\x12\x1b\n" +
+	"\tfuel_type\x18\x05 \x01(\tR\bfuelType\x12#\n" +
+	"\rcover_purpose\x18\x06 \x01(\tR\fcoverPurpose\x12\x1a\n" +
+	"\bpostcode\x18\a \x01(\tR\bpostcode\x121\n" +
+	"\x14vehicle_registration\x18\b \x01(\tR\x13vehicleRegistration\x12!\n" +
+	"\fvehicle_code\x18\t \x01(\tR\vvehicleCode\x12/\n" +
+	"\x06claims\x18\n" +
+	" \x03(\v2\x17.pricingengine.v1.ClaimR\x06claims\x12>\n" +
+	"\vconvictions\x18\v \x03(\v2\x1c.pricingengine.v1.ConvictionR\vconvictions\x12\x1f\n" +
+	"\vcover_start\x18\f \x01(\tR\n" +
+	"coverStart\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\r \x01(\tR\tpromoCode\x12\x1f\n" +
+	"\vcustomer_id\x18\x0e \x01(\tR\n" +
+	"customerId\x12G\n" +
+	"\x12additional_drivers\x18\x0f \x03(\v2\x18.pricingengine.v1.DriverR\x11additionalDrivers\"n\n" +
+	"\x06Driver\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\rdate_of_birth\x18\x02 \x01(\tR\vdateOfBirth\x12,\n" +
+	"\x12license_held_since\x18\x03 \x01(\tR\x10licenseHeldSince\"E\n" +
+	"\x05Claim\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05fault\x18\x03 \x01(\bR\x05fault\"L\n" +
+	"\n" +
+	"Conviction\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x16\n" +
+	"\x06points\x18\x03 \x01(\x05R\x06points\"\x9f\x01\n" +
+	"\vPricingItem\x12\x18\n" +
+	"\apremium\x18\x01 \x01(\x01R\apremium\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"fare_group\x18\x03 \x01(\tR\tfareGroup\x12;\n" +
+	"\tbreakdown\x18\x04 \x03(\v2\x1d.pricingengine.v1.PricingStepR\tbreakdown\"s\n" +
+	"\vPricingStep\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x16\n" +
+	"\x06factor\x18\x02 \x01(\x01R\x06factor\x12\x18\n" +
+	"\apremium\x18\x03 \x01(\x01R\apremium\x12\x1c\n" +
+	"\toperation\x18\x04 \x01(\tR\toperation\"\xb1\x02\n" +
+	"\x17GeneratePricingResponse\x12>\n" +
+	"\x05input\x18\x01 \x01(\v2(.pricingengine.v1.GeneratePricingRequestR\x05input\x12\x1f\n" +
+	"\vis_eligible\x18\x02 \x01(\bR\n" +
+	"isEligible\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x127\n" +
+	"\apricing\x18\x04 \x03(\v2\x1d.pricingengine.v1.PricingItemR\apricing\x123\n" +
+	"\avehicle\x18\x05 \x01(\v2\x19.pricingengine.v1.VehicleR\avehicle\x12-\n" +
+	"\x05promo\x18\x06 \x01(\v2\x17.pricingengine.v1.PromoR\x05promo\"M\n" +
+	"\x05Promo\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xbc\x01\n" +
+	"\aVehicle\x12\"\n" +
+	"\fregistration\x18\x01 \x01(\tR\fregistration\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04make\x18\x03 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12'\n" +
+	"\x0finsurance_group\x18\x05 \x01(\x05R\x0einsuranceGroup\x12\x14\n" +
+	"\x05value\x18\x06 \x01(\x01R\x05value\x12\x10\n" +
+	"\x03age\x18\a \x01(\x05R\x03age\"c\n" +
+	"\x1bGenerateBatchPricingRequest\x12D\n" +
+	"\brequests\x18\x01 \x03(\v2(.pricingengine.v1.GeneratePricingRequestR\brequests\"g\n" +
+	"\x1cGenerateBatchPricingResponse\x12G\n" +
+	"\tresponses\x18\x01 \x03(\v2).pricingengine.v1.GeneratePricingResponseR\tresponses\"\x19\n" +
+	"\x17GetPricingConfigRequest\"\xa0\x01\n" +
+	"\vRangeConfig\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\x12\x1f\n" +
+	"\vis_eligible\x18\x03 \x01(\bR\n" +
+	"isEligible\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation\"\xfd\a\n" +
+	"\rPricingConfig\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12:\n" +
+	"\tbase_rate\x18\x02 \x03(\v2\x1d.pricingengine.v1.RangeConfigR\bbaseRate\x12I\n" +
+	"\x11driver_age_factor\x18\x03 \x03(\v2\x1d.pricingengine.v1.RangeConfigR\x0fdriverAgeFactor\x12S\n" +
+	"\x16insurance_group_factor\x18\x04 \x03(\v2\x1d.pricingengine.v1.RangeConfigR\x14insuranceGroupFactor\x12U\n" +
+	"\x17licence_validity_factor\x18\x05 \x03(\v2\x1d.pricingengine.v1.RangeConfigR\x15licenceValidityFactor\x12S\n" +
+	"\x13interaction_factors\x18\x06 \x03(\v2\".pricingengine.v1.InteractionTableR\x12interactionFactors\x12S\n" +
+	"\x13categorical_factors\x18\a \x03(\v2\".pricingengine.v1.CategoricalTableR\x12categoricalFactors\x12H\n" +
+	"\x0fpostcode_factor\x18\b \x03(\v2\x1f.pricingengine.v1.PostcodeRangeR\x0epostcodeFactor\x12L\n" +
+	"\x10history_loadings\x18\t \x01(\v2!.pricingengine.v1.HistoryLoadingsR\x0fhistoryLoadings\x12L\n" +
+	"\x10temporal_factors\x18\n" +
+	" \x01(\v2!.pricingengine.v1.TemporalFactorsR\x0ftemporalFactors\x125\n" +
+	"\bholidays\x18\v \x03(\v2\x19.pricingengine.v1.HolidayR\bholidays\x12<\n" +
+	"\vpromo_codes\x18\f \x03(\v2\x1b.pricingengine.v1.PromoCodeR\n" +
+	"promoCodes\x12F\n" +
+	"\x0eloyalty_factor\x18\r \x01(\v2\x1f.pricingengine.v1.LoyaltyFactorR\rloyaltyFactor\x12R\n" +
+	"\x12driver_combination\x18\x0e \x01(\v2#.pricingengine.v1.DriverCombinationR\x11driverCombination\"\x83\x01\n" +
+	"\x11DriverCombination\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x1f\n" +
+	"\vmain_weight\x18\x02 \x01(\x01R\n" +
+	"mainWeight\x129\n" +
+	"\aloading\x18\x03 \x01(\v2\x1f.pricingengine.v1.DriverLoadingR\aloading\"[\n" +
+	"\rDriverLoading\x12\x16\n" +
+	"\x06factor\x18\x01 \x01(\x01R\x06factor\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\"Z\n" +
+	"\rLoyaltyFactor\x12\x14\n" +
+	"\x05basis\x18\x01 \x01(\tR\x05basis\x123\n" +
+	"\x05bands\x18\x02 \x03(\v2\x1d.pricingengine.v1.LoyaltyBandR\x05bands\"m\n" +
+	"\vLoyaltyBand\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x05R\x04from\x12\x16\n" +
+	"\x06factor\x18\x02 \x01(\x01R\x06factor\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x04 \x01(\tR\toperation\"\xf3\x01\n" +
+	"\tPromoCode\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12\x1d\n" +
+	"\n" +
+	"valid_from\x18\x04 \x01(\tR\tvalidFrom\x12\x19\n" +
+	"\bvalid_to\x18\x05 \x01(\tR\avalidTo\x12\x1c\n" +
+	"\tdurations\x18\x06 \x03(\tR\tdurations\x12\x1f\n" +
+	"\vmin_premium\x18\a \x01(\x01R\n" +
+	"minPremium\x12\x19\n" +
+	"\bmax_uses\x18\b \x01(\x05R\amaxUses\x12\x14\n" +
+	"\x05label\x18\t \x01(\tR\x05label\"\xcd\x01\n" +
+	"\x0fTemporalFactors\x12\x1a\n" +
+	"\btimezone\x18\x01 \x01(\tR\btimezone\x122\n" +
+	"\x05hours\x18\x02 \x03(\v2\x1c.pricingengine.v1.HourFactorR\x05hours\x12/\n" +
+	"\x04days\x18\x03 \x03(\v2\x1b.pricingengine.v1.DayFactorR\x04days\x129\n" +
+	"\aholiday\x18\x04 \x01(\v2\x1f.pricingengine.v1.HolidayFactorR\aholiday\"\x9d\x01\n" +
+	"\n" +
+	"HourFactor\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x05R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x05R\x02to\x12\x1f\n" +
+	"\vis_eligible\x18\x03 \x01(\bR\n" +
+	"isEligible\x12\x16\n" +
+	"\x06factor\x18\x04 \x01(\x01R\x06factor\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation\"\x8c\x01\n" +
+	"\tDayFactor\x12\x12\n" +
+	"\x04days\x18\x01 \x03(\tR\x04days\x12\x1f\n" +
+	"\vis_eligible\x18\x02 \x01(\bR\n" +
+	"isEligible\x12\x16\n" +
+	"\x06factor\x18\x03 \x01(\x01R\x06factor\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x05 \x01(\tR\toperation\"|\n" +
+	"\rHolidayFactor\x12\x1f\n" +
+	"\vis_eligible\x18\x01 \x01(\bR\n" +
+	"isEligible\x12\x16\n" +
+	"\x06factor\x18\x02 \x01(\x01R\x06factor\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x04 \x01(\tR\toperation\"1\n" +
+	"\aHoliday\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x92\x01\n" +
+	"\x0fHistoryLoadings\x127\n" +
+	"\x06claims\x18\x01 \x01(\v2\x1f.pricingengine.v1.ClaimLoadingsR\x06claims\x12F\n" +
+	"\vconvictions\x18\x02 \x01(\v2$.pricingengine.v1.ConvictionLoadingsR\vconvictions\"\x8d\x01\n" +
+	"\rClaimLoadings\x12%\n" +
+	"\x0elookback_years\x18\x01 \x01(\x05R\rlookbackYears\x12\x1f\n" +
+	"\vmax_loading\x18\x02 \x01(\x01R\n" +
+	"maxLoading\x124\n" +
+	"\x05types\x18\x03 \x03(\v2\x1e.pricingengine.v1.ClaimLoadingR\x05types\"\xc6\x01\n" +
+	"\fClaimLoading\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12%\n" +
+	"\x0elookback_years\x18\x02 \x01(\x05R\rlookbackYears\x12!\n" +
+	"\ffault_factor\x18\x03 \x01(\x01R\vfaultFactor\x12(\n" +
+	"\x10non_fault_factor\x18\x04 \x01(\x01R\x0enonFaultFactor\x12\x18\n" +
+	"\adecline\x18\x05 \x01(\bR\adecline\x12\x14\n" +
+	"\x05label\x18\x06 \x01(\tR\x05label\"\xb6\x01\n" +
+	"\x12ConvictionLoadings\x12%\n" +
+	"\x0elookback_years\x18\x01 \x01(\x05R\rlookbackYears\x12\x1f\n" +
+	"\vmax_loading\x18\x02 \x01(\x01R\n" +
+	"maxLoading\x12\x1d\n" +
+	"\n" +
+	"max_points\x18\x03 \x01(\x05R\tmaxPoints\x129\n" +
+	"\x05codes\x18\x04 \x03(\v2#.pricingengine.v1.ConvictionLoadingR\x05codes\"\x96\x01\n" +
+	"\x11ConvictionLoading\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12%\n" +
+	"\x0elookback_years\x18\x02 \x01(\x05R\rlookbackYears\x12\x16\n" +
+	"\x06factor\x18\x03 \x01(\x01R\x06factor\x12\x18\n" +
+	"\adecline\x18\x04 \x01(\bR\adecline\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\"\x92\x01\n" +
+	"\rPostcodeRange\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1f\n" +
+	"\vis_eligible\x18\x02 \x01(\bR\n" +
+	"isEligible\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x05 \x01(\tR\toperation\"\x80\x01\n" +
+	"\x10InteractionTable\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"dimensions\x18\x02 \x03(\tR\n" +
+	"dimensions\x128\n" +
+	"\x05cells\x18\x03 \x03(\v2\".pricingengine.v1.InteractionRangeR\x05cells\"\xa5\x01\n" +
+	"\x10InteractionRange\x12\x14\n" +
+	"\x05start\x18\x01 \x03(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x03(\x03R\x03end\x12\x1f\n" +
+	"\vis_eligible\x18\x03 \x01(\bR\n" +
+	"isEligible\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation\"\xc0\x01\n" +
+	"\x10CategoricalTable\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tattribute\x18\x02 \x01(\tR\tattribute\x12<\n" +
+	"\aentries\x18\x03 \x03(\v2\".pricingengine.v1.CategoricalRangeR\aentries\x12<\n" +
+	"\adefault\x18\x04 \x01(\v2\".pricingengine.v1.CategoricalRangeR\adefault\"\xaf\x01\n" +
+	"\x10CategoricalRange\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\x12\x1f\n" +
+	"\vis_eligible\x18\x03 \x01(\bR\n" +
+	"isEligible\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation2\xce\x02\n" +
+	"\rPricingEngine\x12f\n" +
+	"\x0fGeneratePricing\x12(.pricingengine.v1.GeneratePricingRequest\x1a).pricingengine.v1.GeneratePricingResponse\x12u\n" +
+	"\x14GenerateBatchPricing\x12-.pricingengine.v1.GenerateBatchPricingRequest\x1a..pricingengine.v1.GenerateBatchPricingResponse\x12^\n" +
+	"\x10GetPricingConfig\x12).pricingengine.v1.GetPricingConfigRequest\x1a\x1f.pricingengine.v1.PricingConfigB)Z'pricingengine/service/grpcapi/pricingpbb\x06proto3"
+
+var (
+	file_service_grpcapi_pricingpb_pricing_proto_rawDescOnce sync.Once
+	file_service_grpcapi_pricingpb_pricing_proto_rawDescData []byte
+)
+
+func file_service_grpcapi_pricingpb_pricing_proto_rawDescGZIP() []byte {
+	file_service_grpcapi_pricingpb_pricing_proto_rawDescOnce.Do(func() {
+		file_service_grpcapi_pricingpb_pricing_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_service_grpcapi_pricingpb_pricing_proto_rawDesc), len(file_service_grpcapi_pricingpb_pricing_proto_rawDesc)))
+	})
+	return file_service_grpcapi_pricingpb_pricing_proto_rawDescData
+}
+
+var file_service_grpcapi_pricingpb_pricing_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_service_grpcapi_pricingpb_pricing_proto_goTypes = []any{
+	(*GeneratePricingRequest)(nil),       // 0: pricingengine.v1.GeneratePricingRequest
+	(*Driver)(nil),                       // 1: pricingengine.v1.Driver
+	(*Claim)(nil),                        // 2: pricingengine.v1.Claim
+	(*Conviction)(nil),                   // 3: pricingengine.v1.Conviction
+	(*PricingItem)(nil),                  // 4: pricingengine.v1.PricingItem
+	(*PricingStep)(nil),                  // 5: pricingengine.v1.PricingStep
+	(*GeneratePricingResponse)(nil),      // 6: pricingengine.v1.GeneratePricingResponse
+	(*Promo)(nil),                        // 7: pricingengine.v1.Promo
+	(*Vehicle)(nil),                      // 8: pricingengine.v1.Vehicle
+	(*GenerateBatchPricingRequest)(nil),  // 9: pricingengine.v1.GenerateBatchPricingRequest
+	(*GenerateBatchPricingResponse)(nil), // 10: pricingengine.v1.GenerateBatchPricingResponse
+	(*GetPricingConfigRequest)(nil),      // 11: pricingengine.v1.GetPricingConfigRequest
+	(*RangeConfig)(nil),                  // 12: pricingengine.v1.RangeConfig
+	(*PricingConfig)(nil),                // 13: pricingengine.v1.PricingConfig
+	(*DriverCombination)(nil),            // 14: pricingengine.v1.DriverCombination
+	(*DriverLoading)(nil),                // 15: pricingengine.v1.DriverLoading
+	(*LoyaltyFactor)(nil),                // 16: pricingengine.v1.LoyaltyFactor
+	(*LoyaltyBand)(nil),                  // 17: pricingengine.v1.LoyaltyBand
+	(*PromoCode)(nil),                    // 18: pricingengine.v1.PromoCode
+	(*TemporalFactors)(nil),              // 19: pricingengine.v1.TemporalFactors
+	(*HourFactor)(nil),                   // 20: pricingengine.v1.HourFactor
+	(*DayFactor)(nil),                    // 21: pricingengine.v1.DayFactor
+	(*HolidayFactor)(nil),                // 22: pricingengine.v1.HolidayFactor
+	(*Holiday)(nil),                      // 23: pricingengine.v1.Holiday
+	(*HistoryLoadings)(nil),              // 24: pricingengine.v1.HistoryLoadings
+	(*ClaimLoadings)(nil),                // 25: pricingengine.v1.ClaimLoadings
+	(*ClaimLoading)(nil),                 // 26: pricingengine.v1.ClaimLoading
+	(*ConvictionLoadings)(nil),           // 27: pricingengine.v1.ConvictionLoadings
+	(*ConvictionLoading)(nil),            // 28: pricingengine.v1.ConvictionLoading
+	(*PostcodeRange)(nil),                // 29: pricingengine.v1.PostcodeRange
+	(*InteractionTable)(nil),             // 30: pricingengine.v1.InteractionTable
+	(*InteractionRange)(nil),             // 31: pricingengine.v1.InteractionRange
+	(*CategoricalTable)(nil),             // 32: pricingengine.v1.CategoricalTable
+	(*CategoricalRange)(nil),             // 33: pricingengine.v1.CategoricalRange
+}
+var file_service_grpcapi_pricingpb_pricing_proto_depIdxs = []int32{
+	2,  // 0: pricingengine.v1.GeneratePricingRequest.claims:type_name -> pricingengine.v1.Claim
+	3,  // 1: pricingengine.v1.GeneratePricingRequest.convictions:type_name -> pricingengine.v1.Conviction
+	1,  // 2: pricingengine.v1.GeneratePricingRequest.additional_drivers:type_name -> pricingengine.v1.Driver
+	5,  // 3: pricingengine.v1.PricingItem.breakdown:type_name -> pricingengine.v1.PricingStep
+	0,  // 4: pricingengine.v1.GeneratePricingResponse.input:type_name -> pricingengine.v1.GeneratePricingRequest
+	4,  // 5: pricingengine.v1.GeneratePricingResponse.pricing:type_name -> pricingengine.v1.PricingItem
+	8,  // 6: pricingengine.v1.GeneratePricingResponse.vehicle:type_name -> pricingengine.v1.Vehicle
+	7,  // 7: pricingengine.v1.GeneratePricingResponse.promo:type_name -> pricingengine.v1.Promo
+	0,  // 8: pricingengine.v1.GenerateBatchPricingRequest.requests:type_name -> pricingengine.v1.GeneratePricingRequest
+	6,  // 9: pricingengine.v1.GenerateBatchPricingResponse.responses:type_name -> pricingengine.v1.GeneratePricingResponse
+	12, // 10: pricingengine.v1.PricingConfig.base_rate:type_name -> pricingengine.v1.RangeConfig
+	12, // 11: pricingengine.v1.PricingConfig.driver_age_factor:type_name -> pricingengine.v1.RangeConfig
+	12, // 12: pricingengine.v1.PricingConfig.insurance_group_factor:type_name -> pricingengine.v1.RangeConfig
+	12, // 13: pricingengine.v1.PricingConfig.licence_validity_factor:type_name -> pricingengine.v1.RangeConfig
+	30, // 14: pricingengine.v1.PricingConfig.interaction_factors:type_name -> pricingengine.v1.InteractionTable
+	32, // 15: pricingengine.v1.PricingConfig.categorical_factors:type_name -> pricingengine.v1.CategoricalTable
+	29, // 16: pricingengine.v1.PricingConfig.postcode_factor:type_name -> pricingengine.v1.PostcodeRange
+	24, // 17: pricingengine.v1.PricingConfig.history_loadings:type_name -> pricingengine.v1.HistoryLoadings
+	19, // 18: pricingengine.v1.PricingConfig.temporal_factors:type_name -> pricingengine.v1.TemporalFactors
+	23, // 19: pricingengine.v1.PricingConfig.holidays:type_name -> pricingengine.v1.Holiday
+	18, // 20: pricingengine.v1.PricingConfig.promo_codes:type_name -> pricingengine.v1.PromoCode
+	16, // 21: pricingengine.v1.PricingConfig.loyalty_factor:type_name -> pricingengine.v1.LoyaltyFactor
+	14, // 22: pricingengine.v1.PricingConfig.driver_combination:type_name -> pricingengine.v1.DriverCombination
+	15, // 23: pricingengine.v1.DriverCombination.loading:type_name -> pricingengine.v1.DriverLoading
+	17, // 24: pricingengine.v1.LoyaltyFactor.bands:type_name -> pricingengine.v1.LoyaltyBand
+	20, // 25: pricingengine.v1.TemporalFactors.hours:type_name -> pricingengine.v1.HourFactor
+	21, // 26: pricingengine.v1.TemporalFactors.days:type_name -> pricingengine.v1.DayFactor
+	22, // 27: pricingengine.v1.TemporalFactors.holiday:type_name -> pricingengine.v1.HolidayFactor
+	25, // 28: pricingengine.v1.HistoryLoadings.claims:type_name -> pricingengine.v1.ClaimLoadings
+	27, // 29: pricingengine.v1.HistoryLoadings.convictions:type_name -> pricingengine.v1.ConvictionLoadings
+	26, // 30: pricingengine.v1.ClaimLoadings.types:type_name -> pricingengine.v1.ClaimLoading
+	28, // 31: pricingengine.v1.ConvictionLoadings.codes:type_name -> pricingengine.v1.ConvictionLoading
+	31, // 32: pricingengine.v1.InteractionTable.cells:type_name -> pricingengine.v1.InteractionRange
+	33, // 33: pricingengine.v1.CategoricalTable.entries:type_name -> pricingengine.v1.CategoricalRange
+	33, // 34: pricingengine.v1.CategoricalTable.default:type_name -> pricingengine.v1.CategoricalRange
+	0,  // 35: pricingengine.v1.PricingEngine.GeneratePricing:input_type -> pricingengine.v1.GeneratePricingRequest
+	9,  // 36: pricingengine.v1.PricingEngine.GenerateBatchPricing:input_type -> pricingengine.v1.GenerateBatchPricingRequest
+	11, // 37: pricingengine.v1.PricingEngine.GetPricingConfig:input_type -> pricingengine.v1.GetPricingConfigRequest
+	6,  // 38: pricingengine.v1.PricingEngine.GeneratePricing:output_type -> pricingengine.v1.GeneratePricingResponse
+	10, // 39: pricingengine.v1.PricingEngine.GenerateBatchPricing:output_type -> pricingengine.v1.GenerateBatchPricingResponse
+	13, // 40: pricingengine.v1.PricingEngine.GetPricingConfig:output_type -> pricingengine.v1.PricingConfig
+	38, // [38:41] is the sub-list for method output_type
+	35, // [35:38] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_service_grpcapi_pricingpb_pricing_proto_init() }
+func file_service_grpcapi_pricingpb_pricing_proto_init() {
+	if File_service_grpcapi_pricingpb_pricing_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_grpcapi_pricingpb_pricing_proto_rawDesc), len(file_service_grpcapi_pricingpb_pricing_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_service_grpcapi_pricingpb_pricing_proto_goTypes,
+		DependencyIndexes: file_service_grpcapi_pricingpb_pricing_proto_depIdxs,
+		MessageInfos:      file_service_grpcapi_pricingpb_pricing_proto_msgTypes,
+	}.Build()
+	File_service_grpcapi_pricingpb_pricing_proto = out.File
+	file_service_grpcapi_pricingpb_pricing_proto_goTypes = nil
+	file_service_grpcapi_pricingpb_pricing_proto_depIdxs = nil
+}
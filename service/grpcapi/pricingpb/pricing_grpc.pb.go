@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: service/grpcapi/pricingpb/pricing.proto
+
+package pricingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PricingEngine_GeneratePricing_FullMethodName      = "/pricingengine.v1.PricingEngine/GeneratePricing"
+	PricingEngine_GenerateBatchPricing_FullMethodName = "/pricingengine.v1.PricingEngine/GenerateBatchPricing"
+	PricingEngine_GetPricingConfig_FullMethodName     = "/pricingengine.v1.PricingEngine/GetPricingConfig"
+)
+
+// PricingEngineClient is the client API for PricingEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PricingEngineClient interface {
+	GeneratePricing(ctx context.Context, in *GeneratePricingRequest, opts ...grpc.CallOption) (*GeneratePricingResponse, error)
+	GenerateBatchPricing(ctx context.Context, in *GenerateBatchPricingRequest, opts ...grpc.CallOption) (*GenerateBatchPricingResponse, error)
+	GetPricingConfig(ctx context.Context, in *GetPricingConfigRequest, opts ...grpc.CallOption) (*PricingConfig, error)
+}
+
+type pricingEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPricingEngineClient(cc grpc.ClientConnInterface) PricingEngineClient {
+	return &pricingEngineClient{cc}
+}
+
+func (c *pricingEngineClient) GeneratePricing(ctx context.Context, in *GeneratePricingRequest, opts ...grpc.CallOption) (*GeneratePricingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GeneratePricingResponse)
+	err := c.cc.Invoke(ctx, PricingEngine_GeneratePricing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pricingEngineClient) GenerateBatchPricing(ctx context.Context, in *GenerateBatchPricingRequest, opts ...grpc.CallOption) (*GenerateBatchPricingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateBatchPricingResponse)
+	err := c.cc.Invoke(ctx, PricingEngine_GenerateBatchPricing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pricingEngineClient) GetPricingConfig(ctx context.Context, in *GetPricingConfigRequest, opts ...grpc.CallOption) (*PricingConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PricingConfig)
+	err := c.cc.Invoke(ctx, PricingEngine_GetPricingConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PricingEngineServer is the server API for PricingEngine service.
+// All implementations must embed UnimplementedPricingEngineServer
+// for forward compatibility.
+type PricingEngineServer interface {
+	GeneratePricing(context.Context, *GeneratePricingRequest) (*GeneratePricingResponse, error)
+	GenerateBatchPricing(context.Context, *GenerateBatchPricingRequest) (*GenerateBatchPricingResponse, error)
+	GetPricingConfig(context.Context, *GetPricingConfigRequest) (*PricingConfig, error)
+	mustEmbedUnimplementedPricingEngineServer()
+}
+
+// UnimplementedPricingEngineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPricingEngineServer struct{}
+
+func (UnimplementedPricingEngineServer) GeneratePricing(context.Context, *GeneratePricingRequest) (*GeneratePricingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GeneratePricing not implemented")
+}
+func (UnimplementedPricingEngineServer) GenerateBatchPricing(context.Context, *GenerateBatchPricingRequest) (*GenerateBatchPricingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateBatchPricing not implemented")
+}
+func (UnimplementedPricingEngineServer) GetPricingConfig(context.Context, *GetPricingConfigRequest) (*PricingConfig, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPricingConfig not implemented")
+}
+func (UnimplementedPricingEngineServer) mustEmbedUnimplementedPricingEngineServer() {}
+func (UnimplementedPricingEngineServer) testEmbeddedByValue()                       {}
+
+// UnsafePricingEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PricingEngineServer will
+// result in compilation errors.
+type UnsafePricingEngineServer interface {
+	mustEmbedUnimplementedPricingEngineServer()
+}
+
+func RegisterPricingEngineServer(s grpc.ServiceRegistrar, srv PricingEngineServer) {
+	// If the following call panics, it indicates UnimplementedPricingEngineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PricingEngine_ServiceDesc, srv)
+}
+
+func _PricingEngine_GeneratePricing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeneratePricingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingEngineServer).GeneratePricing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PricingEngine_GeneratePricing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingEngineServer).GeneratePricing(ctx, req.(*GeneratePricingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PricingEngine_GenerateBatchPricing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBatchPricingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingEngineServer).GenerateBatchPricing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PricingEngine_GenerateBatchPricing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingEngineServer).GenerateBatchPricing(ctx, req.(*GenerateBatchPricingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PricingEngine_GetPricingConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPricingConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PricingEngineServer).GetPricingConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PricingEngine_GetPricingConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PricingEngineServer).GetPricingConfig(ctx, req.(*GetPricingConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PricingEngine_ServiceDesc is the grpc.ServiceDesc for PricingEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PricingEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pricingengine.v1.PricingEngine",
+	HandlerType: (*PricingEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GeneratePricing",
+			Handler:    _PricingEngine_GeneratePricing_Handler,
+		},
+		{
+			MethodName: "GenerateBatchPricing",
+			Handler:    _PricingEngine_GenerateBatchPricing_Handler,
+		},
+		{
+			MethodName: "GetPricingConfig",
+			Handler:    _PricingEngine_GetPricingConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/grpcapi/pricingpb/pricing.proto",
+}
@@ -0,0 +1,503 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/auth"
+	"pricingengine/service/config"
+	"pricingengine/service/grpcapi/pricingpb"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+	"pricingengine/service/model"
+	"pricingengine/service/ratelimit"
+	"pricingengine/service/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata is the metadata key the request ID is read from and echoed back in
+const requestIDMetadata = "x-request-id"
+
+// Server exposes the App over gRPC, it is served on its own port next to the REST service
+type Server struct {
+	pricingpb.UnimplementedPricingEngineServer
+	App        *app.App
+	Auth       *auth.Authenticator // authenticates the calls, every method is open when nil
+	Limiter    *ratelimit.Limiter  // limits the calls of every client, nothing is limited when nil
+	GRPCServer *grpc.Server
+}
+
+// methodScopes maps every method of the PricingEngine service to the scope its caller needs
+var methodScopes = map[string]string{
+	pricingpb.PricingEngine_GeneratePricing_FullMethodName:      auth.ScopeQuote,
+	pricingpb.PricingEngine_GenerateBatchPricing_FullMethodName: auth.ScopeQuote,
+	pricingpb.PricingEngine_GetPricingConfig_FullMethodName:     auth.ScopeConfigRead,
+}
+
+// methodCosts maps every method of the PricingEngine service to its rate limit class, a batch
+// takes a batch item token per request
+var methodCosts = map[string]ratelimit.Cost{
+	pricingpb.PricingEngine_GeneratePricing_FullMethodName: {Class: ratelimit.ClassQuote},
+	pricingpb.PricingEngine_GenerateBatchPricing_FullMethodName: {Class: ratelimit.ClassBatchItem, Tokens: func(req interface{}) int {
+		return len(req.(*pricingpb.GenerateBatchPricingRequest).GetRequests())
+	}},
+	pricingpb.PricingEngine_GetPricingConfig_FullMethodName: {Class: ratelimit.ClassConfigRead},
+}
+
+// NewServer method returns a Server with the PricingEngine and reflection services registered
+// The options are passed on to the grpc.Server, e.g. grpc.Creds to serve over TLS
+func NewServer(app *app.App, options ...grpc.ServerOption) *Server {
+	s := &Server{App: app}
+	s.register(options...)
+	return s
+}
+
+// Start method registers the PricingEngine service along with the reflection service
+// and serves it at the given address, it blocks until the server is stopped
+// returns the error that made the server stop, if any
+func (s *Server) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve method serves the gRPC service on an existing listener
+func (s *Server) Serve(listener net.Listener) error {
+	if s.GRPCServer == nil {
+		s.register()
+	}
+	slog.Info("Starting gRPC Server", "address", listener.Addr().String())
+	return s.GRPCServer.Serve(listener)
+}
+
+// Stop method gracefully stops the running gRPC server
+func (s *Server) Stop() {
+	if s.GRPCServer != nil {
+		slog.Info("Stopping gRPC Server")
+		s.GRPCServer.GracefulStop()
+	}
+}
+
+// Shutdown method gracefully stops the running gRPC server, letting the in-flight calls
+// finish until the context is done, the calls still running then are cancelled
+// returns the context error when some calls had to be cancelled
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.GRPCServer == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		slog.Warn("Forcing gRPC Server stop")
+		s.GRPCServer.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// GeneratePricing prices a single risk with app.GeneratePricing
+func (s *Server) GeneratePricing(ctx context.Context, request *pricingpb.GeneratePricingRequest) (*pricingpb.GeneratePricingResponse, error) {
+	res, err := s.App.GeneratePricing(ctx, fromProtoRequest(request))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toProtoResponse(res), nil
+}
+
+// GenerateBatchPricing prices every request of the batch with app.GenerateBatchPricing
+func (s *Server) GenerateBatchPricing(ctx context.Context, request *pricingpb.GenerateBatchPricingRequest) (*pricingpb.GenerateBatchPricingResponse, error) {
+	requests := make([]*pricingengine.GeneratePricingRequest, 0, len(request.GetRequests()))
+	for _, r := range request.GetRequests() {
+		requests = append(requests, fromProtoRequest(r))
+	}
+	res, err := s.App.GenerateBatchPricing(ctx, requests)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	result := &pricingpb.GenerateBatchPricingResponse{}
+	for _, r := range res {
+		result.Responses = append(result.Responses, toProtoResponse(r))
+	}
+	return result, nil
+}
+
+// GetPricingConfig returns the factor ranges currently cached by the App
+func (s *Server) GetPricingConfig(ctx context.Context, request *pricingpb.GetPricingConfigRequest) (*pricingpb.PricingConfig, error) {
+	snapshot := s.App.PricingConfigSnapshot(ctx)
+	return &pricingpb.PricingConfig{
+		Version:               int32(snapshot.Version),
+		BaseRate:              toProtoRanges(snapshot.BaseRateList),
+		DriverAgeFactor:       toProtoRanges(snapshot.DriverAgeFactorList),
+		InsuranceGroupFactor:  toProtoRanges(snapshot.InsuranceGroupFactorList),
+		LicenceValidityFactor: toProtoRanges(snapshot.LicenceValidityFactorList),
+		InteractionFactors:    toProtoInteractions(snapshot.InteractionFactorList),
+		CategoricalFactors:    toProtoCategoricals(snapshot.CategoricalFactorList),
+		PostcodeFactor:        toProtoPostcodes(snapshot.PostcodeFactorList),
+		HistoryLoadings:       toProtoHistoryLoadings(snapshot.HistoryLoadings),
+		TemporalFactors:       toProtoTemporalFactors(snapshot.TemporalFactors),
+		Holidays:              toProtoHolidays(snapshot.HolidayList),
+		PromoCodes:            toProtoPromoCodes(snapshot.PromoCodeList),
+		LoyaltyFactor:         toProtoLoyaltyFactor(snapshot.LoyaltyFactor),
+		DriverCombination:     toProtoDriverCombination(snapshot.DriverCombination),
+	}, nil
+}
+
+func (s *Server) register(options ...grpc.ServerOption) {
+	options = append(options, grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, requestIDInterceptor, s.authInterceptor, s.rateLimitInterceptor, metrics.UnaryServerInterceptor, recoveryInterceptor))
+	s.GRPCServer = grpc.NewServer(options...)
+	pricingpb.RegisterPricingEngineServer(s.GRPCServer, s)
+	reflection.Register(s.GRPCServer)
+}
+
+// requestIDInterceptor carries the x-request-id metadata of the call, or a new ID when it has none,
+// into the context of the call and echoes it in the response header, the same way the REST service does
+func requestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(requestIDMetadata)) > 0 {
+		id = md.Get(requestIDMetadata)[0]
+	}
+	if len(id) == 0 {
+		id = logging.NewRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+	ctx = logging.WithRequestID(ctx, id)
+
+	start := time.Now()
+	res, err := handler(ctx, req)
+	logging.FromContext(ctx).Info("call served", "method", info.FullMethod, "code", status.Code(err).String(), "duration_ms", float64(time.Since(start).Microseconds())/1000)
+	return res, err
+}
+
+// recoveryInterceptor turns a panic of the handler into an Internal error instead of crashing
+// the process, the panic is logged with its stack. It is the innermost interceptor so the
+// logs and metrics of the call see the error.
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logging.FromContext(ctx).Error("Recovered from panic", "method", info.FullMethod, "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+			res, err = nil, status.Error(codes.Internal, "the call could not be handled")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// authInterceptor authenticates and authorises the calls with the Auth of the server, see
+// auth.Authenticator.UnaryServerInterceptor, it passes every call through when Auth is nil
+func (s *Server) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.Auth == nil {
+		return handler(ctx, req)
+	}
+	return s.Auth.UnaryServerInterceptor(methodScopes)(ctx, req, info, handler)
+}
+
+// rateLimitInterceptor limits the calls with the Limiter of the server, see
+// ratelimit.Limiter.UnaryServerInterceptor, it passes every call through when Limiter is nil
+func (s *Server) rateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.Limiter == nil {
+		return handler(ctx, req)
+	}
+	return s.Limiter.UnaryServerInterceptor(methodCosts)(ctx, req, info, handler)
+}
+
+// toStatusError maps the errors surfaced by the application to gRPC status codes
+func toStatusError(err error) error {
+	var validationErr *config.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, config.ErrVersionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func fromProtoRequest(request *pricingpb.GeneratePricingRequest) *pricingengine.GeneratePricingRequest {
+	result := &pricingengine.GeneratePricingRequest{
+		DateOfBirth:         request.GetDateOfBirth(),
+		InsuranceGroup:      int(request.GetInsuranceGroup()),
+		LicenseHeldSince:    request.GetLicenseHeldSince(),
+		Occupation:          request.GetOccupation(),
+		FuelType:            request.GetFuelType(),
+		CoverPurpose:        request.GetCoverPurpose(),
+		Postcode:            request.GetPostcode(),
+		VehicleRegistration: request.GetVehicleRegistration(),
+		VehicleCode:         request.GetVehicleCode(),
+		CoverStart:          request.GetCoverStart(),
+		PromoCode:           request.GetPromoCode(),
+		CustomerID:          request.GetCustomerId(),
+	}
+	for _, claim := range request.GetClaims() {
+		result.Claims = append(result.Claims, pricingengine.Claim{Date: claim.GetDate(), Type: claim.GetType(), Fault: claim.GetFault()})
+	}
+	for _, conviction := range request.GetConvictions() {
+		result.Convictions = append(result.Convictions, pricingengine.Conviction{Code: conviction.GetCode(), Date: conviction.GetDate(), Points: int(conviction.GetPoints())})
+	}
+	for _, driver := range request.GetAdditionalDrivers() {
+		result.AdditionalDrivers = append(result.AdditionalDrivers, pricingengine.Driver{Name: driver.GetName(), DateOfBirth: driver.GetDateOfBirth(), LicenseHeldSince: driver.GetLicenseHeldSince()})
+	}
+	return result
+}
+
+func toProtoRequest(request *pricingengine.GeneratePricingRequest) *pricingpb.GeneratePricingRequest {
+	result := &pricingpb.GeneratePricingRequest{
+		DateOfBirth:         request.DateOfBirth,
+		InsuranceGroup:      int32(request.InsuranceGroup),
+		LicenseHeldSince:    request.LicenseHeldSince,
+		Occupation:          request.Occupation,
+		FuelType:            request.FuelType,
+		CoverPurpose:        request.CoverPurpose,
+		Postcode:            request.Postcode,
+		VehicleRegistration: request.VehicleRegistration,
+		VehicleCode:         request.VehicleCode,
+		CoverStart:          request.CoverStart,
+		PromoCode:           request.PromoCode,
+		CustomerId:          request.CustomerID,
+	}
+	for _, claim := range request.Claims {
+		result.Claims = append(result.Claims, &pricingpb.Claim{Date: claim.Date, Type: claim.Type, Fault: claim.Fault})
+	}
+	for _, conviction := range request.Convictions {
+		result.Convictions = append(result.Convictions, &pricingpb.Conviction{Code: conviction.Code, Date: conviction.Date, Points: int32(conviction.Points)})
+	}
+	for _, driver := range request.AdditionalDrivers {
+		result.AdditionalDrivers = append(result.AdditionalDrivers, &pricingpb.Driver{Name: driver.Name, DateOfBirth: driver.DateOfBirth, LicenseHeldSince: driver.LicenseHeldSince})
+	}
+	return result
+}
+
+func toProtoResponse(response *pricingengine.GeneratePricingResponse) *pricingpb.GeneratePricingResponse {
+	result := &pricingpb.GeneratePricingResponse{
+		Input:      toProtoRequest(&response.Input),
+		IsEligible: response.IsEligible,
+		Message:    response.Message,
+	}
+	if vehicle := response.Vehicle; vehicle != nil {
+		result.Vehicle = &pricingpb.Vehicle{
+			Registration:   vehicle.Registration,
+			Code:           vehicle.Code,
+			Make:           vehicle.Make,
+			Model:          vehicle.Model,
+			InsuranceGroup: int32(vehicle.InsuranceGroup),
+			Value:          vehicle.Value,
+			Age:            int32(vehicle.Age),
+		}
+	}
+	if promo := response.Promo; promo != nil {
+		result.Promo = &pricingpb.Promo{Code: promo.Code, Applied: promo.Applied, Reason: promo.Reason}
+	}
+	for _, item := range response.PricingList {
+		converted := &pricingpb.PricingItem{
+			Premium:   item.Premium,
+			Currency:  item.Currency,
+			FareGroup: item.FareGroup,
+		}
+		for _, step := range item.Breakdown {
+			converted.Breakdown = append(converted.Breakdown, &pricingpb.PricingStep{Label: step.Label, Factor: step.Factor, Premium: step.Premium, Operation: step.Operation})
+		}
+		result.Pricing = append(result.Pricing, converted)
+	}
+	return result
+}
+
+func toProtoRanges(ranges []models.RangeConfig) []*pricingpb.RangeConfig {
+	result := make([]*pricingpb.RangeConfig, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, &pricingpb.RangeConfig{
+			Start:      int64(r.Start),
+			End:        int64(r.End),
+			IsEligible: r.IsEligible,
+			Value:      r.Value,
+			Label:      r.Label,
+			Operation:  r.Operation,
+		})
+	}
+	return result
+}
+
+func toProtoInteractions(tables []models.InteractionTable) []*pricingpb.InteractionTable {
+	result := make([]*pricingpb.InteractionTable, 0, len(tables))
+	for _, table := range tables {
+		cells := make([]*pricingpb.InteractionRange, 0, len(table.Cells))
+		for _, cell := range table.Cells {
+			converted := &pricingpb.InteractionRange{IsEligible: cell.IsEligible, Value: cell.Value, Label: cell.Label, Operation: cell.Operation}
+			for i := range cell.Start {
+				converted.Start = append(converted.Start, int64(cell.Start[i]))
+				converted.End = append(converted.End, int64(cell.End[i]))
+			}
+			cells = append(cells, converted)
+		}
+		result = append(result, &pricingpb.InteractionTable{Name: table.Name, Dimensions: table.Dimensions, Cells: cells})
+	}
+	return result
+}
+
+func toProtoCategoricals(tables []models.CategoricalTable) []*pricingpb.CategoricalTable {
+	result := make([]*pricingpb.CategoricalTable, 0, len(tables))
+	for _, table := range tables {
+		converted := &pricingpb.CategoricalTable{Name: table.Name, Attribute: table.Attribute}
+		for _, entry := range table.Entries {
+			converted.Entries = append(converted.Entries, toProtoCategoricalRange(entry))
+		}
+		if table.Default != nil {
+			converted.Default = toProtoCategoricalRange(*table.Default)
+		}
+		result = append(result, converted)
+	}
+	return result
+}
+
+func toProtoCategoricalRange(entry models.CategoricalRange) *pricingpb.CategoricalRange {
+	return &pricingpb.CategoricalRange{
+		Values:     entry.Values,
+		Pattern:    entry.Pattern,
+		IsEligible: entry.IsEligible,
+		Value:      entry.Value,
+		Label:      entry.Label,
+		Operation:  entry.Operation,
+	}
+}
+
+func toProtoPostcodes(postcodes []models.PostcodeRange) []*pricingpb.PostcodeRange {
+	result := make([]*pricingpb.PostcodeRange, 0, len(postcodes))
+	for _, p := range postcodes {
+		result = append(result, &pricingpb.PostcodeRange{
+			Prefix:     p.Prefix,
+			IsEligible: p.IsEligible,
+			Value:      p.Value,
+			Label:      p.Label,
+			Operation:  p.Operation,
+		})
+	}
+	return result
+}
+
+func toProtoHistoryLoadings(loadings *models.HistoryLoadings) *pricingpb.HistoryLoadings {
+	if loadings == nil {
+		return nil
+	}
+	claims := &pricingpb.ClaimLoadings{
+		LookbackYears: int32(loadings.Claims.LookbackYears),
+		MaxLoading:    loadings.Claims.MaxLoading,
+	}
+	for _, claim := range loadings.Claims.Types {
+		claims.Types = append(claims.Types, &pricingpb.ClaimLoading{
+			Type:           claim.Type,
+			LookbackYears:  int32(claim.LookbackYears),
+			FaultFactor:    claim.FaultFactor,
+			NonFaultFactor: claim.NonFaultFactor,
+			Decline:        claim.Decline,
+			Label:          claim.Label,
+		})
+	}
+	convictions := &pricingpb.ConvictionLoadings{
+		LookbackYears: int32(loadings.Convictions.LookbackYears),
+		MaxLoading:    loadings.Convictions.MaxLoading,
+		MaxPoints:     int32(loadings.Convictions.MaxPoints),
+	}
+	for _, conviction := range loadings.Convictions.Codes {
+		convictions.Codes = append(convictions.Codes, &pricingpb.ConvictionLoading{
+			Code:          conviction.Code,
+			LookbackYears: int32(conviction.LookbackYears),
+			Factor:        conviction.Factor,
+			Decline:       conviction.Decline,
+			Label:         conviction.Label,
+		})
+	}
+	return &pricingpb.HistoryLoadings{Claims: claims, Convictions: convictions}
+}
+
+func toProtoTemporalFactors(factors *models.TemporalFactors) *pricingpb.TemporalFactors {
+	if factors == nil {
+		return nil
+	}
+	result := &pricingpb.TemporalFactors{Timezone: factors.Timezone}
+	for _, hour := range factors.Hours {
+		result.Hours = append(result.Hours, &pricingpb.HourFactor{
+			From:       int32(hour.From),
+			To:         int32(hour.To),
+			IsEligible: hour.IsEligible,
+			Factor:     hour.Factor,
+			Label:      hour.Label,
+			Operation:  hour.Operation,
+		})
+	}
+	for _, day := range factors.Days {
+		result.Days = append(result.Days, &pricingpb.DayFactor{Days: day.Days, IsEligible: day.IsEligible, Factor: day.Factor, Label: day.Label, Operation: day.Operation})
+	}
+	if factors.Holiday != nil {
+		result.Holiday = &pricingpb.HolidayFactor{IsEligible: factors.Holiday.IsEligible, Factor: factors.Holiday.Factor, Label: factors.Holiday.Label, Operation: factors.Holiday.Operation}
+	}
+	return result
+}
+
+func toProtoHolidays(holidays []models.Holiday) []*pricingpb.Holiday {
+	result := []*pricingpb.Holiday{}
+	for _, holiday := range holidays {
+		result = append(result, &pricingpb.Holiday{Date: holiday.Date, Name: holiday.Name})
+	}
+	return result
+}
+
+func toProtoPromoCodes(promos []models.PromoCode) []*pricingpb.PromoCode {
+	result := []*pricingpb.PromoCode{}
+	for _, promo := range promos {
+		result = append(result, &pricingpb.PromoCode{
+			Code:       promo.Code,
+			Type:       promo.Type,
+			Value:      promo.Value,
+			ValidFrom:  promo.ValidFrom,
+			ValidTo:    promo.ValidTo,
+			Durations:  promo.Durations,
+			MinPremium: promo.MinPremium,
+			MaxUses:    int32(promo.MaxUses),
+			Label:      promo.Label,
+		})
+	}
+	return result
+}
+
+func toProtoLoyaltyFactor(loyalty *models.LoyaltyFactor) *pricingpb.LoyaltyFactor {
+	if loyalty == nil {
+		return nil
+	}
+	result := &pricingpb.LoyaltyFactor{Basis: loyalty.Basis}
+	for _, band := range loyalty.Bands {
+		result.Bands = append(result.Bands, &pricingpb.LoyaltyBand{From: int32(band.From), Factor: band.Factor, Label: band.Label, Operation: band.Operation})
+	}
+	return result
+}
+
+func toProtoDriverCombination(combination *models.DriverCombination) *pricingpb.DriverCombination {
+	if combination == nil {
+		return nil
+	}
+	result := &pricingpb.DriverCombination{Rule: combination.Rule, MainWeight: combination.MainWeight}
+	if combination.Loading != nil {
+		result.Loading = &pricingpb.DriverLoading{Factor: combination.Loading.Factor, Label: combination.Loading.Label, Operation: combination.Loading.Operation}
+	}
+	return result
+}
@@ -0,0 +1,390 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+	"pricingengine/service/util"
+)
+
+// Job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusFailed    = "failed"
+)
+
+// outcomes of pricing a single request of a job
+const (
+	outcomePriced   = "priced"
+	outcomeDeclined = "declined"
+	outcomeFailed   = "failed"
+)
+
+// ErrJobNotFound is returned for a job id that was never submitted, or was submitted by another owner
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that is no longer queued or running
+var ErrJobFinished = errors.New("job already finished")
+
+// maxLineSize caps a single request line of a submitted job
+const maxLineSize = 1024 * 1024
+
+// Manager runs repricing jobs in the background on a bounded pool of workers
+// Every job is persisted through the Store, jobs that were queued or running when the
+// process stopped are picked up again from their last checkpoint on Start
+type Manager struct {
+	App     *app.App
+	Store   *Store
+	Workers int // size of the worker pool, defaults to 2
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	jobs    map[string]*pricingengine.RepricingJob
+	cancels map[string]context.CancelFunc
+	pending []string
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// Start method loads the stored jobs, queues the unfinished ones again and starts the workers
+func (m *Manager) Start() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cond = sync.NewCond(&m.mutex)
+	m.jobs = make(map[string]*pricingengine.RepricingJob)
+	m.cancels = make(map[string]context.CancelFunc)
+
+	stored, err := m.Store.LoadAll()
+	if err != nil {
+		return err
+	}
+	for _, job := range stored {
+		m.jobs[job.ID] = job
+		if job.Status == StatusQueued || job.Status == StatusRunning {
+			slog.Info("Resuming job", "job_id", job.ID, "processed", job.Processed, "total", job.Total)
+			job.Status = StatusQueued
+			m.pending = append(m.pending, job.ID)
+		}
+	}
+	m.queueChanged()
+
+	workers := m.Workers
+	if workers <= 0 {
+		workers = 2
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	return nil
+}
+
+// Stop method stops the workers, the running jobs are interrupted after their current
+// request and stay queued in the Store so they resume on the next Start
+func (m *Manager) Stop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.Shutdown(ctx)
+}
+
+// Shutdown method stops the workers from taking new jobs and lets the running ones finish
+// until the context is done, the jobs still running then are interrupted after their current
+// request and stay queued in the Store so they resume on the next Start
+// returns the context error when some jobs had to be interrupted
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	m.stopped = true
+	m.cond.Broadcast()
+	m.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	m.mutex.Lock()
+	for _, cancel := range m.cancels {
+		cancel()
+	}
+	m.mutex.Unlock()
+	<-done
+	return ctx.Err()
+}
+
+// Submit method queues a job of the owner pricing the given list of requests, see SubmitFile
+func (m *Manager) Submit(owner string, requests []pricingengine.GeneratePricingRequest, admit func(total int) error) (*pricingengine.RepricingJob, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	for i := range requests {
+		if err := encoder.Encode(&requests[i]); err != nil {
+			return nil, err
+		}
+	}
+	return m.SubmitFile(owner, &buffer, admit)
+}
+
+// SubmitFile method queues a job of the owner pricing the newline delimited requests read from the reader
+// The owner is the client ID of the caller, empty when the service runs without authentication
+// admit, when not nil, is given the number of requests once they are stored, the job is dropped
+// instead of queued when it returns an error, e.g. when the requests are over a rate limit
+func (m *Manager) SubmitFile(owner string, requests io.Reader, admit func(total int) error) (*pricingengine.RepricingJob, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	job := &pricingengine.RepricingJob{ID: id, Owner: owner, Status: StatusQueued, CreatedAt: now, UpdatedAt: now}
+	if _, err := m.Store.Create(job, requests); err != nil {
+		return nil, err
+	}
+	if admit != nil {
+		if err := admit(job.Total); err != nil {
+			m.Store.Remove(id)
+			return nil, err
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.jobs[id] = job
+	m.pending = append(m.pending, id)
+	m.queueChanged()
+	m.cond.Signal()
+	slog.Info("Submitted job", "job_id", id, "owner", owner, "total", job.Total)
+	result := *job
+	return &result, nil
+}
+
+// Status method returns a copy of the current state of the job
+// A job submitted by another owner is reported as ErrJobNotFound, so its id is not disclosed
+func (m *Manager) Status(owner string, id string) (*pricingengine.RepricingJob, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job, err := m.find(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	result := *job
+	return &result, nil
+}
+
+// Results method copies the results written so far, one GeneratePricingResponse per line
+func (m *Manager) Results(owner string, id string, w io.Writer) error {
+	if _, err := m.Status(owner, id); err != nil {
+		return err
+	}
+	file, err := m.Store.OpenResults(id)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// Cancel method stops a queued or running job of the owner, the results written so far are kept
+func (m *Manager) Cancel(owner string, id string) (*pricingengine.RepricingJob, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job, err := m.find(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != StatusQueued && job.Status != StatusRunning {
+		return nil, ErrJobFinished
+	}
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+	}
+	job.Status = StatusCancelled
+	m.touch(job)
+	if err := m.Store.Save(job); err != nil {
+		return nil, err
+	}
+	result := *job
+	return &result, nil
+}
+
+// QueueDepth method returns the number of jobs waiting for a worker
+func (m *Manager) QueueDepth() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.pending)
+}
+
+// work is the loop of a single worker taking the next pending job until the manager stops
+func (m *Manager) work() {
+	defer m.wg.Done()
+	for {
+		m.mutex.Lock()
+		for len(m.pending) == 0 && !m.stopped {
+			m.cond.Wait()
+		}
+		if m.stopped {
+			m.mutex.Unlock()
+			return
+		}
+		id := m.pending[0]
+		m.pending = m.pending[1:]
+		m.queueChanged()
+		job := m.jobs[id]
+		if job.Status != StatusQueued {
+			// cancelled while waiting in the queue
+			m.mutex.Unlock()
+			continue
+		}
+		ctx, cancel := context.WithCancel(logging.With(context.Background(), "job_id", id))
+		m.cancels[id] = cancel
+		job.Status = StatusRunning
+		m.touch(job)
+		m.Store.Save(job)
+		m.mutex.Unlock()
+
+		err := m.run(ctx, job)
+
+		m.mutex.Lock()
+		interrupted := ctx.Err() != nil
+		cancel()
+		delete(m.cancels, id)
+		switch {
+		case job.Status == StatusCancelled:
+		case m.stopped && interrupted:
+			// interrupted by Stop, it resumes from the checkpoint on the next Start
+			job.Status = StatusQueued
+		case err != nil:
+			job.Status = StatusFailed
+			job.Error = err.Error()
+		default:
+			job.Status = StatusCompleted
+		}
+		m.touch(job)
+		m.Store.Save(job)
+		logging.FromContext(ctx).Info("Job stopped", "status", job.Status, "processed", job.Processed, "total", job.Total)
+		m.mutex.Unlock()
+	}
+}
+
+// run prices the requests of the job that were not processed yet, appending one result line
+// and saving the progress as a checkpoint after each of them
+func (m *Manager) run(ctx context.Context, job *pricingengine.RepricingJob) error {
+	m.mutex.Lock()
+	processed := job.Processed
+	m.mutex.Unlock()
+
+	requests, err := m.Store.OpenRequests(job.ID)
+	if err != nil {
+		return err
+	}
+	defer requests.Close()
+	results, err := m.Store.AppendResults(job.ID, processed)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	scanner := newLineScanner(requests)
+	encoder := json.NewEncoder(results)
+	line := 0
+	for scanner.Scan() {
+		if line++; line <= processed {
+			continue // already priced before a restart
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, outcome := m.price(ctx, scanner.Bytes())
+		if err := encoder.Encode(res); err != nil {
+			return err
+		}
+
+		m.mutex.Lock()
+		job.Processed++
+		switch outcome {
+		case outcomeFailed:
+			job.Failed++
+		case outcomeDeclined:
+			job.Declined++
+		default:
+			job.Priced++
+		}
+		m.touch(job)
+		err = m.Store.Save(job)
+		m.mutex.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// price decodes and prices a single request line, a line is decoded as strictly as a single quote
+// returns the response to store along with whether it was priced, declined or failed
+func (m *Manager) price(ctx context.Context, line []byte) (*pricingengine.GeneratePricingResponse, string) {
+	input := pricingengine.GeneratePricingRequest{}
+	if err := util.DecodeStrict(line, &input); err != nil {
+		return &pricingengine.GeneratePricingResponse{Message: "Invalid request: " + err.Error()}, outcomeFailed
+	}
+	res, err := m.App.GeneratePricing(ctx, &input)
+	if err != nil {
+		return &pricingengine.GeneratePricingResponse{Input: input, Message: err.Error()}, outcomeFailed
+	}
+	if !res.IsEligible {
+		return res, outcomeDeclined
+	}
+	return res, outcomePriced
+}
+
+// find returns the job when it belongs to the owner, the caller holds the mutex
+func (m *Manager) find(owner string, id string) (*pricingengine.RepricingJob, error) {
+	job, ok := m.jobs[id]
+	if !ok || job.Owner != owner {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// queueChanged publishes the number of pending jobs, the caller holds the mutex
+func (m *Manager) queueChanged() {
+	metrics.JobQueueDepth.Set(float64(len(m.pending)))
+}
+
+// touch updates the modification time of the job, the caller holds the mutex
+func (m *Manager) touch(job *pricingengine.RepricingJob) {
+	job.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return scanner
+}
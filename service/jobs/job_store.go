@@ -0,0 +1,159 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"pricingengine"
+)
+
+const (
+	jobFile      = "job.json"
+	requestsFile = "requests.ndjson"
+	resultsFile  = "results.ndjson"
+)
+
+// Store keeps every job in its own directory under Dir so the job state survives a restart
+// Each directory holds the job status, the submitted requests and the results written so far,
+// the latter two as newline delimited JSON
+type Store struct {
+	Dir string
+}
+
+// Create method writes the submitted requests of a new job and its initial status
+// The requests are copied line by line from the reader so a large file is never held in memory
+// returns the number of requests stored
+func (s *Store) Create(job *pricingengine.RepricingJob, requests io.Reader) (int, error) {
+	dir := s.jobDir(job.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	file, err := os.Create(filepath.Join(dir, requestsFile))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	count := 0
+	writer := bufio.NewWriter(file)
+	scanner := newLineScanner(requests)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		writer.Write(line)
+		writer.WriteByte('\n')
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		os.RemoveAll(dir)
+		return 0, err
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+	job.Total = count
+	return count, s.Save(job)
+}
+
+// Save method atomically writes the job status
+func (s *Store) Save(job *pricingengine.RepricingJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(s.jobDir(job.ID), jobFile)
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// Remove method deletes a job along with its requests and results
+func (s *Store) Remove(id string) error {
+	return os.RemoveAll(s.jobDir(id))
+}
+
+// LoadAll method reads the status of every stored job
+func (s *Store) LoadAll() ([]*pricingengine.RepricingJob, error) {
+	entries, err := ioutil.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := []*pricingengine.RepricingJob{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.Dir, entry.Name(), jobFile))
+		if err != nil {
+			continue // a job directory that was never completely created
+		}
+		job := pricingengine.RepricingJob{}
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		result = append(result, &job)
+	}
+	return result, nil
+}
+
+// OpenRequests method opens the submitted requests of a job for reading
+func (s *Store) OpenRequests(id string) (*os.File, error) {
+	return os.Open(filepath.Join(s.jobDir(id), requestsFile))
+}
+
+// OpenResults method opens the results of a job for reading
+func (s *Store) OpenResults(id string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(s.jobDir(id), resultsFile))
+	if os.IsNotExist(err) {
+		// no result was written yet
+		return os.Open(os.DevNull)
+	}
+	return file, err
+}
+
+// AppendResults method opens the results of a job for appending, keeping only the first
+// processed lines so that a result written after the last saved checkpoint is not counted twice
+func (s *Store) AppendResults(id string, processed int) (*os.File, error) {
+	filename := filepath.Join(s.jobDir(id), resultsFile)
+	if err := truncateLines(filename, processed); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+func (s *Store) jobDir(id string) string {
+	return filepath.Join(s.Dir, id)
+}
+
+// truncateLines cuts the file right after its first n lines
+func truncateLines(filename string, n int) error {
+	file, err := os.OpenFile(filename, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var offset int64
+	for i := 0; i < n; i++ {
+		line, err := reader.ReadBytes('\n')
+		offset += int64(len(line))
+		if err != nil {
+			break
+		}
+	}
+	return file.Truncate(offset)
+}
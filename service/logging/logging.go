@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"pricingengine/service/util"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header the request ID is read from and echoed back in
+const RequestIDHeader = "X-Request-Id"
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// Setup method installs a JSON logger writing to w at the given level as the default one
+// The standard log package is routed to it as well, at the info level
+func Setup(w io.Writer, level string) error {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return errors.New("invalid log level: " + level)
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel})))
+	return nil
+}
+
+// WithRequestID method returns a context carrying the request ID, every log written
+// through FromContext with it is tagged with the ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return context.WithValue(ctx, loggerKey{}, FromContext(ctx).With("request_id", id))
+}
+
+// NewRequestID method returns a random ID for the requests that do not come through chi
+func NewRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// RequestID method returns the request ID carried by the context, empty when there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// With method returns a context whose logger adds the given attributes to every log
+func With(ctx context.Context, args ...interface{}) context.Context {
+	return context.WithValue(ctx, loggerKey{}, FromContext(ctx).With(args...))
+}
+
+// FromContext method returns the logger of the context, the default one when there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Middleware carries the ID set by chi's middleware.RequestID into the request context,
+// echoes it in the X-Request-Id response header and logs every request once it is served
+// It has to be mounted after middleware.RequestID, and after the tracing middleware for
+// the logs to carry the trace ID
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := middleware.GetReqID(r.Context())
+		w.Header().Set(RequestIDHeader, id)
+		ctx := WithRequestID(r.Context(), id)
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			ctx = With(ctx, "trace_id", spanContext.TraceID().String())
+		}
+
+		ww := util.WrapResponseWriter(w, r)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := ""
+		if routeCtx := chi.RouteContext(ctx); routeCtx != nil {
+			route = routeCtx.RoutePattern()
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		FromContext(ctx).Log(ctx, level, "request served",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route,
+			"status", status,
+			"bytes", ww.BytesWritten(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pricingengine/service/util"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Registry holds every metric of the engine along with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// RequestDuration observes the latency of every REST and gRPC call by route and status
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pricingengine_request_duration_seconds",
+		Help:    "Latency of the requests served, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// QuotesPriced counts the requests priced successfully by app.GeneratePricing
+	QuotesPriced = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pricingengine_quotes_priced_total",
+		Help: "Number of quotes priced.",
+	})
+
+	// QuotesDeclined counts the declined requests by the factor and the band that declined them
+	QuotesDeclined = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pricingengine_quotes_declined_total",
+		Help: "Number of quotes declined, by factor and band.",
+	}, []string{"factor", "band"})
+
+	// ValidationFailures counts the requests rejected because of an invalid field
+	ValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pricingengine_validation_failures_total",
+		Help: "Number of requests rejected by the validation, by field.",
+	}, []string{"field"})
+
+	// ConfigReloads counts the reloads of the factor documents by ConfigCache, by result
+	ConfigReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pricingengine_config_reloads_total",
+		Help: "Number of reloads of the factor documents, by result.",
+	}, []string{"result"})
+
+	// ConfigVersion is set to 1 for the config version currently active
+	ConfigVersion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pricingengine_config_version_info",
+		Help: "Config version currently active.",
+	}, []string{"version"})
+
+	// RateLimited counts the requests, or batch items, rejected by the rate limiter by class
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pricingengine_rate_limited_total",
+		Help: "Number of requests rejected by the rate limiter, by class.",
+	}, []string{"class"})
+
+	// PromoCodes counts the promo codes of the priced requests, applied or rejected, and the redeemed ones
+	PromoCodes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pricingengine_promo_codes_total",
+		Help: "Number of promo codes received, by result.",
+	}, []string{"result"})
+
+	// JobQueueDepth is the number of repricing jobs waiting for a worker
+	JobQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pricingengine_jobs_queue_depth",
+		Help: "Number of repricing jobs waiting for a worker.",
+	})
+)
+
+// results of a config reload
+const (
+	ReloadSuccess = "success"
+	ReloadFailure = "failure"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RequestDuration,
+		QuotesPriced,
+		QuotesDeclined,
+		ValidationFailures,
+		ConfigReloads,
+		ConfigVersion,
+		RateLimited,
+		PromoCodes,
+		JobQueueDepth,
+	)
+}
+
+// Handler method returns the handler serving the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// SetConfigVersion method marks the given config version as the active one
+func SetConfigVersion(version int) {
+	ConfigVersion.Reset()
+	ConfigVersion.WithLabelValues(strconv.Itoa(version)).Set(1)
+}
+
+// Middleware observes the latency of every request, labelled by the chi route pattern
+// rather than the path so that path parameters do not multiply the series
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := util.WrapResponseWriter(w, r)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if ctx := chi.RouteContext(r.Context()); ctx != nil && len(ctx.RoutePattern()) > 0 {
+			route = ctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		RequestDuration.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// UnaryServerInterceptor observes the latency of every gRPC call, labelled by its full method name
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	RequestDuration.WithLabelValues("GRPC", info.FullMethod, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return res, err
+}
@@ -14,6 +14,7 @@ type DriverAgeFactor struct {
   Age int `json:"age"`
   IsEligible bool `json:"is-eligible"`
   Factor float64 `json:"factor"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
 }
 
 type InsuranceGroupFactor struct {
@@ -21,11 +22,13 @@ type InsuranceGroupFactor struct {
   Group string `json:"group"`
   IsEligible bool `json:"is-eligible"`
   Factor float64 `json:"factor"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
 }
 
 type LicenceValidityFactor struct {
   Length string `json:"length"`
   Factor float64 `json:"factor"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
 }
 
 type RangeConfig struct {
@@ -34,4 +37,277 @@ type RangeConfig struct {
 	IsEligible bool
 	Value float64
 	Label string
+	Operation string `json:",omitempty"` // one of the strategy Operations, multiply when empty
+}
+
+// InteractionFactor is a factor table keyed on several request attributes at once, e.g. the
+// driver age and the insurance group, for the risks that are not the product of the two factors
+// Dimensions names the request attributes, every cell holds one band per dimension
+type InteractionFactor struct {
+  Name string `json:"name"`
+  Dimensions []string `json:"dimensions"`
+  Cells []InteractionCell `json:"cells"`
+}
+
+// InteractionCell is a cell of an InteractionFactor, its bands are in the "start-end" or
+// "start" format of the other factor files, or "*" to match any value
+type InteractionCell struct {
+  Bands []string `json:"bands"`
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// InteractionTable is an InteractionFactor converted for the pricing strategies, its cells
+// are matched in order and the first one matching every dimension applies
+type InteractionTable struct {
+  Name string
+  Dimensions []string
+  Cells []InteractionRange
+}
+
+// InteractionRange is an InteractionCell converted for the pricing strategies
+// Start and End hold the range of every dimension, in the order of the table Dimensions
+type InteractionRange struct {
+  Start []int
+  End []int
+  IsEligible bool
+  Value float64
+  Label string
+  Operation string `json:",omitempty"`
+}
+
+// Vehicle is an entry of the vehicle table, it is found by its Registration or its ABI Code
+// Registered is the date of first registration the vehicle age is computed from
+type Vehicle struct {
+  Registration string `json:"registration,omitempty"`
+  Code string `json:"abi-code,omitempty"`
+  Make string `json:"make,omitempty"`
+  Model string `json:"model,omitempty"`
+  InsuranceGroup int `json:"insurance-group"`
+  Value float64 `json:"value"`
+  Registered string `json:"registered"`
+}
+
+// TemporalFactors are the factors of the time the cover starts at, resolved in the Timezone
+// e.g. "Europe/London", the factors of the matching hour band, day band and holiday all apply
+type TemporalFactors struct {
+  Timezone string `json:"timezone"`
+  Hours []HourFactor `json:"hours"`
+  Days []DayFactor `json:"days"`
+  Holiday *HolidayFactor `json:"holiday,omitempty"`
+}
+
+// HourFactor applies to the covers starting from the hour From up to the hour To excluded,
+// a band with To lower than From wraps around midnight, e.g. from 22 to 6
+type HourFactor struct {
+  From int `json:"from"`
+  To int `json:"to"`
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// DayFactor applies to the covers starting on one of the Days of the week, e.g. "friday"
+type DayFactor struct {
+  Days []string `json:"days"`
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// HolidayFactor applies to the covers starting on a day of the holiday calendar
+type HolidayFactor struct {
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// Holiday is a day of the holiday calendar, Date is in the YYYY-MM-DD format
+type Holiday struct {
+  Date string `json:"date"`
+  Name string `json:"name"`
+}
+
+// PromoCode is a promotional code discounting the premium, Type is the percent-discount or subtract
+// operation applied with its Value. It is valid from ValidFrom to ValidTo included, as YYYY-MM-DD, only
+// for the base rates labelled one of its Durations, all when empty, and the premiums of at least MinPremium
+// MaxUses is the number of quotes the code can be applied to, unlimited when 0
+type PromoCode struct {
+  Code string `json:"code"`
+  Type string `json:"type"`
+  Value float64 `json:"value"`
+  ValidFrom string `json:"valid-from,omitempty"`
+  ValidTo string `json:"valid-to,omitempty"`
+  Durations []string `json:"durations,omitempty"`
+  MinPremium float64 `json:"min-premium,omitempty"`
+  MaxUses int `json:"max-uses,omitempty"`
+  Label string `json:"label"`
+}
+
+// LoyaltyFactor rates the returning customers by their claim-free run, counted in Basis: the "policies"
+// they bound or the "days" since their first policy, both since their last claim. The band with the
+// highest From the run reaches applies, none when the run is below every band
+type LoyaltyFactor struct {
+  Basis string `json:"basis"`
+  Bands []LoyaltyBand `json:"bands"`
+}
+
+// LoyaltyBand applies to the claim-free runs of at least From policies or days
+type LoyaltyBand struct {
+  From int `json:"from"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// DriverCombination tells how the driver age and licence validity factors of the additional drivers are
+// combined with the ones of the main driver, Rule being highest-risk, weighted-average or main-plus-loading
+// MainWeight is the weight of the main driver in the weighted-average, the additional drivers sharing the
+// rest equally, every driver weighing the same when it is 0
+// Loading is applied once per additional driver on top of the factors of the main driver by main-plus-loading
+type DriverCombination struct {
+  Rule string `json:"rule"`
+  MainWeight float64 `json:"main-weight,omitempty"`
+  Loading *DriverLoading `json:"loading,omitempty"`
+}
+
+// DriverLoading is the loading of an additional driver
+type DriverLoading struct {
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// HistoryLoadings holds the loadings of the claims and convictions history of the driver
+type HistoryLoadings struct {
+  Claims ClaimLoadings `json:"claims"`
+  Convictions ConvictionLoadings `json:"convictions"`
+}
+
+// ClaimLoadings are the loadings per claim type, only the claims of the last LookbackYears are loaded
+// and their loadings multiplied together are capped at MaxLoading
+type ClaimLoadings struct {
+  LookbackYears int `json:"lookback-years"`
+  MaxLoading float64 `json:"max-loading"`
+  Types []ClaimLoading `json:"types"`
+}
+
+// ClaimLoading is the loading of a claim type, Type "*" applies to the types not listed
+// LookbackYears overrides the one of the ClaimLoadings when set
+type ClaimLoading struct {
+  Type string `json:"type"`
+  LookbackYears int `json:"lookback-years,omitempty"`
+  FaultFactor float64 `json:"fault-factor"`
+  NonFaultFactor float64 `json:"non-fault-factor"`
+  Decline bool `json:"decline,omitempty"`
+  Label string `json:"label"`
+}
+
+// ConvictionLoadings are the loadings per conviction code, only the convictions of the last LookbackYears
+// are loaded and their loadings multiplied together are capped at MaxLoading
+// MaxPoints declines the drivers with as many penalty points or more, it is not checked when 0
+type ConvictionLoadings struct {
+  LookbackYears int `json:"lookback-years"`
+  MaxLoading float64 `json:"max-loading"`
+  MaxPoints int `json:"max-points,omitempty"`
+  Codes []ConvictionLoading `json:"codes"`
+}
+
+// ConvictionLoading is the loading of a conviction code, Code accepts "*" wildcards, e.g. "DR*"
+// LookbackYears overrides the one of the ConvictionLoadings when set
+type ConvictionLoading struct {
+  Code string `json:"code"`
+  LookbackYears int `json:"lookback-years,omitempty"`
+  Factor float64 `json:"factor"`
+  Decline bool `json:"decline,omitempty"`
+  Label string `json:"label"`
+}
+
+// PostcodeFactor is an entry of the postcode factor, Prefix is a postcode area (e.g. "SW"), a district
+// (e.g. "SW1A"), a sector (e.g. "SW1A 1") or a full postcode
+type PostcodeFactor struct {
+  Prefix string `json:"prefix"`
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// PostcodeRange is a PostcodeFactor converted for the pricing strategies, with its Prefix normalised
+type PostcodeRange struct {
+  Prefix string
+  IsEligible bool
+  Value float64
+  Label string
+  Operation string `json:",omitempty"`
+}
+
+// CategoricalFactor is a factor table keyed on a string attribute of the request, e.g. the occupation
+// Every entry matches an exact value, a set of values or a wildcard pattern, the default entry
+// applies to the values no other entry matches
+type CategoricalFactor struct {
+  Name string `json:"name"`
+  Attribute string `json:"attribute"`
+  Entries []CategoricalEntry `json:"entries"`
+}
+
+// CategoricalEntry is an entry of a CategoricalFactor, exactly one of Value, Values, Pattern or Default is set
+// Pattern accepts "*" for any run of characters and "?" for a single character
+type CategoricalEntry struct {
+  Value string `json:"value,omitempty"`
+  Values []string `json:"values,omitempty"`
+  Pattern string `json:"pattern,omitempty"`
+  Default bool `json:"default,omitempty"`
+  IsEligible bool `json:"is-eligible"`
+  Factor float64 `json:"factor"`
+  Label string `json:"label"`
+  Operation string `json:"operation,omitempty"` // how the factor is applied, multiply when empty
+}
+
+// CategoricalTable is a CategoricalFactor converted for the pricing strategies, its entries are
+// matched in order and Default applies when none of them matches
+type CategoricalTable struct {
+  Name string
+  Attribute string
+  Entries []CategoricalRange
+  Default *CategoricalRange
+}
+
+// CategoricalRange is a CategoricalEntry converted for the pricing strategies
+// Values and Pattern are normalised the way the request values are
+type CategoricalRange struct {
+  Values []string
+  Pattern string
+  IsEligible bool
+  Value float64
+  Label string
+  Operation string `json:",omitempty"`
+}
+
+type ConfigVersion struct {
+  Version int `json:"version"`
+  CreatedAt string `json:"created_at,omitempty"`
+  Comment string `json:"comment,omitempty"`
+  Files []string `json:"files"`
+  Parent int `json:"parent"`
+  Active bool `json:"active"`
+}
+
+type ConfigStatus struct {
+  Ready bool `json:"ready"` // every factor document was loaded and validated at least once
+  Version int `json:"version"`
+  LoadedAt string `json:"loaded_at,omitempty"`
+  LastReloadAt string `json:"last_reload_at,omitempty"`
+  LastReloadError string `json:"last_reload_error,omitempty"` // error of the last reload, empty when it succeeded
+  Files []ConfigFileStatus `json:"files,omitempty"`
+}
+
+type ConfigFileStatus struct {
+  File string `json:"file"`
+  LoadedAt string `json:"loaded_at"`
 }
@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// document is the OpenAPI 3 description of every endpoint served by the engine
+//
+//go:embed openapi.json
+var document []byte
+
+// Spec is the parsed OpenAPI document, used both to serve it and to validate request bodies
+type Spec struct {
+	Document map[string]interface{}
+}
+
+// SchemaError lists every place a request body does not match its schema
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "request body does not match the schema: " + strings.Join(e.Problems, "; ")
+}
+
+// Load method parses the embedded OpenAPI document
+func Load() (*Spec, error) {
+	spec := Spec{}
+	if err := json.Unmarshal(document, &spec.Document); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// MustLoad method is like Load but panics when the embedded document can not be parsed
+func MustLoad() *Spec {
+	spec, err := Load()
+	if err != nil {
+		panic("openapi: invalid embedded document: " + err.Error())
+	}
+	return spec
+}
+
+// Handler conforms to http.HandlerFunc and serves the OpenAPI document as it is
+func (s *Spec) Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// Middleware validates JSON request bodies against the schema of the matching operation
+// before they reach the handlers, a body that does not match is answered with a 400
+// listing the problems. Requests without a JSON schema in the document are passed through.
+func (s *Spec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema := s.requestSchema(r)
+		if schema == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				w.Write([]byte(fmt.Sprintf("request body should be at most %d bytes", tooLarge.Limit)))
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+		if err := s.ValidateBody(schema, body); err != nil {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Schema method returns the named component schema
+func (s *Spec) Schema(name string) map[string]interface{} {
+	return s.resolve(map[string]interface{}{"$ref": "#/components/schemas/" + name})
+}
+
+// ValidateBody method checks the JSON document against the given schema
+// returns a *SchemaError when it does not match
+func (s *Spec) ValidateBody(schema map[string]interface{}, body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &SchemaError{Problems: []string{"invalid JSON: " + err.Error()}}
+	}
+	problems := s.validate(schema, value, "body")
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// requestSchema finds the JSON request body schema of the operation matching the request
+// returns nil when the request is not JSON or the operation takes no JSON body
+func (s *Spec) requestSchema(r *http.Request) map[string]interface{} {
+	contentType := r.Header.Get("Content-Type")
+	if len(contentType) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != "application/json" {
+			return nil
+		}
+	}
+	operation := s.operation(r.Method, r.URL.Path)
+	if operation == nil {
+		return nil
+	}
+	requestBody := s.resolve(asObject(operation["requestBody"]))
+	media := asObject(asObject(requestBody["content"])["application/json"])
+	if media == nil {
+		return nil
+	}
+	return asObject(media["schema"])
+}
+
+// operation finds the operation of the document matching the method and the path
+func (s *Spec) operation(method string, path string) map[string]interface{} {
+	paths := asObject(s.Document["paths"])
+	for template, item := range paths {
+		if matchPath(template, path) {
+			return asObject(asObject(item)[strings.ToLower(method)])
+		}
+	}
+	return nil
+}
+
+// matchPath tells whether the path matches the template, where {name} matches a single segment
+func matchPath(template string, path string) bool {
+	templateParts := strings.Split(strings.Trim(template, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateParts) != len(pathParts) {
+		return false
+	}
+	for i := range templateParts {
+		if strings.HasPrefix(templateParts[i], "{") && strings.HasSuffix(templateParts[i], "}") {
+			continue
+		}
+		if templateParts[i] != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// resolve follows a local $ref of the document
+func (s *Spec) resolve(schema map[string]interface{}) map[string]interface{} {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	var current interface{} = s.Document
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		current = asObject(current)[part]
+	}
+	return s.resolve(asObject(current))
+}
+
+// validate checks the value against the subset of JSON schema used by the document:
+// type, nullable, enum, properties, required, additionalProperties and items
+func (s *Spec) validate(schema map[string]interface{}, value interface{}, at string) []string {
+	schema = s.resolve(schema)
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schema["type"] == nil {
+			return nil
+		}
+		return []string{at + " should not be null"}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !contains(enum, value) {
+		return []string{fmt.Sprintf("%s should be one of %v", at, enum)}
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " should be an object"}
+		}
+		return s.validateObject(schema, object, at)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{at + " should be an array"}
+		}
+		problems := []string{}
+		items := asObject(schema["items"])
+		for i, item := range array {
+			problems = append(problems, s.validate(items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return problems
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{at + " should be a string"}
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			return []string{at + " should be an integer"}
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return []string{at + " should be a number"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{at + " should be a boolean"}
+		}
+	}
+	return nil
+}
+
+// validateObject checks the required, declared and additional properties of an object
+func (s *Spec) validateObject(schema map[string]interface{}, object map[string]interface{}, at string) []string {
+	problems := []string{}
+	properties := asObject(schema["properties"])
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s is required", at, name))
+			}
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := properties[name]; ok {
+			problems = append(problems, s.validate(asObject(property), object[name], at+"."+name)...)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				problems = append(problems, fmt.Sprintf("%s.%s is not a known field", at, name))
+			}
+		case map[string]interface{}:
+			problems = append(problems, s.validate(additional, object[name], at+"."+name)...)
+		}
+	}
+	return problems
+}
+
+func asObject(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	return object
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package promo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// UsageStore counts the bound quotes every promo code was redeemed for, so that their usage limits hold
+// The counts are kept in File, written atomically on every use so that they survive a restart,
+// a store without File only counts in memory
+type UsageStore struct {
+	File string
+
+	mutex  sync.Mutex
+	uses   map[string]int
+	loaded bool
+}
+
+// Uses method returns the number of times the code was redeemed
+func (s *UsageStore) Uses(code string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+	return s.uses[code], nil
+}
+
+// Redeem method records a use of the code unless it was already used limit times, a limit of 0 being unlimited
+// returns false when the limit is reached, the use is then not recorded
+func (s *UsageStore) Redeem(code string, limit int) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.load(); err != nil {
+		return false, err
+	}
+	if limit > 0 && s.uses[code] >= limit {
+		return false, nil
+	}
+	s.uses[code]++
+	if err := s.save(); err != nil {
+		s.uses[code]--
+		return false, err
+	}
+	return true, nil
+}
+
+// load reads the counts from File the first time they are needed
+func (s *UsageStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	s.uses = map[string]int{}
+	if len(s.File) > 0 {
+		data, err := ioutil.ReadFile(s.File)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &s.uses); err != nil {
+				return err
+			}
+		}
+	}
+	s.loaded = true
+	return nil
+}
+
+// save atomically writes the counts to File
+func (s *UsageStore) save() error {
+	if len(s.File) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(s.uses)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(s.File+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(s.File+".tmp", s.File)
+}
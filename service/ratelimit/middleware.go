@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pricingengine/service/auth"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The rate limit headers of draft-ietf-httpapi-ratelimit-headers, sent on every limited
+// response, along with Retry-After on the 429 responses. gRPC calls get the same lower case metadata.
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+)
+
+// Client method returns the client the buckets of a request are kept for: the ID of the
+// authenticated caller, or "ip:" followed by the remote address of an anonymous one
+func Client(ctx context.Context, remoteAddr string) string {
+	if principal := auth.FromContext(ctx); principal != nil {
+		return principal.ClientID
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + remoteAddr
+}
+
+// Middleware returns a middleware taking a token of the class for every request, requests
+// finding the bucket of their client empty are answered with a 429 and a Retry-After header
+// It has to be mounted after the authentication for the buckets to be kept per client
+func (l *Limiter) Middleware(class string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := l.Take(w, r, class, 1); err != nil {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ExceededError is returned by Take when the bucket of the client holds fewer tokens than asked for
+type ExceededError struct {
+	Class  string
+	Tokens int
+	Result Result
+}
+
+func (e *ExceededError) Error() string {
+	if e.Result.RetryAfter == 0 {
+		return fmt.Sprintf("%d %s items exceed the burst of %d", e.Tokens, e.Class, e.Result.Limit)
+	}
+	return fmt.Sprintf("rate limit of %s requests exceeded, retry in %ss", e.Class, ceilSeconds(e.Result.RetryAfter))
+}
+
+// Take method takes n tokens of the class from the bucket of the client of the request, and sets
+// the rate limit headers of the response along with Retry-After when they are not all available
+// returns an *ExceededError to answer with a 429 in that case
+func (l *Limiter) Take(w http.ResponseWriter, r *http.Request, class string, n int) error {
+	client := Client(r.Context(), r.RemoteAddr)
+	result := l.Allow(client, class, n)
+	if result.Limited() {
+		setHeaders(w.Header().Set, result)
+	}
+	if result.Allowed {
+		return nil
+	}
+	rejected(r.Context(), client, class, result)
+	if result.RetryAfter > 0 {
+		w.Header().Set(RetryAfterHeader, ceilSeconds(result.RetryAfter))
+	}
+	return &ExceededError{Class: class, Tokens: n, Result: result}
+}
+
+// Cost tells the class a gRPC method is limited in and the number of tokens a call takes
+type Cost struct {
+	Class  string
+	Tokens func(req interface{}) int // a single token is taken when nil
+}
+
+// UnaryServerInterceptor returns an interceptor taking the tokens of the cost of every gRPC
+// call, calls finding the bucket of their client short are answered with ResourceExhausted
+// and a retry-after header. Methods missing from costs are not limited.
+func (l *Limiter) UnaryServerInterceptor(costs map[string]Cost) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		cost, ok := costs[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		tokens := 1
+		if cost.Tokens != nil {
+			tokens = cost.Tokens(req)
+		}
+		remoteAddr := ""
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		client := Client(ctx, remoteAddr)
+		result := l.Allow(client, cost.Class, tokens)
+		if !result.Limited() {
+			return handler(ctx, req)
+		}
+
+		md := metadata.MD{}
+		setHeaders(func(key string, value string) { md.Set(key, value) }, result)
+		if result.Allowed {
+			grpc.SetHeader(ctx, md)
+			return handler(ctx, req)
+		}
+		rejected(ctx, client, cost.Class, result)
+		if result.RetryAfter == 0 {
+			grpc.SetHeader(ctx, md)
+			return nil, status.Errorf(codes.ResourceExhausted, "%d %s items exceed the burst of %d", tokens, cost.Class, result.Limit)
+		}
+		md.Set(RetryAfterHeader, ceilSeconds(result.RetryAfter))
+		grpc.SetHeader(ctx, md)
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit of %s requests exceeded, retry in %ss", cost.Class, ceilSeconds(result.RetryAfter))
+	}
+}
+
+func setHeaders(set func(key string, value string), result Result) {
+	set(LimitHeader, strconv.Itoa(result.Limit))
+	set(RemainingHeader, strconv.Itoa(result.Remaining))
+	set(ResetHeader, ceilSeconds(result.Reset))
+}
+
+func rejected(ctx context.Context, client string, class string, result Result) {
+	metrics.RateLimited.WithLabelValues(class).Inc()
+	logging.FromContext(ctx).Info("Rate limit exceeded", "client", client, "class", class, "retry_after", result.RetryAfter.String())
+}
+
+// ceilSeconds writes the duration as a whole number of seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,215 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The classes of requests limited separately, every client has a bucket per class
+const (
+	ClassQuote       = "quote"        // single quotes and repricing job calls
+	ClassBatchItem   = "batch_item"   // every item of a gRPC batch or request of a stream or repricing job
+	ClassConfigRead  = "config_read"  // reads of the factor ranges and the config status
+	ClassConfigAdmin = "config_admin" // calls of the admin endpoints editing the factor tables
+)
+
+// Classes lists every known class
+var Classes = []string{ClassQuote, ClassBatchItem, ClassConfigRead, ClassConfigAdmin}
+
+// sweepInterval is how often the buckets left full, i.e. idle, are dropped
+const sweepInterval = time.Minute
+
+// Limit is the token bucket of a class: it holds at most Burst tokens and refills at Rate tokens per second
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Limits holds the Limit of every class, a class left out is not limited
+type Limits map[string]Limit
+
+// Config is the content of the rate limit file
+// Default applies to every client, Clients overrides it class by class for the clients it names.
+// A client is the ID of the authenticated caller, or "ip:" followed by the address of an anonymous one.
+type Config struct {
+	Default Limits            `json:"default"`
+	Clients map[string]Limits `json:"clients"`
+}
+
+// Result is the outcome of taking tokens from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // burst of the bucket, 0 when the class is not limited
+	Remaining  int           // tokens left in the bucket
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until the tokens asked for are available, when not allowed
+}
+
+// Limited method reports whether the class is limited at all for the client
+func (r Result) Limited() bool {
+	return r.Limit > 0
+}
+
+// bucket is the token bucket of a client and class
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a token bucket per client and class, it is safe for concurrent use
+type Limiter struct {
+	config    Config
+	now       func() time.Time
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New method returns a Limiter applying the config, after checking every limit is usable
+func New(config Config) (*Limiter, error) {
+	problems := config.Default.check("default")
+	for client, limits := range config.Clients {
+		problems = append(problems, limits.check("clients."+client)...)
+	}
+	if len(problems) > 0 {
+		return nil, errors.New("invalid rate limits: " + strings.Join(problems, "; "))
+	}
+	return &Limiter{config: config, now: time.Now, buckets: map[string]*bucket{}}, nil
+}
+
+// Load method reads the JSON rate limit file and returns the Limiter applying it
+func Load(filename string) (*Limiter, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	config := Config{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid rate limit file %s: %v", filename, err)
+	}
+	return New(config)
+}
+
+// SetClock method replaces the clock of the limiter, for the tests
+func (l *Limiter) SetClock(now func() time.Time) {
+	l.now = now
+}
+
+// LimitFor method returns the limit of the class for the client, and false when it is not limited
+func (l *Limiter) LimitFor(client string, class string) (Limit, bool) {
+	if limit, ok := l.config.Clients[client][class]; ok {
+		return limit, true
+	}
+	limit, ok := l.config.Default[class]
+	return limit, ok
+}
+
+// Allow method takes n tokens from the bucket of the client and class when it holds them
+// Nothing is taken when it does not, and RetryAfter tells when it will. A cost above the
+// burst of the bucket is never allowed, and RetryAfter is then left at 0.
+func (l *Limiter) Allow(client string, class string, n int) Result {
+	limit, ok := l.LimitFor(client, class)
+	if !ok {
+		return Result{Allowed: true}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.now()
+	l.sweep(now)
+	key := client + "\x00" + class
+	b := l.buckets[key]
+	if b == nil || b.limit != limit {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+
+	result := Result{Limit: limit.Burst}
+	if float64(n) <= b.tokens {
+		b.tokens -= float64(n)
+		result.Allowed = true
+	} else if n <= limit.Burst {
+		result.RetryAfter = seconds((float64(n) - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = seconds((float64(limit.Burst) - b.tokens) / limit.Rate)
+	return result
+}
+
+// Wait method blocks until n tokens of the bucket of the client and class are available and takes them
+// returns the context error when it is done first, or an error when n is above the burst of the bucket
+func (l *Limiter) Wait(ctx context.Context, client string, class string, n int) error {
+	for {
+		result := l.Allow(client, class, n)
+		if result.Allowed {
+			return nil
+		}
+		if result.RetryAfter == 0 {
+			return fmt.Errorf("%d %s tokens exceed the burst of %d", n, class, result.Limit)
+		}
+		timer := time.NewTimer(result.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// refill adds the tokens earned since the bucket was last used
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	}
+	b.last = now
+}
+
+// sweep drops the buckets that refilled completely, a new full bucket is created on their next use
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (limits Limits) check(name string) []string {
+	problems := []string{}
+	for class, limit := range limits {
+		known := false
+		for _, candidate := range Classes {
+			known = known || candidate == class
+		}
+		switch {
+		case !known:
+			problems = append(problems, name+"."+class+": unknown class, should be one of "+strings.Join(Classes, ", "))
+		case limit.Rate <= 0:
+			problems = append(problems, name+"."+class+".rate: should be positive")
+		case limit.Burst < 1:
+			problems = append(problems, name+"."+class+".burst: should be at least 1")
+		}
+	}
+	return problems
+}
+
+// seconds converts a number of seconds into a duration, rounded up to the millisecond
+func seconds(value float64) time.Duration {
+	return time.Duration(math.Ceil(value*1000)) * time.Millisecond
+}
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pricingengine"
+
+	"github.com/go-chi/chi"
+)
+
+// AdminAuthenticator is the middleware guarding the admin endpoints when authentication is disabled,
+// once it is enabled they require the config:admin scope and the AdminToken is not accepted any more
+// The caller has to send the configured AdminToken as a bearer token, and the admin endpoints stay
+// disabled altogether when no token is configured
+func (rpc *RPC) AdminAuthenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(rpc.AdminToken) == 0 {
+			statusResponse(w, http.StatusForbidden, errors.New("admin endpoints are disabled"))
+			return
+		}
+		scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(rpc.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			statusResponse(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListConfigVersions is a GET method listing the stored versions of the factor documents
+func (rpc *RPC) ListConfigVersions(w http.ResponseWriter, r *http.Request) {
+	res, err := rpc.App.ListConfigVersions(r.Context())
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, res)
+}
+
+// UploadConfigSet is a POST method replacing one or more factor documents at once
+// The body is a pricingengine.ConfigUploadRequest, the new set becomes the active version
+func (rpc *RPC) UploadConfigSet(w http.ResponseWriter, r *http.Request) {
+	var input pricingengine.ConfigUploadRequest
+	err := rpc.decodeBody(r, &input)
+	if err != nil {
+		response(w, err)
+		return
+	}
+
+	res, err := rpc.App.UploadConfigSet(r.Context(), &input)
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, res)
+}
+
+// UploadFactorTable is a PUT method replacing the single factor document named in the path
+// The body is the factor table itself, in the same format as the file under config/
+func (rpc *RPC) UploadFactorTable(w http.ResponseWriter, r *http.Request) {
+	body, err := rpc.readBody(r)
+	if err != nil {
+		response(w, err)
+		return
+	}
+
+	res, err := rpc.App.UploadFactorTable(r.Context(), chi.URLParam(r, "name"), body, r.URL.Query().Get("comment"))
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, res)
+}
+
+// ActivateConfigVersion is a POST method making the version in the path the live one
+// This is used to roll back to any prior version as well
+func (rpc *RPC) ActivateConfigVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		statusResponse(w, http.StatusBadRequest, errors.New("version should be a number"))
+		return
+	}
+
+	res, err := rpc.App.ActivateConfigVersion(r.Context(), version)
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, res)
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"errors"
+	"net/http"
+
+	"pricingengine"
+	"pricingengine/service/customers"
+
+	"github.com/go-chi/chi"
+)
+
+// GetCustomerHistory is a GET method returning the policies and claims recorded for a customer,
+// an unknown customer having an empty history
+func (rpc *RPC) GetCustomerHistory(w http.ResponseWriter, r *http.Request) {
+	store := rpc.customerStore(w)
+	if store == nil {
+		return
+	}
+	history, err := store.Load(chi.URLParam(r, "id"))
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, history)
+}
+
+// RecordCustomerPolicy is a POST method recording a policy bound by a customer, taken into account
+// by the loyalty factor of their next quotes
+// Answers 201 with the updated history
+func (rpc *RPC) RecordCustomerPolicy(w http.ResponseWriter, r *http.Request) {
+	store := rpc.customerStore(w)
+	if store == nil {
+		return
+	}
+	var input pricingengine.CustomerPolicy
+	if err := rpc.decodeBody(r, &input); err != nil {
+		response(w, err)
+		return
+	}
+	history, err := store.RecordPolicy(chi.URLParam(r, "id"), input)
+	if err != nil {
+		response(w, err)
+		return
+	}
+	jsonResponse(w, http.StatusCreated, history)
+}
+
+// RecordCustomerClaim is a POST method recording a claim made by a customer, which restarts
+// the claim-free run of the loyalty factor
+// Answers 201 with the updated history
+func (rpc *RPC) RecordCustomerClaim(w http.ResponseWriter, r *http.Request) {
+	store := rpc.customerStore(w)
+	if store == nil {
+		return
+	}
+	var input pricingengine.Claim
+	if err := rpc.decodeBody(r, &input); err != nil {
+		response(w, err)
+		return
+	}
+	history, err := store.RecordClaim(chi.URLParam(r, "id"), input)
+	if err != nil {
+		response(w, err)
+		return
+	}
+	jsonResponse(w, http.StatusCreated, history)
+}
+
+// customerStore returns the store of the customer histories, answering 404 when the App keeps none
+func (rpc *RPC) customerStore(w http.ResponseWriter) *customers.Store {
+	if rpc.App == nil || rpc.App.Customers == nil {
+		statusResponse(w, http.StatusNotFound, errors.New("customer histories are not kept"))
+		return nil
+	}
+	return rpc.App.Customers
+}
@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"pricingengine/service/util"
+)
+
+// DefaultMaxBodyBytes is the size a request body is capped at when RPC.MaxBodyBytes is not set
+const DefaultMaxBodyBytes = 1024 * 1024
+
+// BodyError is returned when a request body can not be decoded, Status is the 400, 413 or 415 to answer with
+type BodyError struct {
+	Status int
+	Reason string
+}
+
+func (e *BodyError) Error() string {
+	return e.Reason
+}
+
+// LimitBody is the middleware capping the bodies of the requests at MaxBodyBytes, reading past
+// it fails and the request is answered with a 413. The newline delimited and multipart bodies
+// of the stream and the repricing jobs are left unbounded, they are read a record at a time.
+func (rpc *RPC) LimitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if r.Body != nil && mediaType != "application/x-ndjson" && mediaType != "multipart/form-data" {
+			r.Body = http.MaxBytesReader(w, r.Body, rpc.maxBodyBytes())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeBody reads the application/json body of the request into target
+// The body has to hold a single JSON object, not null, with only the fields of target
+// returns a *BodyError telling why it does not
+func (rpc *RPC) decodeBody(r *http.Request, target interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return &BodyError{Status: http.StatusUnsupportedMediaType, Reason: "Content-Type should be application/json"}
+	}
+	body, err := rpc.readBody(r)
+	if err != nil {
+		return err
+	}
+	return decodeStrict(body, target)
+}
+
+// readBody reads the whole body of the request, up to MaxBodyBytes, and leaves a copy of it in the request
+// returns a *BodyError when it is larger or can not be read
+func (rpc *RPC) readBody(r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, rpc.maxBodyBytes()))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, &BodyError{Status: http.StatusRequestEntityTooLarge, Reason: "request body should be at most " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes"}
+		}
+		return nil, &BodyError{Status: http.StatusBadRequest, Reason: "error reading request body: " + err.Error()}
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	return body, nil
+}
+
+// decodeStrict decodes a single JSON object into target as util.DecodeStrict does
+// returns a *BodyError answered with a 400 when it can not
+func decodeStrict(data []byte, target interface{}) error {
+	if err := util.DecodeStrict(data, target); err != nil {
+		return &BodyError{Status: http.StatusBadRequest, Reason: err.Error()}
+	}
+	return nil
+}
+
+func (rpc *RPC) maxBodyBytes() int64 {
+	if rpc.MaxBodyBytes > 0 {
+		return rpc.MaxBodyBytes
+	}
+	return DefaultMaxBodyBytes
+}
@@ -3,15 +3,53 @@ package rpc
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 
 	"pricingengine"
 	"pricingengine/service/app"
+	"pricingengine/service/auth"
+	"pricingengine/service/config"
+	"pricingengine/service/customers"
+	"pricingengine/service/jobs"
+	"pricingengine/service/ratelimit"
 )
 
 type RPC struct {
 	App *app.App
+	AdminToken string // bearer token expected by the admin endpoints when Auth is nil, they are disabled when empty
+	Auth *auth.Authenticator // authenticates the callers, every endpoint is open when nil
+	Limiter *ratelimit.Limiter // limits the requests of every client, nothing is limited when nil
+	MaxBodyBytes int64 // size the request bodies are capped at, DefaultMaxBodyBytes when 0
+	Jobs *jobs.Manager
+}
+
+// Authenticator is the middleware resolving the credentials of the requests, see auth.Authenticator.Middleware
+// It passes every request through when authentication is disabled
+func (rpc *RPC) Authenticator(next http.Handler) http.Handler {
+	if rpc.Auth == nil {
+		return next
+	}
+	return rpc.Auth.Middleware(next)
+}
+
+// Authorize method returns the middleware requiring the caller to be granted the scope
+// It passes every request through when authentication is disabled
+func (rpc *RPC) Authorize(scope string) func(http.Handler) http.Handler {
+	if rpc.Auth == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return auth.Require(scope)
+}
+
+// RateLimit method returns the middleware taking a token of the class from the bucket of the caller
+// It passes every request through when rate limiting is disabled
+func (rpc *RPC) RateLimit(class string) func(http.Handler) http.Handler {
+	if rpc.Limiter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return rpc.Limiter.Middleware(class)
 }
 
 // GeneratePricing conforms to http.HandlerFunc and handles request logic
@@ -23,23 +61,14 @@ type RPC struct {
 // not over-engineer this part; we're looking for a single functional endpoint,
 // not a framework!
 func (rpc *RPC) GeneratePricing(w http.ResponseWriter, r *http.Request) {
-	body, err := ioutil.ReadAll(r.Body)
+	var input pricingengine.GeneratePricingRequest
+	err := rpc.decodeBody(r, &input)
 	if err != nil {
 		response(w, err)
 		return
 	}
 
-	r.Body.Close()
-	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-
-	var input *pricingengine.GeneratePricingRequest
-	err = json.Unmarshal(body, &input)
-	if err != nil {
-		response(w, err)
-		return
-	}
-
-	res, err := rpc.App.GeneratePricing(r.Context(), input)
+	res, err := rpc.App.GeneratePricing(r.Context(), &input)
 	if err != nil {
 		response(w, err)
 		return
@@ -79,6 +108,11 @@ func response(w http.ResponseWriter, res interface{}) {
 		return
 	}
 
+	jsonResponse(w, http.StatusOK, res)
+}
+
+// jsonResponse writes the response as JSON to the client with the given status
+func jsonResponse(w http.ResponseWriter, status int, res interface{}) {
 	resBody, err := json.Marshal(res)
 	if err != nil {
 		errorResponse(w, err)
@@ -86,12 +120,40 @@ func response(w http.ResponseWriter, res interface{}) {
 	}
 
 	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	w.Write(resBody)
 }
 
 // errorResponse writes out an error to the client as plaintext
 func errorResponse(w http.ResponseWriter, err error) {
-	w.WriteHeader(http.StatusInternalServerError)
+	statusResponse(w, errorStatus(err), err)
+}
+
+// statusResponse writes out an error to the client as plaintext with the given status
+func statusResponse(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
 	w.Write([]byte(err.Error()))
 }
+
+// errorStatus maps the errors surfaced by the application to the HTTP status to answer with
+func errorStatus(err error) int {
+	var validationErr *config.ValidationError
+	var bodyErr *BodyError
+	var exceededErr *ratelimit.ExceededError
+	var promoErr *app.PromoError
+	switch {
+	case errors.As(err, &bodyErr):
+		return bodyErr.Status
+	case errors.As(err, &exceededErr):
+		return http.StatusTooManyRequests
+	case errors.As(err, &validationErr), errors.Is(err, customers.ErrInvalidCustomerID), errors.Is(err, customers.ErrInvalidRecord):
+		return http.StatusBadRequest
+	case errors.Is(err, config.ErrVersionNotFound), errors.Is(err, jobs.ErrJobNotFound), errors.Is(err, app.ErrPromoCodeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, jobs.ErrJobFinished), errors.Is(err, app.ErrPromoCodeUsedUp):
+		return http.StatusConflict
+	case errors.As(err, &promoErr):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"pricingengine"
+	"pricingengine/service/logging"
+	"pricingengine/service/ratelimit"
+)
+
+// maxStreamLineSize caps a single newline delimited request so a runaway line can not exhaust memory
+const maxStreamLineSize = 1024 * 1024
+
+// GeneratePricingStream conforms to http.HandlerFunc and prices a newline delimited
+// stream of GeneratePricingRequest objects, writing one GeneratePricingResponse line per
+// input line as soon as it is priced.
+// Only one line is held in memory at a time and the next line is read once the previous
+// response has been flushed, so a slow client naturally slows down the reading as well.
+// The stream stops as soon as the client goes away and the request context is cancelled.
+// A line that can not be decoded is answered with a declined response carrying the reason.
+// Every line takes a batch item token of the caller, the first one is taken when the stream
+// is opened and the next ones wait for their token, slowing the stream down to the rate limit.
+// A body that is not sent as application/x-ndjson is answered with a 415 before it is read.
+func (rpc *RPC) GeneratePricingStream(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-ndjson" {
+		errorResponse(w, &BodyError{Status: http.StatusUnsupportedMediaType, Reason: "Content-Type should be application/x-ndjson"})
+		return
+	}
+	controller := http.NewResponseController(w)
+	// allow reading the rest of the request body after the first response line is written
+	controller.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	logger := logging.FromContext(ctx)
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	encoder := json.NewEncoder(w)
+	count := 0
+	client := ratelimit.Client(ctx, r.RemoteAddr)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			logger.Info("Stopping GeneratePricingStream, request cancelled", "records", count)
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if rpc.Limiter != nil && count > 0 {
+			if err := rpc.Limiter.Wait(ctx, client, ratelimit.ClassBatchItem, 1); err != nil {
+				logger.Info("Stopping GeneratePricingStream, request cancelled while rate limited", "records", count)
+				return
+			}
+		}
+		res := rpc.priceStreamLine(r, line)
+		if err := encoder.Encode(res); err != nil {
+			logger.Error("error writing GeneratePricingStream record", "error", err)
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			logger.Error("error flushing GeneratePricingStream record", "error", err)
+			return
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Error("error reading GeneratePricingStream body", "error", err)
+		encoder.Encode(&pricingengine.GeneratePricingResponse{Message: "Error reading request stream: " + err.Error()})
+	}
+}
+
+// priceStreamLine decodes and prices a single line of the stream
+func (rpc *RPC) priceStreamLine(r *http.Request, line []byte) *pricingengine.GeneratePricingResponse {
+	input := pricingengine.GeneratePricingRequest{}
+	if err := decodeStrict(line, &input); err != nil {
+		return &pricingengine.GeneratePricingResponse{Message: "Invalid request: " + err.Error()}
+	}
+	res, err := rpc.App.GeneratePricing(r.Context(), &input)
+	if err != nil {
+		return &pricingengine.GeneratePricingResponse{Input: input, Message: err.Error()}
+	}
+	return res
+}
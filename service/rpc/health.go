@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"net/http"
+)
+
+// Healthz is a GET method answering as long as the process is able to serve requests
+func (rpc *RPC) Healthz(w http.ResponseWriter, r *http.Request) {
+	response(w, map[string]string{"status": "ok"})
+}
+
+// Readyz is a GET method answering 503 until every factor document was loaded and validated
+// The body reports the loaded config version and the error of the last reload, if any
+// It only reads the current state, the documents are never loaded on behalf of the probe
+func (rpc *RPC) Readyz(w http.ResponseWriter, r *http.Request) {
+	status := rpc.App.LoadedConfigStatus()
+	status.Files = nil
+	if !status.Ready {
+		jsonResponse(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	response(w, status)
+}
+
+// ConfigStatus is a GET method reporting the load state of the config along with
+// the time every factor document of the active version was loaded at
+func (rpc *RPC) ConfigStatus(w http.ResponseWriter, r *http.Request) {
+	response(w, rpc.App.ConfigStatus(r.Context()))
+}
@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"net/http"
+
+	"pricingengine"
+
+	"github.com/go-chi/chi"
+)
+
+// RedeemPromoCode is a POST method recording a use of a promo code once the quote it was applied
+// to is bound, quotes only check the code has uses left
+// The body is a pricingengine.PromoRedemption naming the quote and the price that was bound
+// Answers with the redeemed promo, a 404 for an unknown code, a 422 when it does not apply to the
+// quote and a 409 when it has reached its usage limit
+func (rpc *RPC) RedeemPromoCode(w http.ResponseWriter, r *http.Request) {
+	input := pricingengine.PromoRedemption{}
+	if err := rpc.decodeBody(r, &input); err != nil {
+		response(w, err)
+		return
+	}
+	promo, err := rpc.App.RedeemPromoCode(r.Context(), chi.URLParam(r, "code"), &input)
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, promo)
+}
@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"pricingengine"
+	"pricingengine/service/logging"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recoverer is the middleware turning a panic of the handlers into a 500 with a
+// pricingengine.ErrorResponse body, instead of dropping the connection
+// The panic is logged with its stack and recorded on the span of the request. It has to be
+// mounted after the logging middleware for the response to carry the request ID.
+func (rpc *RPC) Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// the handler asked for the connection to be dropped
+				panic(recovered)
+			}
+			ctx := r.Context()
+			logging.FromContext(ctx).Error("Recovered from panic", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(fmt.Errorf("panic: %v", recovered))
+			span.SetStatus(codes.Error, "panic")
+			jsonResponse(w, http.StatusInternalServerError, pricingengine.ErrorResponse{
+				Code:      "internal_error",
+				Message:   "the request could not be handled",
+				RequestID: logging.RequestID(ctx),
+			})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
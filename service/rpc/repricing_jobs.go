@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"pricingengine"
+	"pricingengine/service/auth"
+	"pricingengine/service/ratelimit"
+
+	"github.com/go-chi/chi"
+)
+
+// SubmitRepricingJob is a POST method queueing an asynchronous repricing job
+// The requests are either sent as a pricingengine.RepricingJobRequest JSON body, as a
+// newline delimited application/x-ndjson body, or as such a file in the "file" field
+// of a multipart/form-data upload
+// Answers 202 with the job, whose progress is then polled with GetRepricingJob
+// The job belongs to the authenticated caller, other clients get a 404 for it
+// Every request of the job takes a batch item token of the caller once the job is stored,
+// a job over the limit is dropped and answered with a 429. A job of more requests than the
+// batch item burst of the caller could never be admitted, it is answered with a 413 instead.
+func (rpc *RPC) SubmitRepricingJob(w http.ResponseWriter, r *http.Request) {
+	owner := jobOwner(r)
+	var admit func(total int) error
+	if rpc.Limiter != nil {
+		admit = func(total int) error {
+			if err := rpc.jobBurstError(r, total); err != nil {
+				return err
+			}
+			return rpc.Limiter.Take(w, r, ratelimit.ClassBatchItem, total)
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var job *pricingengine.RepricingJob
+	var err error
+	switch mediaType {
+	case "application/x-ndjson":
+		job, err = rpc.Jobs.SubmitFile(owner, r.Body, admit)
+	case "multipart/form-data":
+		var file io.ReadCloser
+		file, _, err = r.FormFile("file")
+		if err != nil {
+			statusResponse(w, http.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+		job, err = rpc.Jobs.SubmitFile(owner, file, admit)
+	default:
+		input := pricingengine.RepricingJobRequest{}
+		if err = rpc.decodeBody(r, &input); err != nil {
+			response(w, err)
+			return
+		}
+		if len(input.Requests) == 0 {
+			statusResponse(w, http.StatusBadRequest, errors.New("requests cannot be empty"))
+			return
+		}
+		if err = rpc.jobBurstError(r, len(input.Requests)); err != nil {
+			response(w, err)
+			return
+		}
+		job, err = rpc.Jobs.Submit(owner, input.Requests, admit)
+	}
+	if err != nil {
+		response(w, err)
+		return
+	}
+	jsonResponse(w, http.StatusAccepted, job)
+}
+
+// jobBurstError returns a *BodyError answered with a 413 when a job of total requests takes more
+// batch item tokens than the bucket of the caller can ever hold
+func (rpc *RPC) jobBurstError(r *http.Request, total int) error {
+	if rpc.Limiter == nil {
+		return nil
+	}
+	limit, ok := rpc.Limiter.LimitFor(ratelimit.Client(r.Context(), r.RemoteAddr), ratelimit.ClassBatchItem)
+	if !ok || total <= limit.Burst {
+		return nil
+	}
+	return &BodyError{Status: http.StatusRequestEntityTooLarge, Reason: fmt.Sprintf("a job of %d requests is over the %s burst of %d, split it into smaller jobs", total, ratelimit.ClassBatchItem, limit.Burst)}
+}
+
+// GetRepricingJob is a GET method returning the status and progress counts of a job
+func (rpc *RPC) GetRepricingJob(w http.ResponseWriter, r *http.Request) {
+	job, err := rpc.Jobs.Status(jobOwner(r), chi.URLParam(r, "id"))
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, job)
+}
+
+// GetRepricingJobResults is a GET method downloading the results of a job written so far
+// as newline delimited GeneratePricingResponse objects, in the order of the submitted requests
+func (rpc *RPC) GetRepricingJobResults(w http.ResponseWriter, r *http.Request) {
+	owner, id := jobOwner(r), chi.URLParam(r, "id")
+	if _, err := rpc.Jobs.Status(owner, id); err != nil {
+		response(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	rpc.Jobs.Results(owner, id, w)
+}
+
+// CancelRepricingJob is a POST method stopping a queued or running job
+func (rpc *RPC) CancelRepricingJob(w http.ResponseWriter, r *http.Request) {
+	job, err := rpc.Jobs.Cancel(jobOwner(r), chi.URLParam(r, "id"))
+	if err != nil {
+		response(w, err)
+		return
+	}
+	response(w, job)
+}
+
+// jobOwner returns the client ID of the authenticated caller, empty when the service runs without authentication
+func jobOwner(r *http.Request) string {
+	if principal := auth.FromContext(r.Context()); principal != nil {
+		return principal.ClientID
+	}
+	return ""
+}
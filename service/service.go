@@ -1,75 +1,298 @@
 package service
 
 import (
+	"context"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 	"time"
-	"log"
-	"os"
-	"os/signal"
-	"context"
 
 	"pricingengine/service/app"
+	"pricingengine/service/auth"
+	"pricingengine/service/certs"
+	"pricingengine/service/customers"
+	"pricingengine/service/jobs"
+	"pricingengine/service/logging"
+	"pricingengine/service/metrics"
+	"pricingengine/service/openapi"
+	"pricingengine/service/promo"
+	"pricingengine/service/ratelimit"
 	"pricingengine/service/rpc"
+	"pricingengine/service/settings"
+	"pricingengine/service/tracing"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 )
 
-// Start begins a chi-Mux'd net/http server on port 3000
+// configRetryInterval is how often the config is loaded again while it never was, /readyz only reads its state
+const configRetryInterval = 5 * time.Second
+
+// Service serves the chi-Mux'd REST endpoints over net/http at the configured listen address
 type Service struct {
 	Server *http.Server
+	App *app.App // application shared with the other transports, a fresh one is used when nil
+	Auth *auth.Authenticator // authenticator shared with the other transports, loaded from the settings when nil
+	Limiter *ratelimit.Limiter // rate limiter shared with the other transports, loaded from the settings when nil
+	TLS *certs.Reloader // certificates shared with the other transports, loaded from the settings when nil and enabled
+	Settings settings.Settings
+	Drains []func(ctx context.Context) // drained along with the server and the repricing jobs, e.g. the gRPC server, under the same deadline
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	jobs *jobs.Manager
 }
 
 // Start method takes care of handling the initial configs and starting the server based on the handler endpoints configured
 // The service typically relies on the go-chi and chi middleware libraries in constructing a rest service
-func (s * Service)Start(port string) {
+// It blocks until the context is done or Stop is called, the in-flight requests, the repricing jobs and the
+// Drains are then drained together for at most the configured ShutdownTimeout
+// returns the error that prevented the server from starting or made it stop unexpectedly
+func (s *Service) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.Settings.ListenAddress)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve method is like Start but serves on an existing listener, which it closes when it returns
+func (s *Service) Serve(ctx context.Context, listener net.Listener) error {
+	if s.App == nil {
+		s.App = &app.App{
+			ConfigPath: s.Settings.ConfigPath(),
+			CacheTTL: int64(s.Settings.CacheTTL.Seconds()),
+			MaxCoverStartAhead: s.Settings.MaxCoverStartAhead.Duration,
+			PromoUsage: &promo.UsageStore{File: s.Settings.PromoUsageFile},
+			Customers: &customers.Store{Dir: s.Settings.CustomersDir},
+		}
+	}
+	if s.Auth == nil && s.Settings.Auth.Enabled() {
+		authenticator, err := auth.New(s.Settings.Auth.Options())
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		s.Auth = authenticator
+	}
+	if s.Limiter == nil && len(s.Settings.RateLimitsFile) > 0 {
+		limiter, err := ratelimit.Load(s.Settings.RateLimitsFile)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		s.Limiter = limiter
+	}
+	if s.TLS == nil && s.Settings.TLS.Enabled() {
+		reloader, err := certs.NewReloader(s.Settings.TLS.Options())
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		s.TLS = reloader
+		go reloader.Watch(ctx, s.Settings.TLS.ReloadInterval.Duration)
+	}
+	if s.Auth != nil && len(s.Settings.AdminToken) > 0 {
+		slog.Warn("The admin token is ignored, the admin endpoints require the config:admin scope as authentication is enabled")
+	}
+	rpc := rpc.RPC{
+		App: s.App,
+		AdminToken: s.Settings.AdminToken,
+		Auth: s.Auth,
+		Limiter: s.Limiter,
+		MaxBodyBytes: int64(s.Settings.MaxBodyBytes),
+	}
+	if s.Settings.Features.Jobs {
+		jobManager := jobs.Manager{
+			App: s.App,
+			Store: &jobs.Store{Dir: s.Settings.JobsDir},
+			Workers: s.Settings.Workers,
+		}
+		if err := jobManager.Start(); err != nil {
+			listener.Close()
+			return err
+		}
+		rpc.Jobs = &jobManager
+		s.jobs = &jobManager
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mutex.Lock()
+	s.cancel = cancel
+	s.mutex.Unlock()
+
+	if status := s.App.ConfigStatus(ctx); !status.Ready {
+		slog.Warn("Config is not loaded yet, /readyz fails until it is", "error", status.LastReloadError)
+		go s.awaitConfig(ctx, configRetryInterval)
+	}
+	return s.ListenAndServe(ctx, listener, NewRouter(&rpc, s.Settings))
+}
+
+// NewRouter method builds the chi router with the middlewares and all the endpoints served by the rpc
+// The optional endpoints and middlewares are only mounted when their feature is enabled in the settings
+func NewRouter(rpc *rpc.RPC, settings settings.Settings) chi.Router {
 	r := chi.NewRouter()
+	spec := openapi.MustLoad()
+
+	r.Use(middleware.RequestID)
+	r.Use(tracing.Middleware)
+	r.Use(logging.Middleware)
+	r.Use(metrics.Middleware)
+	r.Use(rpc.Recoverer)
+	r.Use(rpc.Authenticator)
+	r.Use(rpc.LimitBody)
+	if settings.Features.SchemaValidation {
+		r.Use(spec.Middleware)
+	}
 
-	r.Use(middleware.Logger)
-	r.Use(middleware.Timeout(5 * time.Second))
+	// the probes, metrics and the document are left open for the orchestrator and scrapers
+	r.Get("/openapi.json", spec.Handler)
+	r.Get("/metrics", metrics.Handler().ServeHTTP)
+	r.Get("/healthz", rpc.Healthz)
+	r.Get("/readyz", rpc.Readyz)
+	r.With(rpc.Authorize(auth.ScopeConfigRead), rpc.RateLimit(ratelimit.ClassConfigRead)).Get("/debug/config-status", rpc.ConfigStatus)
 
-	rpc := rpc.RPC{
-		App: &app.App{},
+	// streams and job uploads or downloads are long lived by design, they stop when the client cancels instead
+	if settings.Features.Streaming {
+		r.With(rpc.Authorize(auth.ScopeQuote), rpc.RateLimit(ratelimit.ClassBatchItem)).Post("/generate_pricing/stream", rpc.GeneratePricingStream)
+	}
+	if settings.Features.Jobs {
+		r.Route("/jobs", func(r chi.Router) {
+			r.Use(rpc.Authorize(auth.ScopeQuote), rpc.RateLimit(ratelimit.ClassQuote))
+			r.Post("/", rpc.SubmitRepricingJob)
+			r.Get("/{id}", rpc.GetRepricingJob)
+			r.Get("/{id}/results", rpc.GetRepricingJobResults)
+			r.Post("/{id}/cancel", rpc.CancelRepricingJob)
+		})
 	}
-	if len(port) == 0 {
-		// default port 3000
-		port = "3000"
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(settings.RequestTimeout.Duration))
+
+		r.With(rpc.Authorize(auth.ScopeQuote), rpc.RateLimit(ratelimit.ClassQuote)).Post("/generate_pricing", rpc.GeneratePricing)
+		r.With(rpc.Authorize(auth.ScopeConfigRead), rpc.RateLimit(ratelimit.ClassConfigRead)).Get("/generate_pricing", rpc.GeneratePricingConfig)
+		r.With(rpc.Authorize(auth.ScopeQuote), rpc.RateLimit(ratelimit.ClassQuote)).Post("/promo_codes/{code}/redeem", rpc.RedeemPromoCode)
+		if settings.Features.Admin {
+			r.Route("/admin/config", func(r chi.Router) {
+				// the config:admin scope guards them once authentication is enabled, the AdminToken is only
+				// the fallback of a service running without it
+				if rpc.Auth != nil {
+					r.Use(rpc.Authorize(auth.ScopeConfigAdmin))
+				} else {
+					r.Use(rpc.AdminAuthenticator)
+				}
+				r.Use(rpc.RateLimit(ratelimit.ClassConfigAdmin))
+				r.Get("/versions", rpc.ListConfigVersions)
+				r.Post("/versions", rpc.UploadConfigSet)
+				r.Post("/versions/{version}/activate", rpc.ActivateConfigVersion)
+				r.Put("/factors/{name}", rpc.UploadFactorTable)
+			})
+		}
+		if settings.Features.Customers {
+			r.Route("/customers/{id}", func(r chi.Router) {
+				r.Use(rpc.Authorize(auth.ScopeCustomers), rpc.RateLimit(ratelimit.ClassQuote))
+				r.Get("/", rpc.GetCustomerHistory)
+				r.Post("/policies", rpc.RecordCustomerPolicy)
+				r.Post("/claims", rpc.RecordCustomerClaim)
+			})
+		}
+	})
+	return r
+}
+
+// awaitConfig loads the config every interval until it was loaded once or the context is done,
+// from then on the requests reload it whenever its TTL expires
+func (s *Service) awaitConfig(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if status := s.App.ConfigStatus(ctx); status.Ready {
+			slog.Info("Loaded the config", "version", status.Version)
+			return
+		}
 	}
-	r.Post("/generate_pricing", rpc.GeneratePricing)
-	r.Get("/generate_pricing", rpc.GeneratePricingConfig)
-	s.ListenAndServe(":"+port, r)
 }
 
-// ListenAndServe method takes care of starting the server at the given port
-// The service instance also listend to the SIGNAL that typically interprets the Interrupted
-func (s *Service)ListenAndServe(port string, r http.Handler) {
-		log.Println("Starting Server!")
-		s.Server = &http.Server{Addr: port, Handler: r}
-		if err := s.Server.ListenAndServe(); err != nil {
-				// handle err
+// ListenAndServe method takes care of serving the handler on the listener until the context is done,
+// over TLS when the service has certificates
+// The server then stops accepting connections and waits for the in-flight requests for at most
+// the configured ShutdownTimeout, before closing the connections still open
+// The repricing jobs and the Drains are drained at the same time, even when the server stopped on its own
+func (s *Service) ListenAndServe(ctx context.Context, listener net.Listener, r http.Handler) error {
+	slog.Info("Starting Server", "address", listener.Addr().String())
+	s.Server = &http.Server{Handler: r}
+	served := make(chan error, 1)
+	go func() {
+		if s.TLS != nil {
+			s.Server.TLSConfig = s.TLS.Config()
+			served <- s.Server.ServeTLS(listener, "", "")
+			return
 		}
+		served <- s.Server.Serve(listener)
+	}()
 
-    // Setting up signal capturing
-    stop := make(chan os.Signal, 1)
-    signal.Notify(stop, os.Interrupt)
+	select {
+	case err := <-served:
+		s.drain(func(context.Context) error { return nil })
+		return err
+	case <-ctx.Done():
+	}
+	slog.Info("Received Server Stop, draining", "timeout", s.Settings.ShutdownTimeout.String())
 
-    // Waiting for SIGINT (pkill -2)
-    <-stop
-		log.Println("Received Server Stop!")
-    s.Stop()
-    // Wait for ListenAndServe goroutine to close.
+	return s.drain(func(drain context.Context) error {
+		err := s.Server.Shutdown(drain)
+		if err != nil {
+			slog.Error("Error while stopping Server, closing the remaining connections", "error", err)
+			s.Server.Close()
+		}
+		<-served
+		return err
+	})
 }
 
-// Stop method will check and stop the currently running http server
-func (s * Service)Stop() {
-	log.Println("Stopping Server!")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// drain runs the shutdown of the server concurrently with the ones of the repricing jobs and of the Drains,
+// all of them sharing a single deadline, the ShutdownTimeout from now
+// returns the error of the shutdown of the server
+func (s *Service) drain(shutdown func(ctx context.Context) error) error {
+	drain, cancel := context.WithTimeout(context.Background(), s.Settings.ShutdownTimeout.Duration)
 	defer cancel()
-	if s.Server != nil {
-		log.Println(" Initiating Server Shutdown!")
-		if err := s.Server.Shutdown(ctx); err != nil {
-			// handle err
-			log.Println("Error while stopping Server!", err)
-		}
+	wg := sync.WaitGroup{}
+	drains := s.Drains
+	if s.jobs != nil {
+		drains = append([]func(context.Context){s.shutdownJobs}, drains...)
+	}
+	for _, fn := range drains {
+		wg.Add(1)
+		go func(fn func(context.Context)) {
+			defer wg.Done()
+			fn(drain)
+		}(fn)
+	}
+	err := shutdown(drain)
+	wg.Wait()
+	return err
+}
+
+// shutdownJobs lets the running repricing jobs finish until the context is done
+func (s *Service) shutdownJobs(ctx context.Context) {
+	if err := s.jobs.Shutdown(ctx); err != nil {
+		slog.Warn("Running jobs were checkpointed, they resume on the next start", "error", err)
+	}
+}
+
+// Stop method will trigger the shutdown of the running server, Start returns once it is drained
+func (s *Service) Stop() {
+	slog.Info("Stopping Server")
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel != nil {
+		s.cancel()
 	}
 }
@@ -0,0 +1,443 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"pricingengine/service/auth"
+	"pricingengine/service/certs"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings is the typed runtime configuration of the service
+// The values are resolved with the following precedence, the later winning:
+//  1. the defaults below
+//  2. the optional YAML or JSON settings file (-settings-file or PRICING_ENGINE_SETTINGS_FILE)
+//  3. the PRICING_ENGINE_* environment variables
+//  4. the command line flags
+type Settings struct {
+	ListenAddress      string   `json:"listen_address" yaml:"listen_address"`
+	GRPCListenAddress  string   `json:"grpc_listen_address" yaml:"grpc_listen_address"`
+	ConfigDir          string   `json:"config_dir" yaml:"config_dir"`             // directory of the factor documents
+	JobsDir            string   `json:"jobs_dir" yaml:"jobs_dir"`                 // directory the repricing jobs are kept in
+	PromoUsageFile     string   `json:"promo_usage_file" yaml:"promo_usage_file"` // file the uses of the promo codes are counted in
+	CustomersDir       string   `json:"customers_dir" yaml:"customers_dir"`       // directory the customer histories are kept in
+	CacheTTL           Duration `json:"cache_ttl" yaml:"cache_ttl"`
+	MaxCoverStartAhead Duration `json:"max_cover_start_ahead" yaml:"max_cover_start_ahead"` // how far ahead a cover can start
+	RequestTimeout     Duration `json:"request_timeout" yaml:"request_timeout"`
+	MaxBodyBytes       int      `json:"max_body_bytes" yaml:"max_body_bytes"`     // size the JSON request bodies are capped at
+	ShutdownTimeout    Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"` // time given to in-flight work to drain on shutdown
+	LogLevel           string   `json:"log_level" yaml:"log_level"`
+	Workers            int      `json:"workers" yaml:"workers"` // size of the repricing job worker pool
+	AdminToken         string   `json:"admin_token" yaml:"admin_token"`
+	Features           Features `json:"features" yaml:"features"`
+	Tracing            Tracing  `json:"tracing" yaml:"tracing"`
+	Auth               Auth     `json:"auth" yaml:"auth"`
+	RateLimitsFile     string   `json:"rate_limits_file" yaml:"rate_limits_file"` // JSON per client limits, nothing is limited when empty
+	TLS                TLS      `json:"tls" yaml:"tls"`
+}
+
+// Features toggles the optional parts of the service
+type Features struct {
+	GRPC             bool `json:"grpc" yaml:"grpc"`
+	Admin            bool `json:"admin" yaml:"admin"`
+	Streaming        bool `json:"streaming" yaml:"streaming"`
+	Jobs             bool `json:"jobs" yaml:"jobs"`
+	Customers        bool `json:"customers" yaml:"customers"`
+	SchemaValidation bool `json:"schema_validation" yaml:"schema_validation"`
+}
+
+// Tracing configures the export of the OpenTelemetry spans
+type Tracing struct {
+	Exporter    string  `json:"exporter" yaml:"exporter"` // none, stdout or otlp
+	Endpoint    string  `json:"endpoint" yaml:"endpoint"` // host:port of the OTLP gRPC collector
+	Insecure    bool    `json:"insecure" yaml:"insecure"`
+	SampleRatio float64 `json:"sample_ratio" yaml:"sample_ratio"`
+}
+
+// Auth locates the credentials accepted from the callers, authentication is enabled
+// as soon as one of the API key file, the JWT secret or the JWT public key is set
+type Auth struct {
+	APIKeysFile      string `json:"api_keys_file" yaml:"api_keys_file"`
+	JWTSecretFile    string `json:"jwt_secret_file" yaml:"jwt_secret_file"`         // HS256 shared secret
+	JWTPublicKeyFile string `json:"jwt_public_key_file" yaml:"jwt_public_key_file"` // RS256 PEM public key
+	JWTIssuer        string `json:"jwt_issuer" yaml:"jwt_issuer"`
+	JWTAudience      string `json:"jwt_audience" yaml:"jwt_audience"`
+}
+
+// TLS locates the certificate both services are served with, they are served in plaintext
+// when no certificate is set. The files are reloaded when they change on disk.
+type TLS struct {
+	CertFile       string   `json:"cert_file" yaml:"cert_file"`
+	KeyFile        string   `json:"key_file" yaml:"key_file"`
+	ClientCAFile   string   `json:"client_ca_file" yaml:"client_ca_file"` // CA bundle the client certificates are verified against
+	ClientAuth     string   `json:"client_auth" yaml:"client_auth"`       // none, optional or require
+	ReloadInterval Duration `json:"reload_interval" yaml:"reload_interval"`
+}
+
+// Duration is a time.Duration written as "5s" or "27h46m40s" in the settings file
+type Duration struct {
+	time.Duration
+}
+
+// LogLevels lists the accepted values of LogLevel
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Default method returns the settings used when nothing else is configured
+func Default() Settings {
+	return Settings{
+		ListenAddress:      ":3000",
+		GRPCListenAddress:  ":3001",
+		ConfigDir:          "config",
+		JobsDir:            "jobs",
+		PromoUsageFile:     "promo-usage.json",
+		CustomersDir:       "customers",
+		CacheTTL:           Duration{100000 * time.Second},
+		MaxCoverStartAhead: Duration{30 * 24 * time.Hour},
+		RequestTimeout:     Duration{5 * time.Second},
+		MaxBodyBytes:       1024 * 1024,
+		ShutdownTimeout:    Duration{30 * time.Second},
+		LogLevel:           "info",
+		Workers:            2,
+		Features: Features{
+			GRPC:             true,
+			Admin:            true,
+			Streaming:        true,
+			Jobs:             true,
+			Customers:        true,
+			SchemaValidation: true,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			Endpoint:    "localhost:4317",
+			SampleRatio: 1,
+		},
+		TLS: TLS{
+			ClientAuth:     certs.ClientAuthNone,
+			ReloadInterval: Duration{10 * time.Second},
+		},
+	}
+}
+
+// option binds a single setting to its environment variable and command line flag
+type option struct {
+	name  string // flag name, the environment variable is derived from it
+	usage string
+	set   func(s *Settings, value string) error
+}
+
+var options = []option{
+	{"listen-address", "address the REST service listens on", func(s *Settings, v string) error { s.ListenAddress = v; return nil }},
+	{"grpc-listen-address", "address the gRPC service listens on", func(s *Settings, v string) error { s.GRPCListenAddress = v; return nil }},
+	{"config-dir", "directory of the factor documents", func(s *Settings, v string) error { s.ConfigDir = v; return nil }},
+	{"jobs-dir", "directory the repricing jobs are kept in", func(s *Settings, v string) error { s.JobsDir = v; return nil }},
+	{"customers-dir", "directory the customer histories are kept in", func(s *Settings, v string) error { s.CustomersDir = v; return nil }},
+	{"promo-usage-file", "file the uses of the promo codes are counted in, only counted in memory when empty", func(s *Settings, v string) error { s.PromoUsageFile = v; return nil }},
+	{"cache-ttl", "time the factor documents are cached for", func(s *Settings, v string) error { return setDuration(&s.CacheTTL, v) }},
+	{"max-cover-start-ahead", "how far ahead the cover of a priced request can start", func(s *Settings, v string) error { return setDuration(&s.MaxCoverStartAhead, v) }},
+	{"request-timeout", "timeout of a single request", func(s *Settings, v string) error { return setDuration(&s.RequestTimeout, v) }},
+	{"max-body-bytes", "size the JSON request bodies are capped at", func(s *Settings, v string) error { return setInt(&s.MaxBodyBytes, v) }},
+	{"shutdown-timeout", "time given to in-flight requests and jobs to drain on shutdown", func(s *Settings, v string) error { return setDuration(&s.ShutdownTimeout, v) }},
+	{"log-level", "one of debug, info, warn, error", func(s *Settings, v string) error { s.LogLevel = v; return nil }},
+	{"workers", "size of the repricing job worker pool", func(s *Settings, v string) error { return setInt(&s.Workers, v) }},
+	{"admin-token", "bearer token of the admin endpoints when authentication is disabled, they are disabled when empty", func(s *Settings, v string) error { s.AdminToken = v; return nil }},
+	{"tracing-exporter", "exporter of the trace spans, one of none, stdout, otlp", func(s *Settings, v string) error { s.Tracing.Exporter = v; return nil }},
+	{"tracing-endpoint", "host:port of the OTLP gRPC collector", func(s *Settings, v string) error { s.Tracing.Endpoint = v; return nil }},
+	{"tracing-insecure", "send the spans to the OTLP collector without TLS", func(s *Settings, v string) error { return setBool(&s.Tracing.Insecure, v) }},
+	{"tracing-sample-ratio", "share of the traces sampled, between 0 and 1", func(s *Settings, v string) error { return setFloat(&s.Tracing.SampleRatio, v) }},
+	{"auth-api-keys-file", "JSON file of the accepted API keys and their scopes", func(s *Settings, v string) error { s.Auth.APIKeysFile = v; return nil }},
+	{"auth-jwt-secret-file", "file holding the shared secret of the HS256 JWTs", func(s *Settings, v string) error { s.Auth.JWTSecretFile = v; return nil }},
+	{"auth-jwt-public-key-file", "PEM file of the RSA public key of the RS256 JWTs", func(s *Settings, v string) error { s.Auth.JWTPublicKeyFile = v; return nil }},
+	{"auth-jwt-issuer", "expected iss claim of the JWTs, not checked when empty", func(s *Settings, v string) error { s.Auth.JWTIssuer = v; return nil }},
+	{"auth-jwt-audience", "expected aud claim of the JWTs, not checked when empty", func(s *Settings, v string) error { s.Auth.JWTAudience = v; return nil }},
+	{"rate-limits-file", "JSON file of the per client rate limits, nothing is limited when empty", func(s *Settings, v string) error { s.RateLimitsFile = v; return nil }},
+	{"tls-cert-file", "PEM certificate chain served, the services are served in plaintext when empty", func(s *Settings, v string) error { s.TLS.CertFile = v; return nil }},
+	{"tls-key-file", "PEM private key of the served certificate", func(s *Settings, v string) error { s.TLS.KeyFile = v; return nil }},
+	{"tls-client-ca-file", "PEM bundle of the CAs the client certificates are verified against", func(s *Settings, v string) error { s.TLS.ClientCAFile = v; return nil }},
+	{"tls-client-auth", "client certificate policy, one of none, optional, require", func(s *Settings, v string) error { s.TLS.ClientAuth = v; return nil }},
+	{"tls-reload-interval", "how often the certificate files are checked for changes", func(s *Settings, v string) error { return setDuration(&s.TLS.ReloadInterval, v) }},
+	{"enable-grpc", "serve the gRPC interface", func(s *Settings, v string) error { return setBool(&s.Features.GRPC, v) }},
+	{"enable-admin", "serve the admin endpoints", func(s *Settings, v string) error { return setBool(&s.Features.Admin, v) }},
+	{"enable-streaming", "serve the streaming endpoint", func(s *Settings, v string) error { return setBool(&s.Features.Streaming, v) }},
+	{"enable-jobs", "serve the repricing job endpoints", func(s *Settings, v string) error { return setBool(&s.Features.Jobs, v) }},
+	{"enable-customers", "serve the customer history endpoints", func(s *Settings, v string) error { return setBool(&s.Features.Customers, v) }},
+	{"enable-schema-validation", "validate request bodies against the OpenAPI document", func(s *Settings, v string) error { return setBool(&s.Features.SchemaValidation, v) }},
+}
+
+const settingsFileOption = "settings-file"
+
+// Load method resolves the settings from the defaults, the settings file, the environment
+// and the command line arguments, in that order of precedence, and validates the result
+// args are the command line arguments without the program name, getenv looks up the environment
+func Load(args []string, getenv func(string) string) (*Settings, error) {
+	settings := Default()
+
+	flags := flag.NewFlagSet("pricingengine", flag.ContinueOnError)
+	settingsFile := flags.String(settingsFileOption, getenv(EnvName(settingsFileOption)), "optional YAML or JSON settings file")
+	for _, o := range options {
+		flags.String(o.name, "", o.usage+" (env "+EnvName(o.name)+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if len(*settingsFile) > 0 {
+		if err := settings.readFile(*settingsFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range options {
+		if value := getenv(EnvName(o.name)); len(value) > 0 {
+			if err := o.set(&settings, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", EnvName(o.name), err)
+			}
+		}
+	}
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		for _, o := range options {
+			if o.name == f.Name && err == nil {
+				if setErr := o.set(&settings, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("invalid -%s: %v", o.name, setErr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// EnvName method returns the environment variable of the option, e.g. PRICING_ENGINE_CACHE_TTL
+func EnvName(name string) string {
+	return "PRICING_ENGINE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Validate method checks every setting is usable before the service starts
+// returns an error listing all the invalid settings
+func (s *Settings) Validate() error {
+	problems := []string{}
+	if _, _, err := net.SplitHostPort(s.ListenAddress); err != nil {
+		problems = append(problems, "listen_address: "+err.Error())
+	}
+	if s.Features.GRPC {
+		if _, _, err := net.SplitHostPort(s.GRPCListenAddress); err != nil {
+			problems = append(problems, "grpc_listen_address: "+err.Error())
+		} else if s.GRPCListenAddress == s.ListenAddress {
+			problems = append(problems, "grpc_listen_address: should differ from listen_address")
+		}
+	}
+	if info, err := os.Stat(s.ConfigDir); err != nil || !info.IsDir() {
+		problems = append(problems, "config_dir: "+s.ConfigDir+" is not a directory")
+	}
+	if len(s.JobsDir) == 0 {
+		problems = append(problems, "jobs_dir: cannot be empty")
+	}
+	if len(s.CustomersDir) == 0 {
+		problems = append(problems, "customers_dir: cannot be empty")
+	}
+	if s.CacheTTL.Duration < time.Second {
+		problems = append(problems, "cache_ttl: should be at least 1s")
+	}
+	if s.MaxCoverStartAhead.Duration <= 0 {
+		problems = append(problems, "max_cover_start_ahead: should be positive")
+	}
+	if s.RequestTimeout.Duration <= 0 {
+		problems = append(problems, "request_timeout: should be positive")
+	}
+	if s.MaxBodyBytes < 1024 {
+		problems = append(problems, "max_body_bytes: should be at least 1024")
+	}
+	if s.ShutdownTimeout.Duration <= 0 {
+		problems = append(problems, "shutdown_timeout: should be positive")
+	}
+	validLevel := false
+	for _, level := range LogLevels {
+		validLevel = validLevel || level == s.LogLevel
+	}
+	if !validLevel {
+		problems = append(problems, "log_level: should be one of "+strings.Join(LogLevels, ", "))
+	}
+	switch s.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if _, _, err := net.SplitHostPort(s.Tracing.Endpoint); err != nil {
+			problems = append(problems, "tracing.endpoint: "+err.Error())
+		}
+	default:
+		problems = append(problems, "tracing.exporter: should be one of none, stdout, otlp")
+	}
+	if s.Tracing.SampleRatio < 0 || s.Tracing.SampleRatio > 1 {
+		problems = append(problems, "tracing.sample_ratio: should be between 0 and 1")
+	}
+	for _, file := range [][2]string{{"auth.api_keys_file", s.Auth.APIKeysFile}, {"auth.jwt_secret_file", s.Auth.JWTSecretFile}, {"auth.jwt_public_key_file", s.Auth.JWTPublicKeyFile}, {"rate_limits_file", s.RateLimitsFile}, {"tls.cert_file", s.TLS.CertFile}, {"tls.key_file", s.TLS.KeyFile}, {"tls.client_ca_file", s.TLS.ClientCAFile}} {
+		if info, err := os.Stat(file[1]); len(file[1]) > 0 && (err != nil || info.IsDir()) {
+			problems = append(problems, file[0]+": "+file[1]+" is not a file")
+		}
+	}
+	problems = append(problems, s.TLS.validate()...)
+	if s.Workers < 1 || s.Workers > 1024 {
+		problems = append(problems, "workers: should be between 1 and 1024")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid settings: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ConfigPath method returns ConfigDir as the absolute directory ConfigFetcher reads the documents from,
+// a relative ConfigDir being taken from the working directory the settings are loaded in
+func (s *Settings) ConfigPath() string {
+	dir, err := filepath.Abs(s.ConfigDir)
+	if err != nil {
+		return s.ConfigDir
+	}
+	return dir
+}
+
+// Enabled method reports whether the callers have to be authenticated
+func (a Auth) Enabled() bool {
+	return len(a.APIKeysFile) > 0 || len(a.JWTSecretFile) > 0 || len(a.JWTPublicKeyFile) > 0
+}
+
+// Options method returns the options the auth.Authenticator is loaded with
+func (a Auth) Options() auth.Options {
+	return auth.Options{
+		APIKeysFile:      a.APIKeysFile,
+		JWTSecretFile:    a.JWTSecretFile,
+		JWTPublicKeyFile: a.JWTPublicKeyFile,
+		JWTIssuer:        a.JWTIssuer,
+		JWTAudience:      a.JWTAudience,
+	}
+}
+
+// Enabled method reports whether the services are served over TLS
+func (t TLS) Enabled() bool {
+	return len(t.CertFile) > 0
+}
+
+// Options method returns the options the certs.Reloader is loaded with
+func (t TLS) Options() certs.Options {
+	return certs.Options{
+		CertFile:     t.CertFile,
+		KeyFile:      t.KeyFile,
+		ClientCAFile: t.ClientCAFile,
+		ClientAuth:   t.ClientAuth,
+	}
+}
+
+func (t TLS) validate() []string {
+	problems := []string{}
+	if len(t.CertFile) > 0 != (len(t.KeyFile) > 0) {
+		problems = append(problems, "tls: cert_file and key_file should be set together")
+	}
+	validMode := false
+	for _, mode := range certs.ClientAuthModes {
+		validMode = validMode || mode == t.ClientAuth
+	}
+	switch {
+	case !validMode:
+		problems = append(problems, "tls.client_auth: should be one of "+strings.Join(certs.ClientAuthModes, ", "))
+	case t.ClientAuth != certs.ClientAuthNone && !t.Enabled():
+		problems = append(problems, "tls.client_auth: needs tls.cert_file to be set")
+	case t.ClientAuth != certs.ClientAuthNone && len(t.ClientCAFile) == 0:
+		problems = append(problems, "tls.client_ca_file: should be set when tls.client_auth is "+t.ClientAuth)
+	}
+	if t.ReloadInterval.Duration < time.Second {
+		problems = append(problems, "tls.reload_interval: should be at least 1s")
+	}
+	return problems
+}
+
+// readFile overlays the values of the YAML or JSON settings file, picked by its extension
+func (s *Settings) readFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+		decoder.KnownFields(true)
+		err = decoder.Decode(s)
+	case ".json":
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(s)
+	default:
+		return errors.New("settings file should be .yaml, .yml or .json: " + filename)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid settings file %s: %v", filename, err)
+	}
+	return nil
+}
+
+// UnmarshalJSON reads a duration written as "5s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return setDuration(d, value)
+}
+
+// MarshalJSON writes the duration as "5s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalYAML reads a duration written as "5s"
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return setDuration(d, node.Value)
+}
+
+func setDuration(d *Duration, value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+func setInt(target *int, value string) error {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*target = number
+	return nil
+}
+
+func setFloat(target *float64, value string) error {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*target = number
+	return nil
+}
+
+func setBool(target *bool, value string) error {
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*target = flag
+	return nil
+}
@@ -1,17 +1,115 @@
 package strategy
 
 import (
-	"log"
+  "context"
+  "log/slog"
   "time"
   "errors"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	"pricingengine"
+	"pricingengine/service/logging"
 	"pricingengine/service/model"
+	"pricingengine/service/tracing"
+	"pricingengine/service/util"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+
+// The request attributes an interaction table can be keyed on
+const (
+  DimensionDriverAge = "driver_age" // years since the date of birth
+  DimensionInsuranceGroup = "insurance_group"
+  DimensionLicenceValidity = "licence_validity" // years since the licence was obtained
+)
+
+// Dimensions lists every request attribute an interaction table can be keyed on
+var Dimensions = []string{DimensionDriverAge, DimensionInsuranceGroup, DimensionLicenceValidity}
+
+// The string request attributes a categorical table can be keyed on
+const (
+  AttributeOccupation = "occupation"
+  AttributeFuelType = "fuel_type"
+  AttributeCoverPurpose = "cover_purpose"
+)
+
+// Attributes lists every request attribute a categorical table can be keyed on
+var Attributes = []string{AttributeOccupation, AttributeFuelType, AttributeCoverPurpose}
+
+// The operations a factor band can apply to the premium, a band without operation multiplies it
+const (
+  OperationMultiply = "multiply"
+  OperationAdd = "add" // adds the value of the band, e.g. a flat loading of 5
+  OperationSubtract = "subtract" // subtracts the value of the band, the premium not going below 0
+  OperationPercentDiscount = "percent-discount" // takes the value of the band off the premium, in percent
+  OperationOverride = "override" // replaces the premium with the value of the band
+)
+
+// Operations lists every operation a factor band can apply, in the order they are applied:
+// the multipliers first, the additive loadings next, then the discounts and the overrides last
+var Operations = []string{OperationMultiply, OperationAdd, OperationSubtract, OperationPercentDiscount, OperationOverride}
+
+// operationOrder ranks the operations, the factors of a same rank are applied in the order they are given
+var operationOrder = map[string]int{"": 0, OperationMultiply: 0, OperationAdd: 1, OperationSubtract: 1, OperationPercentDiscount: 2, OperationOverride: 3}
+
+// The claim-free runs of the returning customers the loyalty factor can be counted in
+const (
+  LoyaltyBasisPolicies = "policies" // policies bound since the last claim
+  LoyaltyBasisDays = "days" // days since the first policy bound after the last claim
+)
+
+// LoyaltyBases lists every basis the loyalty factor can be counted in
+var LoyaltyBases = []string{LoyaltyBasisPolicies, LoyaltyBasisDays}
+
+// The rules combining the factors of the additional drivers of a request with the ones of the main driver
+const (
+  DriverRuleHighestRisk = "highest-risk" // the factors of the driver raising the premium the most
+  DriverRuleWeightedAverage = "weighted-average" // the weighted average of the factors of every driver
+  DriverRuleMainPlusLoading = "main-plus-loading" // the factors of the main driver and a loading per additional driver
 )
 
+// DriverRules lists every rule the factors of the drivers can be combined with
+var DriverRules = []string{DriverRuleHighestRisk, DriverRuleWeightedAverage, DriverRuleMainPlusLoading}
+
+// MainDriver names the main driver of the request in the labels
+const MainDriver = "Main driver"
+
+// DriverBands are the driver age and licence validity bands a driver of the request is rated with
+type DriverBands struct {
+  Driver string // name of the driver, see DriverName
+  Age *models.RangeConfig
+  Licence *models.RangeConfig
+}
+
+type Strategy struct{
+  Context context.Context // request context the logs are correlated with, may be nil
+}
+
+// logger returns the logger of the request the strategy is applied for
+func (s *Strategy) logger() *slog.Logger {
+  return logging.FromContext(s.Context)
+}
+
+// startLookup starts the span of a factor lookup
+func (s *Strategy) startLookup(name string) trace.Span {
+  _, span := tracing.Start(s.Context, name)
+  return span
+}
 
-type Strategy struct{}
+// endLookup ends the span of a factor lookup with the band it matched
+// a band that is not eligible is a decline, not a failure of the lookup
+func endLookup(span trace.Span, band *models.RangeConfig, err error) {
+  if band != nil {
+    span.SetAttributes(attribute.String("pricing.band", band.Label), attribute.Bool("pricing.eligible", band.IsEligible))
+    err = nil
+  }
+  tracing.End(span, err)
+}
 
 // Chained functional response that keeps the ball rolling with the
 type StrartegyChain func(*pricingengine.PricingItem) (*pricingengine.PricingItem, error)
@@ -26,8 +124,9 @@ func (s *Strategy) ApplyBasePricing(input *pricingengine.GeneratePricingRequest,
   result.Premium = config.Value
   result.Currency = "£"
   result.FareGroup = config.Label
+  result.Breakdown = []pricingengine.PricingStep{{Label: config.Label, Premium: result.Premium}}
   if fn != nil {
-    log.Println("Found a chain function, Passing on the result for further computation")
+    s.logger().Debug("Applied base pricing, passing on to the next factor", "fare_group", result.FareGroup, "premium", result.Premium)
     return fn(&result)
   }
   return &result, nil // return nil, errors.New("not implemented")
@@ -38,21 +137,41 @@ func (s *Strategy) ApplyBasePricing(input *pricingengine.GeneratePricingRequest,
 // returns the computed PricingItem or error if any happened during the computation
 func (s *Strategy) ApplySubsecuentFactorsToPricing(input *pricingengine.GeneratePricingRequest, previousPricingItem *pricingengine.PricingItem, config *models.RangeConfig, fn StrartegyChain) (*pricingengine.PricingItem, error) {
   var result pricingengine.PricingItem =  pricingengine.PricingItem{}
-  result.Premium = math.Floor(previousPricingItem.Premium * config.Value * 1000)/1000
+  result.Premium = applyOperation(previousPricingItem.Premium, config)
   result.Currency = previousPricingItem.Currency
   result.FareGroup = previousPricingItem.FareGroup + ", " + config.Label
+  step := pricingengine.PricingStep{Label: config.Label, Factor: config.Value, Operation: config.Operation, Premium: result.Premium}
+  result.Breakdown = append(append([]pricingengine.PricingStep{}, previousPricingItem.Breakdown...), step)
   if fn != nil {
-    log.Println("Found a chain function, Passing on the result for further computation")
+    s.logger().Debug("Applied factor, passing on to the next factor", "fare_group", result.FareGroup, "premium", result.Premium)
     return fn(&result)
   }
   return &result, nil
 }
 
+// applyOperation applies the operation of the factor band to the premium, kept to 3 decimals
+// the multipliers and discounts round the premium down, the additive loadings to the nearest
+func applyOperation(premium float64, config *models.RangeConfig) float64 {
+  switch config.Operation {
+  case OperationAdd:
+    return math.Round((premium + config.Value) * 1000)/1000
+  case OperationSubtract:
+    return math.Max(0, math.Round((premium - config.Value) * 1000)/1000)
+  case OperationPercentDiscount:
+    return math.Floor(premium * (100 - config.Value) / 100 * 1000)/1000
+  case OperationOverride:
+    return math.Floor(config.Value * 1000)/1000
+  }
+  return math.Floor(premium * config.Value * 1000)/1000
+}
+
 // FindMatchingDriverAgeFactor method will find the appropriate DriverAgeFactor RangeConfig
 // based on the DateOfBirth data passed in the input GeneratePricingRequest
 // returns the found DriverAgeFactor
 //  error will be thrown if the field level validation fails or a matching config is not found
-func (s *Strategy) FindMatchingDriverAgeFactor(input *pricingengine.GeneratePricingRequest, allDriverAgeFactors []models.RangeConfig) (*models.RangeConfig, error) {
+func (s *Strategy) FindMatchingDriverAgeFactor(input *pricingengine.GeneratePricingRequest, allDriverAgeFactors []models.RangeConfig) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingDriverAgeFactor")
+  defer func() { endLookup(span, band, err) }()
   date_of_birth := input.DateOfBirth
   parse_dob_t, err := time.Parse("2006-01-02", date_of_birth)
 	if err != nil {
@@ -60,7 +179,7 @@ func (s *Strategy) FindMatchingDriverAgeFactor(input *pricingengine.GeneratePric
 	}
   now := time.Now()
   age := int(now.Sub(parse_dob_t).Hours()/(24*30*12))
-	log.Println("Checking the driver factor for date_of_birth=", date_of_birth, " parse_dob_t=", parse_dob_t, " age=", age)
+	s.logger().Debug("Checking the driver factor", "date_of_birth", date_of_birth, "age", age)
   for i:= 0; i < len(allDriverAgeFactors); i++ {
 		current := allDriverAgeFactors[i]
     if (current.Start < age && current.End >= age) {
@@ -78,7 +197,9 @@ func (s *Strategy) FindMatchingDriverAgeFactor(input *pricingengine.GeneratePric
 // based on the InsuranceGroup data passed in the input GeneratePricingRequest
 // returns the found InsuranceGroupFactor
 //  error will be thrown if the field level validation fails or a matching config is not found
-func (s *Strategy) FindMatchingInsuranceGroupFactor(input *pricingengine.GeneratePricingRequest, allInsuranceGroupFactors []models.RangeConfig) (*models.RangeConfig, error) {
+func (s *Strategy) FindMatchingInsuranceGroupFactor(input *pricingengine.GeneratePricingRequest, allInsuranceGroupFactors []models.RangeConfig) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingInsuranceGroupFactor")
+  defer func() { endLookup(span, band, err) }()
   insurance_group := input.InsuranceGroup
   for i:= 0; i < len(allInsuranceGroupFactors); i++ {
     current := allInsuranceGroupFactors[i]
@@ -97,7 +218,9 @@ func (s *Strategy) FindMatchingInsuranceGroupFactor(input *pricingengine.Generat
 // based on the LicenseHeldSince data passed in the input GeneratePricingRequest
 // returns the found LicenceValidityFactor
 //  error will be thrown if the field level validation fails or a matching config is not found
-func (s *Strategy) FindMatchingLicenceValidityFactor(input *pricingengine.GeneratePricingRequest, allLicenceValidtyFactors []models.RangeConfig) (*models.RangeConfig, error) {
+func (s *Strategy) FindMatchingLicenceValidityFactor(input *pricingengine.GeneratePricingRequest, allLicenceValidtyFactors []models.RangeConfig) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingLicenceValidityFactor")
+  defer func() { endLookup(span, band, err) }()
 	licence_date := input.LicenseHeldSince
   parse_date_t, err := time.Parse("2006-01-02", licence_date)
 	if err != nil {
@@ -117,3 +240,708 @@ func (s *Strategy) FindMatchingLicenceValidityFactor(input *pricingengine.Genera
   }
   return nil, errors.New("MatchingLicenceValidityFactor not found!")
 }
+
+// FindMatchingInteractionFactor method will find the first cell of the interaction table whose
+// ranges hold the value of every dimension resolved from the input GeneratePricingRequest
+// returns the found cell as a RangeConfig, or nil when no cell matches and the table does not apply
+//  error will be thrown if a dimension can not be resolved or the matching cell is not eligible
+func (s *Strategy) FindMatchingInteractionFactor(input *pricingengine.GeneratePricingRequest, table models.InteractionTable) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingInteractionFactor")
+  span.SetAttributes(attribute.String("pricing.interaction", table.Name))
+  defer func() { endLookup(span, band, err) }()
+  values := make([]int, len(table.Dimensions))
+  for i, dimension := range table.Dimensions {
+    if values[i], err = s.DimensionValue(input, dimension); err != nil {
+      return nil, err
+    }
+  }
+  s.logger().Debug("Checking the interaction factor", "interaction", table.Name, "values", values)
+  for _, cell := range table.Cells {
+    matches := len(cell.Start) == len(values)
+    for i := 0; matches && i < len(values); i++ {
+      matches = cell.Start[i] < values[i] && cell.End[i] >= values[i]
+    }
+    if !matches {
+      continue
+    }
+    current := models.RangeConfig{IsEligible: cell.IsEligible, Value: cell.Value, Label: cell.Label, Operation: cell.Operation}
+    if (current.IsEligible) {
+      return &current, nil
+    }
+    return &current, errors.New("Declined due to :"+current.Label)
+  }
+  return nil, nil
+}
+
+// DimensionValue method will resolve the value of one of the Dimensions from the input GeneratePricingRequest
+// returns the value or error if the field it is based on can not be parsed
+func (s *Strategy) DimensionValue(input *pricingengine.GeneratePricingRequest, dimension string) (int, error) {
+  switch dimension {
+  case DimensionDriverAge:
+    return yearsSince(input.DateOfBirth, "DateOfBirth")
+  case DimensionInsuranceGroup:
+    return input.InsuranceGroup, nil
+  case DimensionLicenceValidity:
+    return yearsSince(input.LicenseHeldSince, "LicenseHeldSince")
+  }
+  return 0, errors.New("Unknown dimension "+dimension)
+}
+
+// ChainFactors method will chain ApplySubsecuentFactorsToPricing over the factors, in the order of their
+// Operations and then in the given order
+// returns the StrartegyChain to pass on to ApplyBasePricing, nil when there is no factor
+func (s *Strategy) ChainFactors(input *pricingengine.GeneratePricingRequest, factors []*models.RangeConfig) StrartegyChain {
+  factors = append([]*models.RangeConfig{}, factors...)
+  sort.SliceStable(factors, func(i, j int) bool {
+    return operationOrder[factors[i].Operation] < operationOrder[factors[j].Operation]
+  })
+  var next StrartegyChain
+  for i := len(factors)-1; i >= 0; i-- {
+    factor, fn := factors[i], next
+    next = func(resp *pricingengine.PricingItem) (*pricingengine.PricingItem, error) {
+      return s.ApplySubsecuentFactorsToPricing(input, resp, factor, fn)
+    }
+  }
+  return next
+}
+
+// DriverName returns the name of the additional driver at the index, "Driver <n>" when it has none,
+// the main driver being driver 1
+func DriverName(index int, driver pricingengine.Driver) string {
+  if name := strings.TrimSpace(driver.Name); len(name) > 0 {
+    return name
+  }
+  return "Driver "+strconv.Itoa(index+2)
+}
+
+// CombineDrivers method will combine the bands of the drivers, the main driver first, with the rule of the combination
+// highest-risk keeps the bands of the driver whose bands raise the premium the most, compared at the given premium,
+// weighted-average averages the factors of the bands of every driver, the multiplying and the flat parts apart when
+// their operations differ, and main-plus-loading keeps the bands of the main driver along with a loading per
+// additional driver
+// returns the driver age and licence validity bands to apply, labelled with the driver or the average, and the
+// loadings of the additional drivers or flat parts of the averages, the bands of the main driver alone when there
+// is no additional driver
+func (s *Strategy) CombineDrivers(drivers []DriverBands, combination models.DriverCombination, premium float64) (age *models.RangeConfig, licence *models.RangeConfig, loadings []*models.RangeConfig) {
+  main := drivers[0]
+  if len(drivers) == 1 {
+    return main.Age, main.Licence, nil
+  }
+  s.logger().Debug("Combining the drivers", "drivers", len(drivers), "rule", combination.Rule)
+  switch combination.Rule {
+  case DriverRuleWeightedAverage:
+    weights := driverWeights(len(drivers), combination.MainWeight)
+    ages, licences := []*models.RangeConfig{}, []*models.RangeConfig{}
+    for _, driver := range drivers {
+      ages, licences = append(ages, driver.Age), append(licences, driver.Licence)
+    }
+    age, ageFlat := weightedBand(ages, weights)
+    licence, licenceFlat := weightedBand(licences, weights)
+    for _, flat := range []*models.RangeConfig{ageFlat, licenceFlat} {
+      if flat != nil {
+        loadings = append(loadings, flat)
+      }
+    }
+    return age, licence, loadings
+  case DriverRuleMainPlusLoading:
+    if combination.Loading != nil {
+      for _, driver := range drivers[1:] {
+        loadings = append(loadings, &models.RangeConfig{IsEligible: true, Value: combination.Loading.Factor, Label: combination.Loading.Label+" ("+driver.Driver+")", Operation: combination.Loading.Operation})
+      }
+    }
+    return main.Age, main.Licence, loadings
+  }
+  riskiest := main
+  for _, driver := range drivers[1:] {
+    if DriverRisk(driver, premium) > DriverRisk(riskiest, premium) {
+      riskiest = driver
+    }
+  }
+  return namedBand(riskiest.Age, riskiest.Driver), namedBand(riskiest.Licence, riskiest.Driver), nil
+}
+
+// DriverRisk returns the premium once the bands of the driver are applied to it, in the order of their Operations
+func DriverRisk(driver DriverBands, premium float64) float64 {
+  bands := []*models.RangeConfig{driver.Age, driver.Licence}
+  sort.SliceStable(bands, func(i, j int) bool {
+    return operationOrder[bands[i].Operation] < operationOrder[bands[j].Operation]
+  })
+  for _, band := range bands {
+    premium = applyOperation(premium, band)
+  }
+  return premium
+}
+
+// driverWeights returns the weights of the drivers in the weighted-average, the main driver first
+func driverWeights(drivers int, mainWeight float64) []float64 {
+  weights := []float64{}
+  for i := 0; i < drivers; i++ {
+    switch {
+    case mainWeight <= 0:
+      weights = append(weights, 1/float64(drivers))
+    case i == 0:
+      weights = append(weights, mainWeight)
+    default:
+      weights = append(weights, (1-mainWeight)/float64(drivers-1))
+    }
+  }
+  return weights
+}
+
+// weightedBand returns the band of the main driver, first, with the weighted average of the factors of the bands
+// kept to 4 decimals when they all have the same Operation
+// Bands of different Operations are split into the factor they multiply the premium by and the amount they add
+// to it, e.g. 1 and 10 for an add band of 10, and the two parts are averaged apart: the multiplying part is
+// returned as a multiply band and the flat part, when there is one, as an add or subtract band of its own
+func weightedBand(bands []*models.RangeConfig, weights []float64) (*models.RangeConfig, *models.RangeConfig) {
+  result := *bands[0]
+  result.Label = bands[0].Label+" (weighted average of "+strconv.Itoa(len(bands))+" drivers)"
+  value, mixed := 0.0, false
+  for i, band := range bands {
+    value += band.Value * weights[i]
+    mixed = mixed || bandOperation(band) != bandOperation(bands[0])
+  }
+  if !mixed {
+    result.Value = math.Round(value * 10000)/10000
+    return &result, nil
+  }
+  factor, amount := 0.0, 0.0
+  for i, band := range bands {
+    bandFactor, bandAmount := affineParts(band)
+    factor += bandFactor * weights[i]
+    amount += bandAmount * weights[i]
+  }
+  result.Value = math.Round(factor * 10000)/10000
+  result.Operation = OperationMultiply
+  amount = math.Round(amount * 1000)/1000
+  if amount == 0 {
+    return &result, nil
+  }
+  flat := models.RangeConfig{IsEligible: true, Value: amount, Label: result.Label+" flat", Operation: OperationAdd}
+  if amount < 0 {
+    flat.Value, flat.Operation = -amount, OperationSubtract
+  }
+  return &result, &flat
+}
+
+// affineParts returns the factor the band multiplies the premium by and the amount it adds to it
+func affineParts(band *models.RangeConfig) (float64, float64) {
+  switch band.Operation {
+  case OperationAdd:
+    return 1, band.Value
+  case OperationSubtract:
+    return 1, -band.Value
+  case OperationPercentDiscount:
+    return (100 - band.Value) / 100, 0
+  case OperationOverride:
+    return 0, band.Value
+  }
+  return band.Value, 0
+}
+
+// bandOperation returns the Operation of the band, multiply when it has none
+func bandOperation(band *models.RangeConfig) string {
+  if len(band.Operation) == 0 {
+    return OperationMultiply
+  }
+  return band.Operation
+}
+
+// namedBand returns a copy of the band labelled with the driver it was found for
+func namedBand(band *models.RangeConfig, driver string) *models.RangeConfig {
+  result := *band
+  result.Label = band.Label+" ("+driver+")"
+  return &result
+}
+
+// FindMatchingTemporalFactors method will find the hour band, the day band and the holiday factor matching the
+// CoverStart passed in the input GeneratePricingRequest, once resolved in the timezone of the TemporalFactors
+// A cover start without offset is taken as a local time of that timezone
+// returns the matching bands in that order, none when the cover start is not set
+//  error will be thrown if the cover start or the timezone can not be parsed or a matching band is not eligible,
+//  the declining band is then returned alone
+func (s *Strategy) FindMatchingTemporalFactors(input *pricingengine.GeneratePricingRequest, factors models.TemporalFactors, holidays []models.Holiday) (bands []*models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingTemporalFactors")
+  defer func() { endLoadings(span, bands, err) }()
+  if len(input.CoverStart) == 0 {
+    return nil, nil
+  }
+  location, err := time.LoadLocation(factors.Timezone)
+  if err != nil {
+    return nil, err
+  }
+  start, err := ParseCoverStart(input.CoverStart, location)
+  if err != nil {
+    return nil, err
+  }
+  start = start.In(location)
+  s.logger().Debug("Checking the temporal factors", "cover_start", start.Format(time.RFC3339))
+  matching := []*models.RangeConfig{}
+  for _, hour := range factors.Hours {
+    if hourMatches(hour, start.Hour()) {
+      matching = append(matching, &models.RangeConfig{Start: hour.From, End: hour.To, IsEligible: hour.IsEligible, Value: hour.Factor, Label: hour.Label, Operation: hour.Operation})
+      break
+    }
+  }
+  weekday := strings.ToLower(start.Weekday().String())
+  for _, day := range factors.Days {
+    if categoryMatches(models.CategoricalRange{Values: day.Days}, weekday) {
+      matching = append(matching, &models.RangeConfig{IsEligible: day.IsEligible, Value: day.Factor, Label: day.Label, Operation: day.Operation})
+      break
+    }
+  }
+  if factors.Holiday != nil {
+    date := start.Format("2006-01-02")
+    for _, holiday := range holidays {
+      if holiday.Date == date {
+        label := factors.Holiday.Label
+        if len(holiday.Name) > 0 {
+          label += " ("+holiday.Name+")"
+        }
+        matching = append(matching, &models.RangeConfig{IsEligible: factors.Holiday.IsEligible, Value: factors.Holiday.Factor, Label: label, Operation: factors.Holiday.Operation})
+        break
+      }
+    }
+  }
+  for _, band := range matching {
+    if !band.IsEligible {
+      return []*models.RangeConfig{band}, errors.New("Declined due to :"+band.Label)
+    }
+  }
+  return matching, nil
+}
+
+// ParseCoverStart parses the cover start as an RFC 3339 timestamp, or as a local time of the location when it has no offset
+func ParseCoverStart(value string, location *time.Location) (time.Time, error) {
+  start, err := time.Parse(time.RFC3339, value)
+  if err == nil {
+    return start, nil
+  }
+  start, local := time.ParseInLocation("2006-01-02T15:04:05", value, location)
+  if local != nil {
+    return start, errors.New("Error wile Parsing CoverStart. Error: neither an RFC 3339 timestamp nor a local time: "+ local.Error())
+  }
+  return start, nil
+}
+
+// hourMatches tells whether the hour is in the band, a band with To lower than From wrapping around midnight
+func hourMatches(band models.HourFactor, hour int) bool {
+  if band.From <= band.To {
+    return band.From <= hour && hour < band.To
+  }
+  return hour >= band.From || hour < band.To
+}
+
+// FindMatchingPromoCode method will find the PromoCode matching the normalised PromoCode passed in the input
+// GeneratePricingRequest and check it is valid today
+// returns the found PromoCode
+//  error will be thrown with the reason the code is rejected if it is not found or not valid today
+func (s *Strategy) FindMatchingPromoCode(input *pricingengine.GeneratePricingRequest, promos []models.PromoCode) (promo *models.PromoCode, err error) {
+  span := s.startLookup("strategy.FindMatchingPromoCode")
+  defer func() { tracing.End(span, err) }()
+  code := util.NormalisePromoCode(input.PromoCode)
+  s.logger().Debug("Checking the promo code", "promo_code", code)
+  today := time.Now().Format("2006-01-02")
+  for i := range promos {
+    current := &promos[i]
+    if current.Code != code {
+      continue
+    }
+    if len(current.ValidFrom) > 0 && today < current.ValidFrom {
+      return nil, errors.New("Promo code "+code+" is not valid before "+current.ValidFrom)
+    }
+    if len(current.ValidTo) > 0 && today > current.ValidTo {
+      return nil, errors.New("Promo code "+code+" expired on "+current.ValidTo)
+    }
+    return current, nil
+  }
+  return nil, errors.New("Promo code "+code+" is not known")
+}
+
+// FindMatchingPromoDiscount method will check the promo code applies to the base rate and the premium
+// computed for it, and convert it to the RangeConfig band applying its discount
+// returns the discount band
+//  error will be thrown with the reason the code does not apply if the base rate is not one of its Durations
+//  or the premium is lower than its MinPremium
+func (s *Strategy) FindMatchingPromoDiscount(promo models.PromoCode, baseRate *models.RangeConfig, premium float64) (*models.RangeConfig, error) {
+  if len(promo.Durations) > 0 && !categoryMatches(models.CategoricalRange{Values: promo.Durations}, util.NormaliseCategory(baseRate.Label)) {
+    return nil, errors.New("Promo code "+promo.Code+" does not apply to "+baseRate.Label)
+  }
+  if premium < promo.MinPremium {
+    return nil, errors.New("Promo code "+promo.Code+" needs a premium of at least "+strconv.FormatFloat(promo.MinPremium, 'f', -1, 64))
+  }
+  return &models.RangeConfig{IsEligible: true, Value: promo.Value, Label: promo.Label, Operation: promo.Type}, nil
+}
+
+// FindMatchingLoyaltyFactor method will find the LoyaltyBand of the claim-free run of the customer, counted from
+// their history along with the claims passed in the input GeneratePricingRequest
+// returns the band with the highest From the run reaches, its label telling the run, none when the request
+// has no CustomerID or the run is below every band
+//  error will be thrown if a date of the history can not be parsed
+func (s *Strategy) FindMatchingLoyaltyFactor(input *pricingengine.GeneratePricingRequest, history *pricingengine.CustomerHistory, loyalty models.LoyaltyFactor) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingLoyaltyFactor")
+  defer func() { endLookup(span, band, err) }()
+  if len(input.CustomerID) == 0 || history == nil {
+    return nil, nil
+  }
+  run, err := ClaimFreeRun(history, input.Claims, loyalty.Basis, time.Now())
+  if err != nil {
+    return nil, err
+  }
+  s.logger().Debug("Checking the loyalty factor", "customer_id", input.CustomerID, "claim_free", run, "basis", loyalty.Basis)
+  var matching *models.LoyaltyBand
+  for i := range loyalty.Bands {
+    if loyalty.Bands[i].From <= run && (matching == nil || loyalty.Bands[i].From >= matching.From) {
+      matching = &loyalty.Bands[i]
+    }
+  }
+  if matching == nil {
+    return nil, nil
+  }
+  label := matching.Label+" ("+strconv.Itoa(run)+" claim-free "+loyalty.Basis+")"
+  return &models.RangeConfig{Start: matching.From, IsEligible: true, Value: matching.Factor, Label: label, Operation: matching.Operation}, nil
+}
+
+// ClaimFreeRun counts the policies of the history bound after the last claim, of the history or the extra claims,
+// or the days from the first of them to now when the basis is LoyaltyBasisDays
+// returns 0 when no policy was bound since the last claim
+//  error will be thrown if a policy or claim date can not be parsed
+func ClaimFreeRun(history *pricingengine.CustomerHistory, claims []pricingengine.Claim, basis string, now time.Time) (int, error) {
+  var lastClaim time.Time
+  for _, claim := range append(append([]pricingengine.Claim{}, history.Claims...), claims...) {
+    date, err := time.Parse("2006-01-02", claim.Date)
+    if err != nil {
+      return 0, errors.New("Error wile Parsing Claim date. Error: "+ err.Error())
+    }
+    if date.After(lastClaim) {
+      lastClaim = date
+    }
+  }
+  policies := 0
+  var firstPolicy time.Time
+  for _, policy := range history.Policies {
+    date, err := time.Parse("2006-01-02", policy.BoundOn)
+    if err != nil {
+      return 0, errors.New("Error wile Parsing Policy date. Error: "+ err.Error())
+    }
+    if !date.After(lastClaim) {
+      continue
+    }
+    policies++
+    if firstPolicy.IsZero() || date.Before(firstPolicy) {
+      firstPolicy = date
+    }
+  }
+  if basis != LoyaltyBasisDays || policies == 0 {
+    return policies, nil
+  }
+  days := int(now.Sub(firstPolicy).Hours() / 24)
+  if days < 0 {
+    return 0, nil
+  }
+  return days, nil
+}
+
+// FindMatchingClaimLoadings method will find the loading of every claim passed in the input GeneratePricingRequest
+// that happened within the lookback window of its type, capping the loadings multiplied together at the MaxLoading
+// returns the loadings to apply in the order of the claims, a capped loading is labelled as such
+//  error will be thrown if a claim date can not be parsed or a claim type declines, the declining loading is then returned alone
+func (s *Strategy) FindMatchingClaimLoadings(input *pricingengine.GeneratePricingRequest, loadings models.ClaimLoadings) (bands []*models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingClaimLoadings")
+  defer func() { endLoadings(span, bands, err) }()
+  now := time.Now()
+  total := 1.0
+  for _, claim := range input.Claims {
+    claimType := util.NormaliseCategory(claim.Type)
+    var matching *models.ClaimLoading
+    for i := 0; i < len(loadings.Types) && matching == nil; i++ {
+      if loadings.Types[i].Type == claimType || loadings.Types[i].Type == "*" {
+        matching = &loadings.Types[i]
+      }
+    }
+    if matching == nil {
+      continue
+    }
+    date, err := time.Parse("2006-01-02", claim.Date)
+    if err != nil {
+      return nil, errors.New("Error wile Parsing Claim date. Error: "+ err.Error())
+    }
+    if !withinLookback(date, now, matching.LookbackYears, loadings.LookbackYears) {
+      continue
+    }
+    fault := "non-fault"
+    factor := matching.NonFaultFactor
+    if claim.Fault {
+      fault, factor = "fault", matching.FaultFactor
+    }
+    label := matching.Label
+    if len(label) == 0 {
+      label = "Claim:"+claimType
+    }
+    dated := label+" ("+fault+" "+claim.Date+")"
+    if matching.Decline {
+      // the declining band keeps the label of the claim type alone, it names the decline in the metrics
+      declined := models.RangeConfig{IsEligible: false, Label: label}
+      return []*models.RangeConfig{&declined}, errors.New("Declined due to :"+dated)
+    }
+    if band := capLoading(&total, factor, loadings.MaxLoading, dated); band != nil {
+      bands = append(bands, band)
+    }
+  }
+  return bands, nil
+}
+
+// FindMatchingConvictionLoadings method will find the loading of every conviction passed in the input GeneratePricingRequest
+// that happened within the lookback window of its code, capping the loadings multiplied together at the MaxLoading
+// returns the loadings to apply in the order of the convictions, a capped loading is labelled as such
+//  error will be thrown if a conviction date can not be parsed, a conviction code declines or the penalty points
+//  reach the MaxPoints, the declining loading is then returned alone
+func (s *Strategy) FindMatchingConvictionLoadings(input *pricingengine.GeneratePricingRequest, loadings models.ConvictionLoadings) (bands []*models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingConvictionLoadings")
+  defer func() { endLoadings(span, bands, err) }()
+  now := time.Now()
+  total, points := 1.0, 0
+  for _, conviction := range input.Convictions {
+    code := util.NormaliseConvictionCode(conviction.Code)
+    date, err := time.Parse("2006-01-02", conviction.Date)
+    if err != nil {
+      return nil, errors.New("Error wile Parsing Conviction date. Error: "+ err.Error())
+    }
+    var matching *models.ConvictionLoading
+    for i := 0; i < len(loadings.Codes) && matching == nil; i++ {
+      if wildcardMatch(loadings.Codes[i].Code, code) {
+        matching = &loadings.Codes[i]
+      }
+    }
+    lookback := loadings.LookbackYears
+    if matching != nil && matching.LookbackYears > 0 {
+      lookback = matching.LookbackYears
+    }
+    if !withinLookback(date, now, lookback, loadings.LookbackYears) {
+      continue
+    }
+    points += conviction.Points
+    if matching == nil {
+      continue
+    }
+    label := matching.Label
+    if len(label) == 0 {
+      label = "Conviction:"+code
+    }
+    dated := label+" ("+conviction.Date+")"
+    if matching.Decline {
+      // the declining band keeps the label of the conviction code alone, it names the decline in the metrics
+      declined := models.RangeConfig{IsEligible: false, Label: label}
+      return []*models.RangeConfig{&declined}, errors.New("Declined due to :"+dated)
+    }
+    if band := capLoading(&total, matching.Factor, loadings.MaxLoading, dated); band != nil {
+      bands = append(bands, band)
+    }
+  }
+  if loadings.MaxPoints > 0 && points >= loadings.MaxPoints {
+    declined := models.RangeConfig{IsEligible: false, Label: "Conviction points:"+strconv.Itoa(points)}
+    return []*models.RangeConfig{&declined}, errors.New("Declined due to :"+declined.Label)
+  }
+  return bands, nil
+}
+
+// withinLookback tells whether the date is within the lookback years before now, the lookback of the
+// entry when it is set or else the default one
+func withinLookback(date time.Time, now time.Time, lookback int, fallback int) bool {
+  if lookback <= 0 {
+    lookback = fallback
+  }
+  return !date.Before(now.AddDate(-lookback, 0, 0))
+}
+
+// capLoading returns the loading as a band, reduced so that the total loading does not exceed the max loading
+// returns nil when the total loading already reached it, the total is updated with the applied loading
+func capLoading(total *float64, factor float64, max float64, label string) *models.RangeConfig {
+  if max > 0 && *total*factor > max {
+    factor = max / *total
+    label += " (capped)"
+    if factor <= 1 {
+      return nil
+    }
+  }
+  *total *= factor
+  return &models.RangeConfig{IsEligible: true, Value: math.Floor(factor*10000)/10000, Label: label}
+}
+
+// endLoadings ends the span of a loadings lookup with the number of loadings it found
+// a declining loading is a decline, not a failure of the lookup
+func endLoadings(span trace.Span, bands []*models.RangeConfig, err error) {
+  span.SetAttributes(attribute.Int("pricing.loadings", len(bands)))
+  if len(bands) == 1 && !bands[0].IsEligible {
+    span.SetAttributes(attribute.String("pricing.band", bands[0].Label), attribute.Bool("pricing.eligible", false))
+    err = nil
+  }
+  tracing.End(span, err)
+}
+
+// FindMatchingVehicle method will find the Vehicle of the VehicleRegistration passed in the input GeneratePricingRequest,
+// or of its VehicleCode when there is no registration or it is not in the table, and resolve its insurance group,
+// value and age
+// returns the resolved Vehicle, or nil when the request identifies no vehicle or there is no vehicle table to look it up
+//  error will be thrown if neither the registration nor the code is in the table or its registration date can not be parsed
+func (s *Strategy) FindMatchingVehicle(input *pricingengine.GeneratePricingRequest, allVehicles []models.Vehicle) (vehicle *pricingengine.Vehicle, err error) {
+  span := s.startLookup("strategy.FindMatchingVehicle")
+  defer func() { tracing.End(span, err) }()
+  registration, code := util.NormaliseVehicleID(input.VehicleRegistration), util.NormaliseVehicleID(input.VehicleCode)
+  if (len(registration) == 0 && len(code) == 0) || len(allVehicles) == 0 {
+    return nil, nil
+  }
+  current := findVehicle(allVehicles, func(vehicle *models.Vehicle) bool { return len(registration) > 0 && vehicle.Registration == registration })
+  if current == nil {
+    current = findVehicle(allVehicles, func(vehicle *models.Vehicle) bool { return len(code) > 0 && vehicle.Code == code })
+  }
+  switch {
+  case current == nil && len(registration) > 0 && len(code) > 0:
+    return nil, errors.New("Vehicle registration "+registration+" and code "+code+" were not found")
+  case current == nil && len(registration) > 0:
+    return nil, errors.New("Vehicle registration "+registration+" was not found")
+  case current == nil:
+    return nil, errors.New("Vehicle code "+code+" was not found")
+  }
+  age, err := yearsSince(current.Registered, "Registered")
+  if err != nil {
+    return nil, err
+  }
+  s.logger().Debug("Resolved the vehicle", "registration", current.Registration, "code", current.Code, "insurance_group", current.InsuranceGroup)
+  span.SetAttributes(attribute.Int("pricing.vehicle_group", current.InsuranceGroup))
+  return &pricingengine.Vehicle{
+    Registration: current.Registration,
+    Code: current.Code,
+    Make: current.Make,
+    Model: current.Model,
+    InsuranceGroup: current.InsuranceGroup,
+    Value: current.Value,
+    Age: age,
+  }, nil
+}
+
+// findVehicle returns the first vehicle of the table the match holds for, nil when there is none
+func findVehicle(allVehicles []models.Vehicle, match func(*models.Vehicle) bool) *models.Vehicle {
+  for i := range allVehicles {
+    if match(&allVehicles[i]) {
+      return &allVehicles[i]
+    }
+  }
+  return nil
+}
+
+// FindMatchingPostcodeFactor method will find the PostcodeRange with the longest prefix of the Postcode
+// passed in the input GeneratePricingRequest, matching its full postcode, sector, district and area in that order
+// returns the found PostcodeRange as a RangeConfig, or nil when the postcode is not set or no prefix matches
+//  error will be thrown if the postcode is not valid or the matching range is not eligible
+func (s *Strategy) FindMatchingPostcodeFactor(input *pricingengine.GeneratePricingRequest, allPostcodeFactors []models.PostcodeRange) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingPostcodeFactor")
+  defer func() { endLookup(span, band, err) }()
+  if len(input.Postcode) == 0 {
+    return nil, nil
+  }
+  postcode, ok := util.NormalisePostcode(input.Postcode)
+  if !ok {
+    return nil, errors.New("Postcode "+input.Postcode+" is not a valid UK postcode")
+  }
+  s.logger().Debug("Checking the postcode factor", "postcode", postcode)
+  for _, prefix := range util.PostcodePrefixes(postcode) {
+    for _, current := range allPostcodeFactors {
+      if current.Prefix != prefix {
+        continue
+      }
+      result := models.RangeConfig{IsEligible: current.IsEligible, Value: current.Value, Label: current.Label, Operation: current.Operation}
+      if (result.IsEligible) {
+        return &result, nil
+      }
+      return &result, errors.New("Declined due to :"+result.Label)
+    }
+  }
+  return nil, nil
+}
+
+// FindMatchingCategoricalFactor method will find the first entry of the categorical table matching the
+// normalised value of its attribute in the input GeneratePricingRequest, or the default entry when none does
+// returns the found entry as a RangeConfig, or nil when the attribute is not set or nothing matches
+//  error will be thrown if the matching entry is not eligible
+func (s *Strategy) FindMatchingCategoricalFactor(input *pricingengine.GeneratePricingRequest, table models.CategoricalTable) (band *models.RangeConfig, err error) {
+  span := s.startLookup("strategy.FindMatchingCategoricalFactor")
+  span.SetAttributes(attribute.String("pricing.categorical", table.Name))
+  defer func() { endLookup(span, band, err) }()
+  value := util.NormaliseCategory(AttributeValue(input, table.Attribute))
+  if len(value) == 0 {
+    return nil, nil
+  }
+  s.logger().Debug("Checking the categorical factor", "categorical", table.Name, "value", value)
+  matching := table.Default
+  for i := 0; i < len(table.Entries); i++ {
+    if categoryMatches(table.Entries[i], value) {
+      matching = &table.Entries[i]
+      break
+    }
+  }
+  if matching == nil {
+    return nil, nil
+  }
+  current := models.RangeConfig{IsEligible: matching.IsEligible, Value: matching.Value, Label: matching.Label, Operation: matching.Operation}
+  if (current.IsEligible) {
+    return &current, nil
+  }
+  return &current, errors.New("Declined due to :"+current.Label)
+}
+
+// AttributeValue method will return the value of one of the Attributes from the input GeneratePricingRequest, empty when it is not set
+func AttributeValue(input *pricingengine.GeneratePricingRequest, attribute string) string {
+  switch attribute {
+  case AttributeOccupation:
+    return input.Occupation
+  case AttributeFuelType:
+    return input.FuelType
+  case AttributeCoverPurpose:
+    return input.CoverPurpose
+  }
+  return ""
+}
+
+// categoryMatches tells whether the normalised value is one of the entry values or matches its pattern
+func categoryMatches(entry models.CategoricalRange, value string) bool {
+  for _, candidate := range entry.Values {
+    if candidate == value {
+      return true
+    }
+  }
+  return len(entry.Pattern) > 0 && wildcardMatch(entry.Pattern, value)
+}
+
+// wildcardMatch matches the value against a pattern where "*" stands for any run of characters and "?" for one character
+func wildcardMatch(pattern string, value string) bool {
+  p, v := []rune(pattern), []rune(value)
+  i, j, star, mark := 0, 0, -1, 0
+  for j < len(v) {
+    switch {
+    case i < len(p) && (p[i] == '?' || p[i] == v[j]):
+      i, j = i+1, j+1
+    case i < len(p) && p[i] == '*':
+      star, mark = i, j
+      i++
+    case star >= 0:
+      mark++
+      i, j = star+1, mark
+    default:
+      return false
+    }
+  }
+  for i < len(p) && p[i] == '*' {
+    i++
+  }
+  return i == len(p)
+}
+
+// yearsSince computes the number of years elapsed since the date, the same way the age and licence factors do
+func yearsSince(date string, field string) (int, error) {
+  parsed, err := time.Parse("2006-01-02", date)
+  if err != nil {
+    return 0, errors.New("Error wile Parsing "+field+" date. Error: "+ err.Error())
+  }
+  return int(time.Now().Sub(parsed).Hours()/(24*30*12)), nil
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeStrict decodes a single JSON object into target, rejecting empty and null documents,
+// unknown fields and anything following the object, so that a request is validated the same
+// whether it comes alone, on a line of the stream or on a line of a repricing job
+func DecodeStrict(data []byte, target interface{}) error {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return errors.New("request body cannot be empty")
+	case bytes.Equal(trimmed, []byte("null")):
+		return errors.New("request body cannot be null")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return errors.New("invalid request body: " + err.Error())
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid request body: unexpected data after the JSON object at offset %d", decoder.InputOffset())
+	}
+	return nil
+}
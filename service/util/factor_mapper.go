@@ -1,6 +1,7 @@
 package util
 
 import (
+  "fmt"
   "sort"
   "strings"
   "strconv"
@@ -38,12 +39,12 @@ func (f *FactorMapper) DriverAgeFactorToRangeConfig(ageFactors []models.DriverAg
   result := []models.RangeConfig{}
   for i:= 0; i < len(ageFactors); i++ {
     curr := ageFactors[i]
-    c_range := models.RangeConfig{Start: prev, End: curr.Age, Label: "Driver Age:"+strconv.Itoa(prev) +"-"+strconv.Itoa(curr.Age), Value: curr.Factor, IsEligible: curr.IsEligible}
+    c_range := models.RangeConfig{Start: prev, End: curr.Age, Label: "Driver Age:"+strconv.Itoa(prev) +"-"+strconv.Itoa(curr.Age), Value: curr.Factor, IsEligible: curr.IsEligible, Operation: curr.Operation}
     result = append(result, c_range)
     if(i == len(ageFactors)-1) {
       // for last item add boundary range
       end := int((^uint(0))>> 1) // max int range
-      c_range = models.RangeConfig{Start: curr.Age, End: end, Label: "Driver Age >"+strconv.Itoa(curr.Age), Value: curr.Factor, IsEligible: true}
+      c_range = models.RangeConfig{Start: curr.Age, End: end, Label: "Driver Age >"+strconv.Itoa(curr.Age), Value: curr.Factor, IsEligible: true, Operation: curr.Operation}
       result = append(result, c_range)
     }
     prev = curr.Age
@@ -66,7 +67,7 @@ func (f *FactorMapper) InsuranceGroupFactorToRangeConfig(insuranceGroups []model
   	} else {
       end = int((^uint(0))>> 1) // max int range
     }
-    c_range := models.RangeConfig{Start: start, End: end, Label: "Insurance Group:"+curr.Group, Value: curr.Factor, IsEligible: curr.IsEligible}
+    c_range := models.RangeConfig{Start: start, End: end, Label: "Insurance Group:"+curr.Group, Value: curr.Factor, IsEligible: curr.IsEligible, Operation: curr.Operation}
     result = append(result, c_range)
   }
   return result
@@ -86,8 +87,242 @@ func (f *FactorMapper) LicenceValidityFactorToRangeConfig(licenceValidities []mo
   	} else {
       end = int((^uint(0))>> 1) // max int range
     }
-    c_range := models.RangeConfig{Start: start, End: end, Label: "Licence Validity:"+curr.Length, Value: curr.Factor, IsEligible: true}
+    c_range := models.RangeConfig{Start: start, End: end, Label: "Licence Validity:"+curr.Length, Value: curr.Factor, IsEligible: true, Operation: curr.Operation}
     result = append(result, c_range)
   }
   return result
 }
+
+// InteractionFactorToInteractionTable method will go over the list of InteractionFactor and converts the
+// bands of every cell to the ranges of its dimensions, a "*" band covering every value
+// returns the list of converted InteractionTable
+func (f *FactorMapper) InteractionFactorToInteractionTable(interactions []models.InteractionFactor) []models.InteractionTable {
+  result := []models.InteractionTable{}
+  for _, interaction := range interactions {
+    table := models.InteractionTable{Name: interaction.Name, Dimensions: interaction.Dimensions, Cells: []models.InteractionRange{}}
+    for _, cell := range interaction.Cells {
+      c_range := models.InteractionRange{IsEligible: cell.IsEligible, Value: cell.Factor, Label: cell.Label, Operation: cell.Operation}
+      bands := []string{}
+      for i, band := range cell.Bands {
+        start, end := bandToRange(band)
+        c_range.Start = append(c_range.Start, start)
+        c_range.End = append(c_range.End, end)
+        if i < len(interaction.Dimensions) {
+          bands = append(bands, interaction.Dimensions[i]+" "+band)
+        }
+      }
+      if len(c_range.Label) == 0 {
+        c_range.Label = interaction.Name+":"+strings.Join(bands, ", ")
+      }
+      table.Cells = append(table.Cells, c_range)
+    }
+    result = append(result, table)
+  }
+  return result
+}
+
+// bandToRange converts a "start-end", "start" or "*" band to its range, the end of an open band being the max int
+func bandToRange(band string) (start int, end int) {
+  end = int((^uint(0))>> 1) // max int range
+  if band == "*" {
+    return -end - 1, end
+  }
+  s := strings.Split(band, "-")
+  start,_ = strconv.Atoi(s[0])
+  if(len(s) > 1) {
+    end,_ = strconv.Atoi(s[1])
+  }
+  return start, end
+}
+
+// NormaliseTemporalFactors method will lower case the days of the DayFactor bands with NormaliseCategory
+// and label the bands that have no label
+// returns the normalised TemporalFactors
+func (f *FactorMapper) NormaliseTemporalFactors(factors models.TemporalFactors) models.TemporalFactors {
+  hours := []models.HourFactor{}
+  for _, hour := range factors.Hours {
+    if len(hour.Label) == 0 {
+      hour.Label = fmt.Sprintf("Cover start:%02d-%02d", hour.From, hour.To)
+    }
+    hours = append(hours, hour)
+  }
+  days := []models.DayFactor{}
+  for _, day := range factors.Days {
+    normalised := []string{}
+    for _, name := range day.Days {
+      normalised = append(normalised, NormaliseCategory(name))
+    }
+    day.Days = normalised
+    if len(day.Label) == 0 {
+      day.Label = "Cover start:"+strings.Join(normalised, "|")
+    }
+    days = append(days, day)
+  }
+  factors.Hours, factors.Days = hours, days
+  if factors.Holiday != nil && len(factors.Holiday.Label) == 0 {
+    holiday := *factors.Holiday
+    holiday.Label = "Cover start:holiday"
+    factors.Holiday = &holiday
+  }
+  return factors
+}
+
+// NormaliseHistoryLoadings method will normalise the claim types with NormaliseCategory and upper case the
+// conviction codes so that they can be compared to the request
+// returns the normalised HistoryLoadings
+func (f *FactorMapper) NormaliseHistoryLoadings(loadings models.HistoryLoadings) models.HistoryLoadings {
+  types := []models.ClaimLoading{}
+  for _, claim := range loadings.Claims.Types {
+    claim.Type = NormaliseCategory(claim.Type)
+    types = append(types, claim)
+  }
+  codes := []models.ConvictionLoading{}
+  for _, conviction := range loadings.Convictions.Codes {
+    conviction.Code = NormaliseConvictionCode(conviction.Code)
+    codes = append(codes, conviction)
+  }
+  loadings.Claims.Types = types
+  loadings.Convictions.Codes = codes
+  return loadings
+}
+
+// NormaliseVehicles method will go over the list of Vehicle and normalises their Registration and Code
+// with NormaliseVehicleID so that they can be compared to the request
+// returns the list of normalised Vehicle
+func (f *FactorMapper) NormaliseVehicles(vehicles []models.Vehicle) []models.Vehicle {
+  result := []models.Vehicle{}
+  for _, vehicle := range vehicles {
+    vehicle.Registration = NormaliseVehicleID(vehicle.Registration)
+    vehicle.Code = NormaliseVehicleID(vehicle.Code)
+    result = append(result, vehicle)
+  }
+  return result
+}
+
+// NormalisePromoCodes method will go over the list of PromoCode and normalises their Code with NormalisePromoCode
+// and their Durations with NormaliseCategory, a code without label being labelled "Promo:<code>"
+// returns the list of normalised PromoCode
+func (f *FactorMapper) NormalisePromoCodes(promos []models.PromoCode) []models.PromoCode {
+  result := []models.PromoCode{}
+  for _, promo := range promos {
+    promo.Code = NormalisePromoCode(promo.Code)
+    durations := []string{}
+    for _, duration := range promo.Durations {
+      durations = append(durations, NormaliseCategory(duration))
+    }
+    promo.Durations = durations
+    if len(promo.Label) == 0 {
+      promo.Label = "Promo:"+promo.Code
+    }
+    result = append(result, promo)
+  }
+  return result
+}
+
+// NormaliseLoyaltyFactor method normalises the Basis with NormaliseCategory and sorts the bands by From,
+// a band without label being labelled "Loyalty:<from>+ <basis>"
+// returns the normalised LoyaltyFactor
+func (f *FactorMapper) NormaliseLoyaltyFactor(loyalty models.LoyaltyFactor) models.LoyaltyFactor {
+  loyalty.Basis = NormaliseCategory(loyalty.Basis)
+  bands := []models.LoyaltyBand{}
+  for _, band := range loyalty.Bands {
+    if len(band.Label) == 0 {
+      band.Label = "Loyalty:"+strconv.Itoa(band.From)+"+ "+loyalty.Basis
+    }
+    bands = append(bands, band)
+  }
+  sort.SliceStable(bands, func(i, j int) bool {
+    return bands[i].From < bands[j].From
+  })
+  loyalty.Bands = bands
+  return loyalty
+}
+
+// NormaliseDriverCombination method normalises the Rule with NormaliseCategory, a loading without label
+// being labelled "Additional driver"
+// returns the normalised DriverCombination
+func (f *FactorMapper) NormaliseDriverCombination(combination models.DriverCombination) models.DriverCombination {
+  combination.Rule = NormaliseCategory(combination.Rule)
+  if combination.Loading != nil {
+    loading := *combination.Loading
+    if len(loading.Label) == 0 {
+      loading.Label = "Additional driver"
+    }
+    combination.Loading = &loading
+  }
+  return combination
+}
+
+// NormalisePromoCode returns the promo code upper cased without any space, so that "spring 10" is "SPRING10"
+func NormalisePromoCode(value string) string {
+  return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// NormaliseConvictionCode returns the conviction code upper cased without any space, so that "dr 10" is "DR10"
+func NormaliseConvictionCode(value string) string {
+  return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// NormaliseVehicleID returns the registration or vehicle code upper cased without any space, so that "ab12 cde" is "AB12CDE"
+func NormaliseVehicleID(value string) string {
+  return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// PostcodeFactorToPostcodeRange method will go over the list of PostcodeFactor and converts them to PostcodeRange
+// with their prefix normalised by NormalisePostcodePrefix
+// returns the list of converted PostcodeRange
+func (f *FactorMapper) PostcodeFactorToPostcodeRange(postcodes []models.PostcodeFactor) []models.PostcodeRange {
+  result := []models.PostcodeRange{}
+  for _, postcode := range postcodes {
+    prefix, _ := NormalisePostcodePrefix(postcode.Prefix)
+    p_range := models.PostcodeRange{Prefix: prefix, IsEligible: postcode.IsEligible, Value: postcode.Factor, Label: postcode.Label, Operation: postcode.Operation}
+    if len(p_range.Label) == 0 {
+      p_range.Label = "Postcode:"+prefix
+    }
+    result = append(result, p_range)
+  }
+  return result
+}
+
+// CategoricalFactorToCategoricalTable method will go over the list of CategoricalFactor and converts every
+// entry, normalising its values and pattern with NormaliseCategory
+// returns the list of converted CategoricalTable
+func (f *FactorMapper) CategoricalFactorToCategoricalTable(categoricals []models.CategoricalFactor) []models.CategoricalTable {
+  result := []models.CategoricalTable{}
+  for _, categorical := range categoricals {
+    table := models.CategoricalTable{Name: categorical.Name, Attribute: categorical.Attribute, Entries: []models.CategoricalRange{}}
+    for _, entry := range categorical.Entries {
+      c_range := models.CategoricalRange{IsEligible: entry.IsEligible, Value: entry.Factor, Label: entry.Label, Operation: entry.Operation, Values: []string{}}
+      matched := "*"
+      switch {
+      case len(entry.Value) > 0:
+        c_range.Values = append(c_range.Values, NormaliseCategory(entry.Value))
+        matched = c_range.Values[0]
+      case len(entry.Values) > 0:
+        for _, value := range entry.Values {
+          c_range.Values = append(c_range.Values, NormaliseCategory(value))
+        }
+        matched = strings.Join(c_range.Values, "|")
+      case len(entry.Pattern) > 0:
+        c_range.Pattern = NormaliseCategory(entry.Pattern)
+        matched = c_range.Pattern
+      }
+      if len(c_range.Label) == 0 {
+        c_range.Label = categorical.Name+":"+matched
+      }
+      if entry.Default {
+        current := c_range
+        table.Default = &current
+        continue
+      }
+      table.Entries = append(table.Entries, c_range)
+    }
+    result = append(result, table)
+  }
+  return result
+}
+
+// NormaliseCategory returns the categorical value trimmed and lower cased, so that "Business " matches "business"
+func NormaliseCategory(value string) string {
+  return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
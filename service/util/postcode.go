@@ -0,0 +1,56 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// postcodePattern is the syntax of a full UK postcode once normalised, outward and inward code
+	postcodePattern = regexp.MustCompile(`^([A-Z]{1,2}[0-9][A-Z0-9]?) ([0-9][A-Z]{2})$`)
+	// prefixPatterns are the postcode prefixes a postcode factor can be keyed on: area, district and sector
+	prefixPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^[A-Z]{1,2}$`),
+		regexp.MustCompile(`^[A-Z]{1,2}[0-9][A-Z0-9]?$`),
+		regexp.MustCompile(`^[A-Z]{1,2}[0-9][A-Z0-9]? [0-9]$`),
+	}
+	areaPattern = regexp.MustCompile(`^[A-Z]{1,2}`)
+)
+
+// NormalisePostcode returns the postcode upper cased with a single space before the inward code,
+// so that "sw1a1aa" and " SW1A  1AA" are both "SW1A 1AA"
+// returns false when it is not a valid UK postcode
+func NormalisePostcode(value string) (string, bool) {
+	compact := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if len(compact) < 5 {
+		return compact, false
+	}
+	normalised := compact[:len(compact)-3] + " " + compact[len(compact)-3:]
+	return normalised, postcodePattern.MatchString(normalised)
+}
+
+// NormalisePostcodePrefix returns the prefix of a postcode factor normalised the way NormalisePostcode does,
+// e.g. "sw1a" is "SW1A" and "sw1a  1" is "SW1A 1", a sector keeps the space before its digit
+// returns false when it is neither an area, a district, a sector nor a full postcode
+func NormalisePostcodePrefix(value string) (string, bool) {
+	if postcode, ok := NormalisePostcode(value); ok {
+		return postcode, true
+	}
+	normalised := strings.ToUpper(strings.Join(strings.Fields(value), " "))
+	for _, pattern := range prefixPatterns {
+		if pattern.MatchString(normalised) {
+			return normalised, true
+		}
+	}
+	return normalised, false
+}
+
+// PostcodePrefixes returns the prefixes a normalised postcode is matched on, longest first:
+// the full postcode, its sector, its district and its area, e.g. "SW1A 1AA", "SW1A 1", "SW1A", "SW"
+func PostcodePrefixes(postcode string) []string {
+	parts := postcodePattern.FindStringSubmatch(postcode)
+	if parts == nil {
+		return nil
+	}
+	return []string{postcode, parts[1] + " " + parts[2][:1], parts[1], areaPattern.FindString(parts[1])}
+}
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+// WrapResponseWriter returns w when an outer middleware already wrapped it, or wraps it otherwise,
+// so that the status and the bytes written are recorded once however many middlewares read them
+func WrapResponseWriter(w http.ResponseWriter, r *http.Request) middleware.WrapResponseWriter {
+	if ww, ok := w.(middleware.WrapResponseWriter); ok {
+		return ww
+	}
+	return middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+}
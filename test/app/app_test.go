@@ -18,7 +18,7 @@ func TestPriceGenerationAppWithActualConfigs(tp *testing.T){
     Cache: config.ConfigCache{
       TimeToLive : 1,
       Fetcher: config.ConfigFetcher{
-        Path: "/../test_configs/",
+        Path: "../test_configs",
       },
     },
   }
@@ -113,11 +113,13 @@ func TestPriceGenerationAppWithActualConfigs(tp *testing.T){
       Premium: 259.349,
       Currency: "£",
       FareGroup: "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6",
+      Breakdown: []pricingengine.PricingStep{{Label: "0.5 hours", Premium: 273}, {Label: "Driver Age:16-26", Factor: 1, Premium: 273}, {Label: "Insurance Group:1-8", Factor: 1, Premium: 273}, {Label: "Licence Validity:6", Factor: 0.95, Premium: 259.349}},
       }, t)
       util.AssertEqual(resp.PricingList[1], pricingengine.PricingItem{
         Premium: 4943.8,
         Currency: "£",
         FareGroup: "96 hours / 4 days, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6",
+        Breakdown: []pricingengine.PricingStep{{Label: "96 hours / 4 days", Premium: 5204}, {Label: "Driver Age:16-26", Factor: 1, Premium: 5204}, {Label: "Insurance Group:1-8", Factor: 1, Premium: 5204}, {Label: "Licence Validity:6", Factor: 0.95, Premium: 4943.8}},
         }, t)
   })
 
@@ -142,11 +144,13 @@ func TestPriceGenerationAppWithActualConfigs(tp *testing.T){
       Premium: 300.3,
       Currency: "£",
       FareGroup: "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:0-6",
+      Breakdown: []pricingengine.PricingStep{{Label: "0.5 hours", Premium: 273}, {Label: "Driver Age:16-26", Factor: 1, Premium: 273}, {Label: "Insurance Group:1-8", Factor: 1, Premium: 273}, {Label: "Licence Validity:0-6", Factor: 1.1, Premium: 300.3}},
       }, t)
       util.AssertEqual(resp.PricingList[1], pricingengine.PricingItem{
         Premium: 5724.4,
         Currency: "£",
         FareGroup: "96 hours / 4 days, Driver Age:16-26, Insurance Group:1-8, Licence Validity:0-6",
+        Breakdown: []pricingengine.PricingStep{{Label: "96 hours / 4 days", Premium: 5204}, {Label: "Driver Age:16-26", Factor: 1, Premium: 5204}, {Label: "Insurance Group:1-8", Factor: 1, Premium: 5204}, {Label: "Licence Validity:0-6", Factor: 1.1, Premium: 5724.4}},
         }, t)
   })
 }
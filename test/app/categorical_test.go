@@ -0,0 +1,109 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/model"
+  "pricingengine/test/util"
+)
+
+const categoricals = `[
+  {
+    "name": "occupation",
+    "attribute": "occupation",
+    "entries": [
+      {"value": "Teacher", "is-eligible": true, "factor": 0.9, "label": "Occupation:teacher"},
+      {"pattern": "*driver*", "is-eligible": false, "factor": 0, "label": "Occupation:professional driver"},
+      {"default": true, "is-eligible": true, "factor": 1.1, "label": "Occupation:other"}
+    ]
+  },
+  {
+    "name": "purpose",
+    "attribute": "cover_purpose",
+    "entries": [
+      {"values": ["social", "commuting"], "is-eligible": true, "factor": 1, "label": "Purpose:personal"},
+      {"value": "business", "is-eligible": true, "factor": 1.25, "label": "Purpose:business"}
+    ]
+  }
+]`
+
+
+func TestPriceGenerationAppWithCategoricalFactors(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  if err := ioutil.WriteFile(filepath.Join(path, config.CategoricalFactorFile), []byte(categoricals), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  now := time.Now()
+  request := func(occupation string, purpose string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+      Occupation: occupation,
+      CoverPurpose: purpose,
+    }
+  }
+  base := "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6"
+
+  tp.Run("TestCategoricalAbsentAttributesLeaveThePrice", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("", ""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+  })
+  tp.Run("TestCategoricalExactValueIsCaseInsensitive", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("  TEACHER", ""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 233.414, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Occupation:teacher", t)
+  })
+  tp.Run("TestCategoricalValueSet", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("", "Commuting"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Purpose:personal", t)
+  })
+  tp.Run("TestCategoricalDefaultEntry", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("Nurse", "business"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Occupation:other, Purpose:business", t)
+  })
+  tp.Run("TestCategoricalNoMatchWithoutDefault", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("", "racing"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+  })
+  tp.Run("TestCategoricalPatternDeclines", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("Taxi Driver", ""))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(len(resp.PricingList), 0, t)
+    util.AssertEqual(resp.Message, "Declined due to :Occupation:professional driver", t)
+  })
+  tp.Run("TestCategoricalListedInPricingConfig", func(t *testing.T) {
+    result, err := testApp.GeneratePricingConfig(context.Background())
+    util.AssertTrue(err == nil, t)
+    tables := result.(map[string]interface{})["categorical-factors"].([]models.CategoricalTable)
+    util.AssertEqual(len(tables), 2, t)
+    util.AssertEqual(tables[0].Entries[0].Values, []string{"teacher"}, t)
+  })
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+  "pricingengine/test/util"
+)
+
+
+func TestPriceGenerationAppWithAdditionalDrivers(tp *testing.T){
+  newApp := func(combination string, t *testing.T) *app.App {
+    path := util.CopyConfigsToTempDir("../test_configs", t)
+    if len(combination) > 0 {
+      if err := ioutil.WriteFile(filepath.Join(path, config.DriverCombinationFile), []byte(combination), 0644); err != nil {
+        t.Fatal(err)
+      }
+    }
+    return &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: path,
+        },
+      },
+    }
+  }
+  today := time.Now()
+  request := func(drivers ...pricingengine.Driver) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: today.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: today.AddDate(-7, 0, 0).Format("2006-01-02"),
+      AdditionalDrivers: drivers,
+    }
+  }
+  novice := pricingengine.Driver{Name: "Jane", DateOfBirth: today.AddDate(-19, 0, 0).Format("2006-01-02"), LicenseHeldSince: today.AddDate(-1, 0, 0).Format("2006-01-02")}
+  experienced := pricingengine.Driver{DateOfBirth: today.AddDate(-24, 0, 0).Format("2006-01-02"), LicenseHeldSince: today.AddDate(-8, 0, 0).Format("2006-01-02")}
+
+  tp.Run("TestDriversRatesTheHighestRiskDriverByDefault", func(t *testing.T) {
+    resp,_ := newApp("", t).GeneratePricing(context.Background(), request(experienced, novice))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26 (Jane), Insurance Group:1-8, Licence Validity:0-6 (Jane)", t)
+    util.AssertEqual(resp.PricingList[0].Premium, 300.3, t)
+  })
+  tp.Run("TestDriversKeepsTheMainDriverWhenRiskiest", func(t *testing.T) {
+    resp,_ := newApp("", t).GeneratePricing(context.Background(), request(experienced))
+
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26 (Main driver), Insurance Group:1-8, Licence Validity:6 (Main driver)", t)
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+  })
+  tp.Run("TestDriversWeightedAverage", func(t *testing.T) {
+    resp,_ := newApp(`{"rule": "weighted-average", "main-weight": 0.6}`, t).GeneratePricing(context.Background(), request(novice))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Breakdown[3], pricingengine.PricingStep{Label: "Licence Validity:6 (weighted average of 2 drivers)", Factor: 1.01, Premium: 275.73}, t)
+  })
+  tp.Run("TestDriversMainDriverPlusLoading", func(t *testing.T) {
+    resp,_ := newApp(`{"rule": "main-plus-loading", "loading": {"factor": 10, "operation": "add"}}`, t).GeneratePricing(context.Background(), request(novice, experienced))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6, Additional driver (Jane), Additional driver (Driver 3)", t)
+    util.AssertEqual(resp.PricingList[0].Premium, 279.349, t)
+  })
+  tp.Run("TestDriversDeclinesNamingTheIneligibleDriver", func(t *testing.T) {
+    young := pricingengine.Driver{DateOfBirth: today.AddDate(-15, 0, 0).Format("2006-01-02"), LicenseHeldSince: today.AddDate(0, -1, 0).Format("2006-01-02")}
+    resp,_ := newApp("", t).GeneratePricing(context.Background(), request(novice, young))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Driver 3: Declined due to :Driver Age:0-16", t)
+  })
+  tp.Run("TestDriversNeedTheirDates", func(t *testing.T) {
+    resp,_ := newApp("", t).GeneratePricing(context.Background(), request(pricingengine.Driver{Name: "Jane", DateOfBirth: novice.DateOfBirth}))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Jane LicenseHeldSince Date cannot be empty", t)
+  })
+  tp.Run("TestDriversWithoutAdditionalDriver", func(t *testing.T) {
+    resp,_ := newApp(`{"rule": "weighted-average"}`, t).GeneratePricing(context.Background(), request())
+
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6", t)
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+  })
+}
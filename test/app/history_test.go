@@ -0,0 +1,153 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/metrics"
+	"pricingengine/service/model"
+  "pricingengine/test/util"
+
+  "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const historyLoadings = `{
+  "claims": {
+    "lookback-years": 5,
+    "max-loading": 1.5,
+    "types": [
+      {"type": "Accident", "fault-factor": 1.25, "non-fault-factor": 1.05, "label": "Claim:accident"},
+      {"type": "theft", "lookback-years": 3, "fault-factor": 1.1, "non-fault-factor": 1.1},
+      {"type": "*", "fault-factor": 1.02, "non-fault-factor": 1.02, "label": "Claim:other"}
+    ]
+  },
+  "convictions": {
+    "lookback-years": 5,
+    "max-loading": 2,
+    "max-points": 12,
+    "codes": [
+      {"code": "SP30", "factor": 1.1, "label": "Speeding"},
+      {"code": "DR10", "lookback-years": 11, "decline": true, "label": "Drink driving"},
+      {"code": "CU*", "factor": 1.3}
+    ]
+  }
+}`
+
+
+func TestPriceGenerationAppWithHistoryLoadings(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  if err := ioutil.WriteFile(filepath.Join(path, config.HistoryLoadingsFile), []byte(historyLoadings), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  now := time.Now()
+  yearsAgo := func(years int) string {
+    return now.AddDate(-years, 0, 0).Format("2006-01-02")
+  }
+  request := func(claims []pricingengine.Claim, convictions []pricingengine.Conviction) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: yearsAgo(20),
+      InsuranceGroup: 7,
+      LicenseHeldSince: yearsAgo(7),
+      Claims: claims,
+      Convictions: convictions,
+    }
+  }
+  base := "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6"
+
+  tp.Run("TestHistoryWithoutClaimsLeavesThePrice", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(nil, nil))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+    util.AssertEqual(len(resp.PricingList[0].Breakdown), 4, t)
+  })
+  tp.Run("TestHistoryLoadingsInTheBreakdown", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(
+      []pricingengine.Claim{{Date: yearsAgo(1), Type: "accident", Fault: true}},
+      []pricingengine.Conviction{{Code: "sp30", Date: yearsAgo(2), Points: 3}},
+    ))
+
+    util.AssertTrue(resp.IsEligible, t)
+    breakdown := resp.PricingList[0].Breakdown
+    util.AssertEqual(len(breakdown), 6, t)
+    util.AssertEqual(breakdown[4], pricingengine.PricingStep{Label: "Claim:accident (fault "+yearsAgo(1)+")", Factor: 1.25, Premium: 324.186}, t)
+    util.AssertEqual(breakdown[5], pricingengine.PricingStep{Label: "Speeding ("+yearsAgo(2)+")", Factor: 1.1, Premium: 356.604}, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 356.604, t)
+  })
+  tp.Run("TestHistoryLookbackWindows", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(
+      []pricingengine.Claim{{Date: yearsAgo(6), Type: "accident", Fault: true}, {Date: yearsAgo(4), Type: "theft"}, {Date: yearsAgo(4), Type: "Windscreen"}},
+      []pricingengine.Conviction{{Code: "SP30", Date: yearsAgo(6), Points: 3}},
+    ))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Claim:other (non-fault "+yearsAgo(4)+")", t)
+  })
+  tp.Run("TestHistoryLoadingsCapped", func(t *testing.T) {
+    claims := []pricingengine.Claim{}
+    for i := 1; i <= 3; i++ {
+      claims = append(claims, pricingengine.Claim{Date: yearsAgo(i), Type: "accident", Fault: true})
+    }
+    resp,_ := testApp.GeneratePricing(context.Background(), request(claims, nil))
+
+    util.AssertTrue(resp.IsEligible, t)
+    breakdown := resp.PricingList[0].Breakdown
+    // 1.25 and then 1.2 to reach the 1.5 cap, the third claim is not loaded any more
+    util.AssertEqual(len(breakdown), 6, t)
+    util.AssertEqual(breakdown[5].Label, "Claim:accident (fault "+yearsAgo(2)+") (capped)", t)
+    util.AssertEqual(breakdown[5].Factor, 1.2, t)
+  })
+  tp.Run("TestHistoryDeclinesOnConvictionCode", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(nil, []pricingengine.Conviction{{Code: "DR 10", Date: yearsAgo(8), Points: 3}}))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(len(resp.PricingList), 0, t)
+    util.AssertEqual(resp.Message, "Declined due to :Drink driving ("+yearsAgo(8)+")", t)
+  })
+  tp.Run("TestHistoryDeclineIsCountedWithoutItsDate", func(t *testing.T) {
+    counter := metrics.QuotesDeclined.WithLabelValues("convictions", "Drink driving")
+    before := testutil.ToFloat64(counter)
+    testApp.GeneratePricing(context.Background(), request(nil, []pricingengine.Conviction{{Code: "DR10", Date: yearsAgo(6), Points: 3}}))
+    testApp.GeneratePricing(context.Background(), request(nil, []pricingengine.Conviction{{Code: "DR10", Date: yearsAgo(7), Points: 3}}))
+    util.AssertEqual(testutil.ToFloat64(counter), before + 2, t)
+  })
+  tp.Run("TestHistoryDeclinesOnPoints", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(nil, []pricingengine.Conviction{
+      {Code: "SP30", Date: yearsAgo(1), Points: 6},
+      {Code: "CU80", Date: yearsAgo(2), Points: 6},
+    }))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Declined due to :Conviction points:12", t)
+  })
+  tp.Run("TestHistoryInvalidDates", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request([]pricingengine.Claim{{Date: "14/05/2023", Type: "accident"}}, nil))
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Claim 1 date should be a date as YYYY-MM-DD", t)
+
+    resp,_ = testApp.GeneratePricing(context.Background(), request(nil, []pricingengine.Conviction{{Code: "SP30", Date: now.AddDate(0, 0, 2).Format("2006-01-02")}}))
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Conviction 1 date cannot be in the future", t)
+  })
+  tp.Run("TestHistoryListedInPricingConfig", func(t *testing.T) {
+    result, err := testApp.GeneratePricingConfig(context.Background())
+    util.AssertTrue(err == nil, t)
+    loadings := result.(map[string]interface{})["history-loadings"].(*models.HistoryLoadings)
+    util.AssertEqual(loadings.Claims.Types[0].Type, "accident", t)
+    util.AssertEqual(loadings.Convictions.MaxPoints, 12, t)
+  })
+}
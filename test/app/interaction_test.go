@@ -0,0 +1,92 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/model"
+  "pricingengine/test/util"
+)
+
+const interactions = `[
+  {
+    "name": "young-high-group",
+    "dimensions": ["driver_age", "insurance_group"],
+    "cells": [
+      {"bands": ["16-26", "4-8"], "is-eligible": true, "factor": 1.5, "label": "Young driver, high group"},
+      {"bands": ["16-21", "1-2"], "is-eligible": false, "factor": 0, "label": "Young driver, group 2"}
+    ]
+  }
+]`
+
+
+func TestPriceGenerationAppWithInteractionFactors(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  if err := ioutil.WriteFile(filepath.Join(path, config.InteractionFactorFile), []byte(interactions), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  now := time.Now()
+  licence := now.AddDate(-7, 0, 0).Format("2006-01-02")
+
+  tp.Run("TestInteractionAppliedAfterTheOtherFactors", func(t *testing.T) {
+    request := pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: licence,
+    }
+    resp,_ := testApp.GeneratePricing(context.Background(),&request)
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(len(resp.PricingList), 2, t)
+    util.AssertEqual(resp.PricingList[0], pricingengine.PricingItem{
+      Premium: 389.023,
+      Currency: "£",
+      FareGroup: "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6, Young driver, high group",
+      Breakdown: []pricingengine.PricingStep{{Label: "0.5 hours", Premium: 273}, {Label: "Driver Age:16-26", Factor: 1, Premium: 273}, {Label: "Insurance Group:1-8", Factor: 1, Premium: 273}, {Label: "Licence Validity:6", Factor: 0.95, Premium: 259.349}, {Label: "Young driver, high group", Factor: 1.5, Premium: 389.023}},
+      }, t)
+  })
+  tp.Run("TestInteractionNotMatchingLeavesThePrice", func(t *testing.T) {
+    request := pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 3,
+      LicenseHeldSince: licence,
+    }
+    resp,_ := testApp.GeneratePricing(context.Background(),&request)
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6", t)
+  })
+  tp.Run("TestInteractionDeclines", func(t *testing.T) {
+    request := pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 2,
+      LicenseHeldSince: licence,
+    }
+    resp,_ := testApp.GeneratePricing(context.Background(),&request)
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(len(resp.PricingList), 0, t)
+    util.AssertEqual(resp.Message, "Declined due to :Young driver, group 2", t)
+  })
+  tp.Run("TestInteractionListedInPricingConfig", func(t *testing.T) {
+    result, err := testApp.GeneratePricingConfig(context.Background())
+    util.AssertTrue(err == nil, t)
+    tables := result.(map[string]interface{})["interaction-factors"].([]models.InteractionTable)
+    util.AssertEqual(len(tables), 1, t)
+    util.AssertEqual(tables[0].Dimensions, []string{"driver_age", "insurance_group"}, t)
+  })
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/customers"
+  "pricingengine/test/util"
+)
+
+
+func TestPriceGenerationAppWithLoyaltyFactor(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  loyalty := `{"basis": "policies", "bands": [{"from": 1, "factor": 0.9}, {"from": 3, "factor": 0.8, "label": "Loyal customer"}]}`
+  if err := ioutil.WriteFile(filepath.Join(path, config.LoyaltyFactorFile), []byte(loyalty), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  store := &customers.Store{Dir: tp.TempDir()}
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+    Customers: store,
+  }
+  today := time.Now()
+  daysAgo := func(days int) string {
+    return today.AddDate(0, 0, -days).Format("2006-01-02")
+  }
+  for _, days := range []int{1000, 600, 200} {
+    store.RecordPolicy("C-CLEAN", pricingengine.CustomerPolicy{BoundOn: daysAgo(days)})
+    store.RecordPolicy("C-CLAIMED", pricingengine.CustomerPolicy{BoundOn: daysAgo(days)})
+  }
+  store.RecordClaim("C-CLAIMED", pricingengine.Claim{Date: daysAgo(800), Type: "accident", Fault: true})
+  request := func(customerID string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: today.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: today.AddDate(-7, 0, 0).Format("2006-01-02"),
+      CustomerID: customerID,
+    }
+  }
+
+  tp.Run("TestLoyaltyWithoutCustomer", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+  })
+  tp.Run("TestLoyaltyForUnknownCustomer", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("C-NEW"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+  })
+  tp.Run("TestLoyaltyForClaimFreeCustomer", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("C-CLEAN"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    breakdown := resp.PricingList[0].Breakdown
+    util.AssertEqual(breakdown[len(breakdown)-1], pricingengine.PricingStep{Label: "Loyal customer (3 claim-free policies)", Factor: 0.8, Premium: 207.479}, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 207.479, t)
+  })
+  tp.Run("TestLoyaltyRunRestartsAfterAClaim", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("C-CLAIMED"))
+
+    breakdown := resp.PricingList[0].Breakdown
+    util.AssertEqual(breakdown[len(breakdown)-1].Label, "Loyalty:1+ policies (2 claim-free policies)", t)
+    util.AssertEqual(resp.PricingList[0].Premium, 233.414, t)
+  })
+  tp.Run("TestLoyaltyRunCountsTheClaimsOfTheRequest", func(t *testing.T) {
+    claimed := request("C-CLEAN")
+    claimed.Claims = []pricingengine.Claim{{Date: daysAgo(100), Type: "theft"}}
+    resp,_ := testApp.GeneratePricing(context.Background(), claimed)
+
+    util.AssertEqual(resp.PricingList[0].Premium, 259.349, t)
+  })
+  tp.Run("TestLoyaltyRejectsInvalidCustomerID", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("../config"))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "CustomerID should be 1 to 64 letters, digits, '-' or '_'", t)
+  })
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/model"
+  "pricingengine/test/util"
+)
+
+const postcodes = `[
+  {"prefix": "E", "is-eligible": true, "factor": 1.2, "label": "Region:East London"},
+  {"prefix": "E1", "is-eligible": true, "factor": 1.5, "label": "Region:Whitechapel"},
+  {"prefix": "E1 6", "is-eligible": false, "factor": 0, "label": "Region:E1 6"},
+  {"prefix": "sw1a", "is-eligible": true, "factor": 2}
+]`
+
+
+func TestPriceGenerationAppWithPostcodeFactor(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  if err := ioutil.WriteFile(filepath.Join(path, config.PostcodeFactorFile), []byte(postcodes), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  now := time.Now()
+  request := func(postcode string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+      Postcode: postcode,
+    }
+  }
+  base := "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6"
+
+  tp.Run("TestPostcodeLongestPrefixApplies", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("e1 7aa"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Region:Whitechapel", t)
+  })
+  tp.Run("TestPostcodeDistrictDoesNotMatchLongerDistrict", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("E10 5NP"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Region:East London", t)
+  })
+  tp.Run("TestPostcodeNormalisedPrefixAndDefaultLabel", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("SW1A1AA"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 518.698, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Postcode:SW1A", t)
+  })
+  tp.Run("TestPostcodeSectorDeclines", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("E1 6AN"))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Declined due to :Region:E1 6", t)
+  })
+  tp.Run("TestPostcodeNotListedLeavesThePrice", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("M1 1AE"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+  })
+  tp.Run("TestPostcodeInvalidSyntax", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("NOT A POSTCODE"))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Postcode should be a valid UK postcode", t)
+  })
+  tp.Run("TestPostcodeListedInPricingConfig", func(t *testing.T) {
+    result, err := testApp.GeneratePricingConfig(context.Background())
+    util.AssertTrue(err == nil, t)
+    prefixes := result.(map[string]interface{})["postcode-factor"].([]models.PostcodeRange)
+    util.AssertEqual(len(prefixes), 4, t)
+    util.AssertEqual(prefixes[3].Prefix, "SW1A", t)
+  })
+}
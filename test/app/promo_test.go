@@ -0,0 +1,143 @@
+package app
+
+import (
+  "context"
+  "errors"
+  "io/ioutil"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+	"pricingengine/service/promo"
+  "pricingengine/test/util"
+)
+
+
+func TestPriceGenerationAppWithPromoCodes(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  today := time.Now()
+  yesterday, tomorrow := today.AddDate(0, 0, -1).Format("2006-01-02"), today.AddDate(0, 0, 1).Format("2006-01-02")
+  promos := `[
+    {"code": "spring 10", "type": "percent-discount", "value": 10, "valid-from": "`+yesterday+`", "valid-to": "`+tomorrow+`", "label": "Spring sale"},
+    {"code": "FIVEOFF", "type": "subtract", "value": 5, "durations": ["0.5 Hours"]},
+    {"code": "BIGSPEND", "type": "percent-discount", "value": 20, "min-premium": 1000},
+    {"code": "HOURLY", "type": "subtract", "value": 5, "durations": ["1 hour"]},
+    {"code": "LIMITED", "type": "subtract", "value": 1, "max-uses": 1},
+    {"code": "EXPIRED", "type": "subtract", "value": 1, "valid-to": "`+yesterday+`"},
+    {"code": "FUTURE", "type": "subtract", "value": 1, "valid-from": "`+tomorrow+`"}
+  ]`
+  if err := ioutil.WriteFile(filepath.Join(path, config.PromoCodeFile), []byte(promos), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+    PromoUsage: &promo.UsageStore{File: filepath.Join(tp.TempDir(), "promo-usage.json")},
+  }
+  request := func(code string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: today.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: today.AddDate(-7, 0, 0).Format("2006-01-02"),
+      PromoCode: code,
+    }
+  }
+  undiscounted,_ := testApp.GeneratePricing(context.Background(), request(""))
+
+  tp.Run("TestPromoWithoutCode", func(t *testing.T) {
+    util.AssertTrue(undiscounted.IsEligible, t)
+    util.AssertTrue(undiscounted.Promo == nil, t)
+    util.AssertEqual(undiscounted.PricingList[0].Premium, 259.349, t)
+  })
+  tp.Run("TestPromoDiscountLine", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("Spring10"))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(*resp.Promo, pricingengine.Promo{Code: "SPRING10", Applied: true}, t)
+    breakdown := resp.PricingList[0].Breakdown
+    util.AssertEqual(breakdown[len(breakdown)-1], pricingengine.PricingStep{Label: "Spring sale", Factor: 10, Operation: "percent-discount", Premium: 233.414}, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 233.414, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, undiscounted.PricingList[0].FareGroup+", Spring sale", t)
+  })
+  tp.Run("TestPromoOnlyForItsDurations", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("FIVEOFF"))
+
+    util.AssertTrue(resp.Promo.Applied, t)
+    util.AssertEqual(resp.PricingList[0].Premium, 254.349, t)
+    util.AssertEqual(resp.PricingList[1], undiscounted.PricingList[1], t)
+  })
+  tp.Run("TestPromoOnlyFromItsMinPremium", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request("BIGSPEND"))
+
+    util.AssertTrue(resp.Promo.Applied, t)
+    util.AssertEqual(resp.PricingList[0], undiscounted.PricingList[0], t)
+    util.AssertEqual(resp.PricingList[1].FareGroup, undiscounted.PricingList[1].FareGroup+", Promo:BIGSPEND", t)
+  })
+  tp.Run("TestPromoRejected", func(t *testing.T) {
+    for code, reason := range map[string]string{
+      "NOPE": "Promo code NOPE is not known",
+      "expired": "Promo code EXPIRED expired on "+yesterday,
+      "FUTURE": "Promo code FUTURE is not valid before "+tomorrow,
+      "HOURLY": "Promo code HOURLY does not apply to 0.5 hours",
+    } {
+      resp,_ := testApp.GeneratePricing(context.Background(), request(code))
+
+      util.AssertTrue(resp.IsEligible, t)
+      util.AssertEqual(*resp.Promo, pricingengine.Promo{Code: strings.ToUpper(code), Reason: reason}, t)
+      util.AssertEqual(resp.PricingList, undiscounted.PricingList, t)
+    }
+  })
+  tp.Run("TestPromoRequotedWithoutUsingIt", func(t *testing.T) {
+    for i := 0; i < 3; i++ {
+      resp,_ := testApp.GeneratePricing(context.Background(), request("LIMITED"))
+      util.AssertTrue(resp.Promo.Applied, t)
+    }
+    uses, _ := testApp.PromoUsage.Uses("LIMITED")
+    util.AssertEqual(uses, 0, t)
+  })
+  redemption := func(duration string) *pricingengine.PromoRedemption {
+    return &pricingengine.PromoRedemption{Request: *request(""), Duration: duration}
+  }
+  tp.Run("TestPromoUsageLimit", func(t *testing.T) {
+    redeemed, err := testApp.RedeemPromoCode(context.Background(), "limited", redemption("0.5 hours"))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(*redeemed, pricingengine.Promo{Code: "LIMITED", Applied: true}, t)
+    _, err = testApp.RedeemPromoCode(context.Background(), "LIMITED", redemption("0.5 hours"))
+    util.AssertEqual(err.Error(), "Promo code LIMITED has reached its usage limit", t)
+    util.AssertTrue(errors.Is(err, app.ErrPromoCodeUsedUp), t)
+
+    resp,_ := testApp.GeneratePricing(context.Background(), request("LIMITED"))
+    util.AssertFalse(resp.Promo.Applied, t)
+    util.AssertEqual(resp.Promo.Reason, "Promo code LIMITED has reached its usage limit", t)
+    util.AssertEqual(resp.PricingList, undiscounted.PricingList, t)
+  })
+  tp.Run("TestPromoRedeemRejected", func(t *testing.T) {
+    _, err := testApp.RedeemPromoCode(context.Background(), "NOPE", redemption("0.5 hours"))
+    util.AssertEqual(err.Error(), "Promo code NOPE is not known", t)
+    util.AssertTrue(errors.Is(err, app.ErrPromoCodeNotFound), t)
+    _, err = testApp.RedeemPromoCode(context.Background(), "EXPIRED", redemption("0.5 hours"))
+    util.AssertEqual(err, error(&app.PromoError{Code: "EXPIRED", Reason: "Promo code EXPIRED expired on "+yesterday}), t)
+  })
+  tp.Run("TestPromoRedeemOnlyAgainstAQuoteItAppliesTo", func(t *testing.T) {
+    _, err := testApp.RedeemPromoCode(context.Background(), "HOURLY", redemption("0.5 hours"))
+    util.AssertEqual(err.Error(), "Promo code HOURLY does not apply to 0.5 hours", t)
+    _, err = testApp.RedeemPromoCode(context.Background(), "BIGSPEND", redemption("0.5 hours"))
+    util.AssertEqual(err.Error(), "Promo code BIGSPEND needs a premium of at least 1000", t)
+    _, err = testApp.RedeemPromoCode(context.Background(), "BIGSPEND", redemption("1 week"))
+    util.AssertEqual(err.Error(), "Quote has no price for 1 week", t)
+    _, err = testApp.RedeemPromoCode(context.Background(), "HOURLY", &pricingengine.PromoRedemption{Duration: "1 hour"})
+    util.AssertEqual(err.Error(), "Quote can not be priced: DateOfBirth cannot be empty", t)
+
+    redeemed, err := testApp.RedeemPromoCode(context.Background(), "big spend", redemption("96 Hours / 4 days"))
+    util.AssertTrue(err == nil, t)
+    util.AssertTrue(redeemed.Applied, t)
+  })
+}
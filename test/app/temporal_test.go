@@ -0,0 +1,136 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+  "pricingengine/test/util"
+)
+
+const temporalFactors = `{
+  "timezone": "Europe/London",
+  "hours": [
+    {"from": 2, "to": 4, "is-eligible": false, "factor": 0},
+    {"from": 22, "to": 6, "is-eligible": true, "factor": 1.2, "label": "Night"}
+  ],
+  "days": [
+    {"days": ["Saturday", "sunday"], "is-eligible": true, "factor": 1.1, "label": "Weekend"}
+  ],
+  "holiday": {"is-eligible": true, "factor": 1.3, "label": "Holiday"}
+}`
+
+
+func TestPriceGenerationAppWithTemporalFactors(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  london, err := time.LoadLocation("Europe/London")
+  if err != nil {
+    tp.Fatal(err)
+  }
+  // the next day of the week at least two days ahead, at the given hour in London
+  next := func(weekday time.Weekday, hour int) time.Time {
+    day := time.Now().In(london).AddDate(0, 0, 2)
+    for day.Weekday() != weekday {
+      day = day.AddDate(0, 0, 1)
+    }
+    return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, london)
+  }
+  holidays := `[{"date": "`+next(time.Thursday, 0).Format("2006-01-02")+`", "name": "Test Day"}]`
+  for file, data := range map[string]string{config.TemporalFactorFile: temporalFactors, config.HolidayFile: holidays} {
+    if err := ioutil.WriteFile(filepath.Join(path, file), []byte(data), 0644); err != nil {
+      tp.Fatal(err)
+    }
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  request := func(coverStart string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: time.Now().AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: 7,
+      LicenseHeldSince: time.Now().AddDate(-7, 0, 0).Format("2006-01-02"),
+      CoverStart: coverStart,
+    }
+  }
+  base := "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6"
+
+  tp.Run("TestTemporalWithoutCoverStart", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+  })
+  tp.Run("TestTemporalWithoutMatchingBand", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(next(time.Wednesday, 12).Format(time.RFC3339)))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base, t)
+  })
+  tp.Run("TestTemporalHourBandWrapsMidnight", func(t *testing.T) {
+    for _, hour := range []int{23, 5} {
+      resp,_ := testApp.GeneratePricing(context.Background(), request(next(time.Wednesday, hour).Format(time.RFC3339)))
+
+      util.AssertTrue(resp.IsEligible, t)
+      util.AssertEqual(resp.PricingList[0].FareGroup, base+", Night", t)
+    }
+  })
+  tp.Run("TestTemporalResolvedInTheTimezone", func(t *testing.T) {
+    utc,_ := testApp.GeneratePricing(context.Background(), request(next(time.Wednesday, 23).UTC().Format(time.RFC3339)))
+    local,_ := testApp.GeneratePricing(context.Background(), request(next(time.Wednesday, 23).Format("2006-01-02T15:04:05")))
+
+    util.AssertEqual(utc.PricingList[0].FareGroup, base+", Night", t)
+    util.AssertEqual(local.PricingList[0].FareGroup, base+", Night", t)
+  })
+  tp.Run("TestTemporalDayAndHourBands", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(next(time.Saturday, 23).Format(time.RFC3339)))
+
+    util.AssertTrue(resp.IsEligible, t)
+    breakdown := resp.PricingList[0].Breakdown
+    util.AssertEqual(breakdown[len(breakdown)-2].Label, "Night", t)
+    util.AssertEqual(breakdown[len(breakdown)-1], pricingengine.PricingStep{Label: "Weekend", Factor: 1.1, Premium: resp.PricingList[0].Premium}, t)
+  })
+  tp.Run("TestTemporalHoliday", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(next(time.Thursday, 12).Format(time.RFC3339)))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, base+", Holiday (Test Day)", t)
+  })
+  tp.Run("TestTemporalDeclinesOnHourBand", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(next(time.Wednesday, 3).Format(time.RFC3339)))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(len(resp.PricingList), 0, t)
+    util.AssertEqual(resp.Message, "Declined due to :Cover start:02-04", t)
+  })
+  tp.Run("TestCoverStartValidation", func(t *testing.T) {
+    for coverStart, message := range map[string]string{
+      "tomorrow": "CoverStart should be a timestamp as YYYY-MM-DDTHH:MM:SS with an optional offset",
+      time.Now().Add(-time.Hour).Format(time.RFC3339): "CoverStart cannot be in the past",
+      time.Now().AddDate(0, 0, 40).Format(time.RFC3339): "CoverStart cannot be more than 30 days ahead",
+    } {
+      resp,_ := testApp.GeneratePricing(context.Background(), request(coverStart))
+
+      util.AssertFalse(resp.IsEligible, t)
+      util.AssertEqual(resp.Message, message, t)
+    }
+    resp,_ := testApp.GeneratePricing(context.Background(), request(time.Now().Add(-time.Minute).Format(time.RFC3339)))
+    util.AssertTrue(resp.IsEligible, t)
+  })
+  tp.Run("TestCoverStartMaxAhead", func(t *testing.T) {
+    limited := app.App{Cache: config.ConfigCache{TimeToLive: 1, Fetcher: config.ConfigFetcher{Path: path}}, MaxCoverStartAhead: 48 * time.Hour}
+    resp,_ := limited.GeneratePricing(context.Background(), request(time.Now().Add(72 * time.Hour).Format(time.RFC3339)))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "CoverStart cannot be more than 2 days ahead", t)
+  })
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+  "context"
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/app"
+	"pricingengine/service/config"
+  "pricingengine/test/util"
+)
+
+func TestPriceGenerationAppWithVehicleTable(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  now := time.Now()
+  registered := now.AddDate(-3, 0, -10).Format("2006-01-02")
+  vehicles := `[
+    {"registration": "AB12 CDE", "abi-code": "32120101", "make": "Ford", "model": "Fiesta", "insurance-group": 7, "value": 12000, "registered": "` + registered + `"},
+    {"registration": "XY70 ZZZ", "abi-code": "48020611", "make": "Porsche", "model": "911", "insurance-group": 50, "value": 95000, "registered": "` + registered + `"}
+  ]`
+  if err := ioutil.WriteFile(filepath.Join(path, config.VehicleFile), []byte(vehicles), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+  }
+  request := func(group int, registration string, code string) *pricingengine.GeneratePricingRequest {
+    return &pricingengine.GeneratePricingRequest{
+      DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+      InsuranceGroup: group,
+      LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+      VehicleRegistration: registration,
+      VehicleCode: code,
+    }
+  }
+
+  tp.Run("TestVehicleResolvedByRegistration", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(0, "ab12cde", ""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.PricingList[0].FareGroup, "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6", t)
+    util.AssertEqual(*resp.Vehicle, pricingengine.Vehicle{
+      Registration: "AB12CDE",
+      Code: "32120101",
+      Make: "Ford",
+      Model: "Fiesta",
+      InsuranceGroup: 7,
+      Value: 12000,
+      Age: 3,
+    }, t)
+    // the response echoes the request as it was sent
+    util.AssertEqual(resp.Input.InsuranceGroup, 0, t)
+  })
+  tp.Run("TestVehicleGroupOverridesTheCallerGroup", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(3, "XY70 ZZZ", ""))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Declined due to :Insurance Group:8", t)
+    util.AssertEqual(resp.Vehicle.InsuranceGroup, 50, t)
+  })
+  tp.Run("TestVehicleResolvedByCode", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(0, "", " 32120101 "))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.Vehicle.Registration, "AB12CDE", t)
+  })
+  tp.Run("TestVehicleNotFound", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(7, "ZZ99 ZZZ", ""))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertTrue(resp.Vehicle == nil, t)
+    util.AssertEqual(resp.Message, "Vehicle registration ZZ99ZZZ was not found", t)
+  })
+  tp.Run("TestVehicleUnknownRegistrationFallsBackToTheCode", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(0, "ZZ99 ZZZ", "48020611"))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Declined due to :Insurance Group:8", t)
+    util.AssertEqual(resp.Vehicle.Code, "48020611", t)
+    util.AssertEqual(resp.Vehicle.Registration, "XY70ZZZ", t)
+  })
+  tp.Run("TestVehicleUnknownRegistrationAndCode", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(7, "ZZ99 ZZZ", "99999999"))
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Vehicle registration ZZ99ZZZ and code 99999999 were not found", t)
+  })
+  tp.Run("TestVehicleCallerGroupWithoutVehicle", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), request(7, "", ""))
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertTrue(resp.Vehicle == nil, t)
+  })
+}
+
+func TestPriceGenerationAppWithoutVehicleTable(tp *testing.T){
+  testApp := app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: "../test_configs",
+      },
+    },
+  }
+  now := time.Now()
+  request := pricingengine.GeneratePricingRequest{
+    DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+    LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+    VehicleRegistration: "AB12 CDE",
+  }
+  tp.Run("TestVehicleWithoutLookupNeedsTheCallerGroup", func(t *testing.T) {
+    resp,_ := testApp.GeneratePricing(context.Background(), &request)
+
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "InsuranceGroup should be a Positive number", t)
+  })
+  tp.Run("TestVehicleWithoutLookupUsesTheCallerGroup", func(t *testing.T) {
+    request.InsuranceGroup = 7
+    resp,_ := testApp.GeneratePricing(context.Background(), &request)
+
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertTrue(resp.Vehicle == nil, t)
+  })
+}
@@ -0,0 +1,401 @@
+package auth
+
+import (
+  "bytes"
+  "context"
+  "crypto/rand"
+  "crypto/rsa"
+  "crypto/sha256"
+  "crypto/x509"
+  "crypto/x509/pkix"
+  "encoding/hex"
+  "encoding/json"
+  "encoding/pem"
+  "fmt"
+  "io/ioutil"
+  "net"
+  "net/http"
+  "net/http/httptest"
+  "net/url"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/auth"
+  "pricingengine/service/config"
+  "pricingengine/service/grpcapi"
+  "pricingengine/service/grpcapi/pricingpb"
+  "pricingengine/service/jobs"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+
+  "github.com/golang-jwt/jwt/v5"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials/insecure"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "google.golang.org/grpc/test/bufconn"
+)
+
+
+const jwtSecret = "0123456789abcdef0123456789abcdef"
+
+const apiKeys = `{"keys": [
+  {"client_id": "broker", "key": "broker-key", "scopes": ["pricing:quote"]},
+  {"client_id": "analyst", "key_sha256": "%s", "scopes": ["config:read"]},
+  {"client_id": "ops", "key": "ops-key", "scopes": ["pricing:quote", "config:read", "config:admin"]}
+]}`
+
+func WriteFile(dir string, name string, content string, t *testing.T) string {
+  filename := filepath.Join(dir, name)
+  if err := ioutil.WriteFile(filename, []byte(content), 0600); err != nil {
+    t.Fatal(err)
+  }
+  return filename
+}
+
+// WriteCredentials writes the API key file, the HS256 secret and the RS256 public key of the
+// returned private key to a temporary directory
+func WriteCredentials(t *testing.T) (auth.Options, *rsa.PrivateKey) {
+  dir := t.TempDir()
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  if err != nil {
+    t.Fatal(err)
+  }
+  public, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+  digest := sha256.Sum256([]byte("analyst-key"))
+  keys := fmt.Sprintf(apiKeys, hex.EncodeToString(digest[:]))
+  return auth.Options{
+    APIKeysFile: WriteFile(dir, "api-keys.json", keys, t),
+    JWTSecretFile: WriteFile(dir, "jwt.secret", jwtSecret + "\n", t),
+    JWTPublicKeyFile: WriteFile(dir, "jwt.pem", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})), t),
+    JWTIssuer: "https://issuer.example",
+  }, key
+}
+
+func SignToken(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims, t *testing.T) string {
+  token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+  if err != nil {
+    t.Fatal(err)
+  }
+  return token
+}
+
+func ValidClaims(scope string) jwt.MapClaims {
+  return jwt.MapClaims{
+    "sub": "partner",
+    "iss": "https://issuer.example",
+    "exp": time.Now().Add(time.Hour).Unix(),
+    "scope": scope,
+  }
+}
+
+func Serve(router http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+  req := httptest.NewRequest(method, target, strings.NewReader(body))
+  req.Header.Set("Content-Type", "application/json")
+  for name := range header {
+    req.Header.Set(name, header.Get(name))
+  }
+  recorder := httptest.NewRecorder()
+  router.ServeHTTP(recorder, req)
+  return recorder
+}
+
+func Bearer(token string) http.Header {
+  header := http.Header{}
+  header.Set("Authorization", "Bearer " + token)
+  return header
+}
+
+func ReadAuthError(recorder *httptest.ResponseRecorder) pricingengine.AuthError {
+  body := pricingengine.AuthError{}
+  json.Unmarshal(recorder.Body.Bytes(), &body)
+  return body
+}
+
+func TestAuthenticator(tp *testing.T){
+  options, key := WriteCredentials(tp)
+  authenticator, err := auth.New(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+
+  tp.Run("TestAPIKeyInHeaderOrAsBearer", func(t *testing.T) {
+    principal, err := authenticator.Authenticate("", "broker-key")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(principal.ClientID, "broker", t)
+    util.AssertEqual(principal.Method, auth.MethodAPIKey, t)
+    principal, err = authenticator.Authenticate("Bearer ops-key", "")
+    util.AssertTrue(err == nil, t)
+    util.AssertTrue(principal.HasScope(auth.ScopeConfigAdmin), t)
+  })
+  tp.Run("TestMissingAndInvalidCredentials", func(t *testing.T) {
+    _, err := authenticator.Authenticate("", "")
+    util.AssertEqual(err, auth.ErrNoCredentials, t)
+    _, err = authenticator.Authenticate("", "stolen-key")
+    util.AssertEqual(err.Error(), "invalid API key", t)
+    _, err = authenticator.Authenticate("Basic b3BzOm9wcw==", "")
+    util.AssertEqual(err.Error(), "the Authorization header should be a bearer token", t)
+  })
+  tp.Run("TestHS256AndRS256Tokens", func(t *testing.T) {
+    token := SignToken(jwt.SigningMethodHS256, []byte(jwtSecret), ValidClaims("pricing:quote config:read"), t)
+    principal, err := authenticator.Authenticate("Bearer " + token, "")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(principal.ClientID, "partner", t)
+    util.AssertEqual(principal.Method, auth.MethodJWT, t)
+    util.AssertEqual(principal.Scopes, []string{"pricing:quote", "config:read"}, t)
+
+    claims := ValidClaims("")
+    claims["client_id"] = "partner-app"
+    claims["scopes"] = []string{"config:admin"}
+    token = SignToken(jwt.SigningMethodRS256, key, claims, t)
+    principal, err = authenticator.Authenticate("Bearer " + token, "")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(principal.ClientID, "partner-app", t)
+    util.AssertEqual(principal.Scopes, []string{"config:admin"}, t)
+  })
+  tp.Run("TestRejectedTokens", func(t *testing.T) {
+    expired := ValidClaims("pricing:quote")
+    expired["exp"] = time.Now().Add(-time.Hour).Unix()
+    noExpiry := ValidClaims("pricing:quote")
+    delete(noExpiry, "exp")
+    otherIssuer := ValidClaims("pricing:quote")
+    otherIssuer["iss"] = "https://elsewhere.example"
+    otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    for name, token := range map[string]string{
+      "expired": SignToken(jwt.SigningMethodHS256, []byte(jwtSecret), expired, t),
+      "no expiry": SignToken(jwt.SigningMethodHS256, []byte(jwtSecret), noExpiry, t),
+      "other issuer": SignToken(jwt.SigningMethodHS256, []byte(jwtSecret), otherIssuer, t),
+      "wrong secret": SignToken(jwt.SigningMethodHS256, []byte("another secret of at least 32 bytes"), ValidClaims("pricing:quote"), t),
+      "wrong key": SignToken(jwt.SigningMethodRS256, otherKey, ValidClaims("pricing:quote"), t),
+      "HS512": SignToken(jwt.SigningMethodHS512, []byte(jwtSecret), ValidClaims("pricing:quote"), t),
+      "none": SignToken(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, ValidClaims("pricing:quote"), t),
+    } {
+      _, err := authenticator.Authenticate("Bearer " + token, "")
+      if err == nil || !strings.HasPrefix(err.Error(), "invalid token") {
+        t.Errorf("%s token should be rejected, got %v", name, err)
+      }
+    }
+  })
+  tp.Run("TestInvalidAPIKeyFiles", func(t *testing.T) {
+    dir := t.TempDir()
+    for content, problem := range map[string]string{
+      `{"keys": [{"client_id": "a", "key": "k", "scopes": ["pricing:write"]}]}`: "keys[0].scopes: unknown scope pricing:write",
+      `{"keys": [{"client_id": "a", "key": "k", "scopes": []}]}`: "keys[0].scopes cannot be empty",
+      `{"keys": [{"key": "k", "scopes": ["config:read"]}]}`: "keys[0].client_id cannot be empty",
+      `{"keys": [{"client_id": "a", "key_sha256": "abc", "scopes": ["config:read"]}]}`: "keys[0].key_sha256 should be a hex encoded SHA-256 digest",
+      `{"keys": [{"client_id": "a", "key": "k", "scopes": ["config:read"]}, {"client_id": "b", "key": "k", "scopes": ["config:read"]}]}`: "keys[1] is a duplicate key",
+      `{"clients": []}`: "unknown field",
+    } {
+      _, err := auth.New(auth.Options{APIKeysFile: WriteFile(dir, "keys.json", content, t)})
+      util.AssertTrue(err != nil && strings.Contains(err.Error(), problem), t)
+    }
+    _, err := auth.New(auth.Options{JWTSecretFile: WriteFile(dir, "short.secret", "too short", t)})
+    util.AssertTrue(err != nil, t)
+  })
+  tp.Run("TestClientCertificates", func(t *testing.T) {
+    dir := t.TempDir()
+    content := `{"keys": [], "certificates": [
+      {"client_id": "gateway", "subject": "spiffe://partner.example/gateway", "scopes": ["pricing:quote"]},
+      {"client_id": "batch", "subject": "batch.partner.example", "scopes": ["config:read"]}
+    ]}`
+    authenticator, err := auth.New(auth.Options{APIKeysFile: WriteFile(dir, "keys.json", content, t)})
+    util.AssertTrue(err == nil, t)
+    uri, _ := url.Parse("spiffe://partner.example/gateway")
+    principal, err := authenticator.AuthenticateCertificate(&x509.Certificate{URIs: []*url.URL{uri}, Subject: pkix.Name{CommonName: "batch.partner.example"}})
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(principal.ClientID, "gateway", t)
+    util.AssertEqual(principal.Method, auth.MethodCertificate, t)
+    principal, err = authenticator.AuthenticateCertificate(&x509.Certificate{Subject: pkix.Name{CommonName: "batch.partner.example"}})
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(principal.ClientID, "batch", t)
+    _, err = authenticator.AuthenticateCertificate(&x509.Certificate{DNSNames: []string{"other.example"}})
+    util.AssertTrue(err != nil, t)
+
+    _, err = auth.New(auth.Options{APIKeysFile: WriteFile(dir, "keys.json", `{"certificates": [{"client_id": "a", "scopes": ["config:read"]}]}`, t)})
+    util.AssertTrue(err != nil && strings.Contains(err.Error(), "certificates[0].subject cannot be empty"), t)
+  })
+}
+
+func TestAuthenticatedEndpoints(tp *testing.T){
+  options, _ := WriteCredentials(tp)
+  authenticator, err := auth.New(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: util.CopyConfigsToTempDir("../test_configs", tp),
+        },
+      },
+    },
+    AdminToken: "legacy-token",
+    Auth: authenticator,
+  }
+  router := service.NewRouter(&rpc, settings.Default())
+  quote := `{"date_of_birth":"1990-01-01","insurance_group":7,"license_held_since":"2010-01-01"}`
+  broker := http.Header{}
+  broker.Set(auth.APIKeyHeader, "broker-key")
+
+  tp.Run("TestMissingCredentialsAreAnswered401", func(t *testing.T) {
+    recorder := Serve(router, http.MethodPost, "/generate_pricing", quote, nil)
+    util.AssertEqual(recorder.Code, http.StatusUnauthorized, t)
+    util.AssertEqual(recorder.Header().Get("WWW-Authenticate"), `Bearer error="invalid_request"`, t)
+    util.AssertEqual(ReadAuthError(recorder), pricingengine.AuthError{Code: "invalid_request", Message: "missing credentials"}, t)
+  })
+  tp.Run("TestInvalidCredentialsAreAnswered401", func(t *testing.T) {
+    recorder := Serve(router, http.MethodPost, "/generate_pricing", quote, Bearer("stolen-key"))
+    util.AssertEqual(recorder.Code, http.StatusUnauthorized, t)
+    util.AssertEqual(ReadAuthError(recorder).Code, "invalid_token", t)
+    util.AssertEqual(recorder.Header().Get("Content-Type"), "application/json", t)
+  })
+  tp.Run("TestMissingScopeIsAnswered403", func(t *testing.T) {
+    recorder := Serve(router, http.MethodGet, "/generate_pricing", "", broker)
+    util.AssertEqual(recorder.Code, http.StatusForbidden, t)
+    util.AssertEqual(recorder.Header().Get("WWW-Authenticate"), `Bearer error="insufficient_scope", scope="config:read"`, t)
+    util.AssertEqual(ReadAuthError(recorder), pricingengine.AuthError{
+      Code: "insufficient_scope",
+      Message: "client broker is not granted the config:read scope",
+      RequiredScope: "config:read",
+    }, t)
+  })
+  tp.Run("TestScopesAreGrantedPerEndpoint", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodPost, "/generate_pricing", quote, broker).Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodPost, "/jobs", `{"requests":[]}`, Bearer("analyst-key")).Code, http.StatusForbidden, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/debug/config-status", "", Bearer("analyst-key")).Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/admin/config/versions", "", broker).Code, http.StatusForbidden, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/customers/C-1042", "", broker).Code, http.StatusForbidden, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/admin/config/versions", "", Bearer("ops-key")).Code, http.StatusOK, t)
+  })
+  tp.Run("TestAdminTokenIsReplacedByTheAdminScope", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/admin/config/versions", "", Bearer("legacy-token")).Code, http.StatusUnauthorized, t)
+  })
+  tp.Run("TestJWTIsAccepted", func(t *testing.T) {
+    token := SignToken(jwt.SigningMethodHS256, []byte(jwtSecret), ValidClaims("config:read"), t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/generate_pricing", "", Bearer(token)).Code, http.StatusOK, t)
+  })
+  tp.Run("TestProbesAndMetricsStayOpen", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/healthz", "", nil).Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/metrics", "", nil).Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/openapi.json", "", nil).Code, http.StatusOK, t)
+  })
+}
+
+func TestRepricingJobsBelongToTheirSubmitter(tp *testing.T){
+  options, _ := WriteCredentials(tp)
+  authenticator, err := auth.New(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  pricingApp := &app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: "../test_configs",
+      },
+    },
+  }
+  manager := jobs.Manager{App: pricingApp, Store: &jobs.Store{Dir: tp.TempDir()}}
+  manager.Start()
+  defer manager.Stop()
+  router := service.NewRouter(&rpc.RPC{App: pricingApp, Jobs: &manager, Auth: authenticator}, settings.Default())
+
+  recorder := Serve(router, http.MethodPost, "/jobs", `{"requests":[{"date_of_birth":"1990-01-01","insurance_group":7,"license_held_since":"2010-01-01"}]}`, Bearer("broker-key"))
+  util.AssertEqual(recorder.Code, http.StatusAccepted, tp)
+  job := pricingengine.RepricingJob{}
+  json.Unmarshal(recorder.Body.Bytes(), &job)
+  util.AssertEqual(job.Owner, "broker", tp)
+
+  tp.Run("TestOwnerSeesTheJob", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/jobs/" + job.ID, "", Bearer("broker-key")).Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/jobs/" + job.ID + "/results", "", Bearer("broker-key")).Code, http.StatusOK, t)
+  })
+  tp.Run("TestOtherClientGets404", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/jobs/" + job.ID, "", Bearer("ops-key")).Code, http.StatusNotFound, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/jobs/" + job.ID + "/results", "", Bearer("ops-key")).Code, http.StatusNotFound, t)
+    util.AssertEqual(Serve(router, http.MethodPost, "/jobs/" + job.ID + "/cancel", "", Bearer("ops-key")).Code, http.StatusNotFound, t)
+  })
+}
+
+func TestAuthenticatedGRPCServer(tp *testing.T){
+  options, _ := WriteCredentials(tp)
+  authenticator, err := auth.New(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  listener := bufconn.Listen(1024 * 1024)
+  server := grpcapi.Server{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+    Auth: authenticator,
+  }
+  go server.Serve(listener)
+  defer server.Stop()
+
+  conn, err := grpc.NewClient("passthrough:///bufnet",
+    grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+      return listener.DialContext(ctx)
+    }),
+    grpc.WithTransportCredentials(insecure.NewCredentials()))
+  if err != nil {
+    tp.Fatal(err)
+  }
+  defer conn.Close()
+  client := pricingpb.NewPricingEngineClient(conn)
+  request := &pricingpb.GeneratePricingRequest{DateOfBirth: "1990-01-01", InsuranceGroup: 7, LicenseHeldSince: "2010-01-01"}
+
+  tp.Run("TestGRPCCallWithoutCredentialsIsUnauthenticated", func(t *testing.T) {
+    _, err := client.GeneratePricing(context.Background(), request)
+    util.AssertEqual(status.Code(err), codes.Unauthenticated, t)
+  })
+  tp.Run("TestGRPCCallWithAPIKey", func(t *testing.T) {
+    ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "broker-key")
+    resp, err := client.GeneratePricing(ctx, request)
+    util.AssertTrue(err == nil, t)
+    util.AssertTrue(resp.IsEligible, t)
+  })
+  tp.Run("TestGRPCCallMissingScopeIsDenied", func(t *testing.T) {
+    ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer broker-key")
+    _, err := client.GetPricingConfig(ctx, &pricingpb.GetPricingConfigRequest{})
+    util.AssertEqual(status.Code(err), codes.PermissionDenied, t)
+  })
+}
+
+func TestServiceLoadsCredentialsFromSettings(tp *testing.T){
+  options, _ := WriteCredentials(tp)
+  config := settings.Default()
+  config.ConfigDir = "../test_configs"
+  config.Features.Jobs = false
+  config.Auth.APIKeysFile = filepath.Join(tp.TempDir(), "missing.json")
+
+  tp.Run("TestServiceRefusesToStartWithUnreadableCredentials", func(t *testing.T) {
+    listener, _ := net.Listen("tcp", "127.0.0.1:0")
+    s := service.Service{Settings: config}
+    util.AssertTrue(s.Serve(context.Background(), listener) != nil, t)
+  })
+  tp.Run("TestSettingsEnableAuthentication", func(t *testing.T) {
+    util.AssertFalse(settings.Default().Auth.Enabled(), t)
+    config.Auth.APIKeysFile = options.APIKeysFile
+    util.AssertTrue(config.Auth.Enabled(), t)
+    _, err := auth.New(config.Auth.Options())
+    util.AssertTrue(err == nil, t)
+    var body bytes.Buffer
+    json.NewEncoder(&body).Encode(config.Auth)
+    util.AssertTrue(strings.Contains(body.String(), `"api_keys_file"`), t)
+  })
+}
@@ -0,0 +1,352 @@
+package certs
+
+import (
+  "context"
+  "crypto/ecdsa"
+  "crypto/elliptic"
+  "crypto/rand"
+  "crypto/tls"
+  "crypto/x509"
+  "crypto/x509/pkix"
+  "encoding/pem"
+  "io/ioutil"
+  "math/big"
+  "net"
+  "net/http"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/auth"
+  "pricingengine/service/certs"
+  "pricingengine/service/config"
+  "pricingengine/service/grpcapi"
+  "pricingengine/service/grpcapi/pricingpb"
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials"
+  "google.golang.org/grpc/status"
+)
+
+
+const apiKeys = `{
+  "keys": [{"client_id": "ops", "key": "ops-key", "scopes": ["pricing:quote", "config:read", "config:admin"]}],
+  "certificates": [{"client_id": "broker-gateway", "subject": "broker.example", "scopes": ["pricing:quote", "config:read"]}]
+}`
+
+// Authority is a test CA issuing the server and client certificates
+type Authority struct {
+  Certificate *x509.Certificate
+  Key *ecdsa.PrivateKey
+}
+
+func NewAuthority(name string, t *testing.T) *Authority {
+  key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+  template := &x509.Certificate{
+    SerialNumber: big.NewInt(1),
+    Subject: pkix.Name{CommonName: name},
+    NotBefore: time.Now().Add(-time.Hour),
+    NotAfter: time.Now().Add(time.Hour),
+    IsCA: true,
+    BasicConstraintsValid: true,
+    KeyUsage: x509.KeyUsageCertSign,
+  }
+  der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+  if err != nil {
+    t.Fatal(err)
+  }
+  certificate, _ := x509.ParseCertificate(der)
+  return &Authority{Certificate: certificate, Key: key}
+}
+
+// Issue returns the PEM certificate and key of a server certificate for localhost, or of a client certificate
+func (a *Authority) Issue(commonName string, client bool, t *testing.T) ([]byte, []byte) {
+  key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+  serial, _ := rand.Int(rand.Reader, big.NewInt(1 << 62))
+  template := &x509.Certificate{
+    SerialNumber: serial,
+    Subject: pkix.Name{CommonName: commonName},
+    NotBefore: time.Now().Add(-time.Hour),
+    NotAfter: time.Now().Add(time.Hour),
+    KeyUsage: x509.KeyUsageDigitalSignature,
+    ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+    DNSNames: []string{"localhost"},
+    IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+  }
+  if client {
+    template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+    template.DNSNames, template.IPAddresses = nil, nil
+  }
+  der, err := x509.CreateCertificate(rand.Reader, template, a.Certificate, &key.PublicKey, a.Key)
+  if err != nil {
+    t.Fatal(err)
+  }
+  keyDER, _ := x509.MarshalECPrivateKey(key)
+  return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (a *Authority) PEM() []byte {
+  return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.Certificate.Raw})
+}
+
+func (a *Authority) ClientCertificate(commonName string, t *testing.T) tls.Certificate {
+  certPEM, keyPEM := a.Issue(commonName, true, t)
+  certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+  if err != nil {
+    t.Fatal(err)
+  }
+  return certificate
+}
+
+func WriteFile(filename string, content []byte, t *testing.T) string {
+  if err := ioutil.WriteFile(filename, content, 0600); err != nil {
+    t.Fatal(err)
+  }
+  // make the change visible to the reloader whatever the resolution of the file system clock
+  later := time.Now().Add(time.Second)
+  os.Chtimes(filename, later, later)
+  return filename
+}
+
+// WriteServerFiles writes a server certificate of the authority, along with the authority as client CA bundle
+func WriteServerFiles(dir string, ca *Authority, commonName string, t *testing.T) certs.Options {
+  certPEM, keyPEM := ca.Issue(commonName, false, t)
+  return certs.Options{
+    CertFile: WriteFile(filepath.Join(dir, "server.pem"), certPEM, t),
+    KeyFile: WriteFile(filepath.Join(dir, "server.key"), keyPEM, t),
+    ClientCAFile: WriteFile(filepath.Join(dir, "client-ca.pem"), ca.PEM(), t),
+  }
+}
+
+func NewClient(ca *Authority, certificates ...tls.Certificate) *http.Client {
+  roots := x509.NewCertPool()
+  roots.AddCert(ca.Certificate)
+  return &http.Client{Transport: &http.Transport{
+    TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates},
+    DisableKeepAlives: true,
+  }}
+}
+
+// StartTLSService serves the service with the reloader on a random port until the returned function is called
+func StartTLSService(reloader *certs.Reloader, authenticator *auth.Authenticator, t *testing.T) (string, func()) {
+  configured := settings.Default()
+  configured.ConfigDir = "../test_configs"
+  configured.Features.Jobs = false
+  listener, err := net.Listen("tcp", "127.0.0.1:0")
+  if err != nil {
+    t.Fatal(err)
+  }
+  s := &service.Service{
+    App: &app.App{Cache: config.ConfigCache{TimeToLive: 1, Fetcher: config.ConfigFetcher{Path: "../test_configs"}}},
+    Auth: authenticator,
+    TLS: reloader,
+    Settings: configured,
+  }
+  ctx, cancel := context.WithCancel(context.Background())
+  served := make(chan error, 1)
+  go func() {
+    served <- s.Serve(ctx, listener)
+  }()
+  return "https://" + listener.Addr().String(), func() {
+    cancel()
+    <-served
+  }
+}
+
+func Get(client *http.Client, url string, header http.Header) (*http.Response, error) {
+  req, _ := http.NewRequest("GET", url, nil)
+  for name := range header {
+    req.Header.Set(name, header.Get(name))
+  }
+  resp, err := client.Do(req)
+  if err == nil {
+    resp.Body.Close()
+  }
+  return resp, err
+}
+
+func TestTLS(tp *testing.T){
+  ca := NewAuthority("Test CA", tp)
+  options := WriteServerFiles(tp.TempDir(), ca, "pricing-engine", tp)
+  options.ClientAuth = certs.ClientAuthOptional
+  reloader, err := certs.NewReloader(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  authenticator, err := auth.New(auth.Options{APIKeysFile: WriteFile(filepath.Join(tp.TempDir(), "api-keys.json"), []byte(apiKeys), tp)})
+  if err != nil {
+    tp.Fatal(err)
+  }
+  url, stop := StartTLSService(reloader, authenticator, tp)
+  defer stop()
+
+  tp.Run("TestServesTheCertificate", func(t *testing.T) {
+    resp, err := Get(NewClient(ca), url + "/healthz", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 200, t)
+    util.AssertEqual(resp.TLS.PeerCertificates[0].Subject.CommonName, "pricing-engine", t)
+    resp, err = http.Get(strings.Replace(url, "https", "http", 1) + "/healthz")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 400, t)
+  })
+  tp.Run("TestClientCertificateIdentityGrantsItsScopes", func(t *testing.T) {
+    client := NewClient(ca, ca.ClientCertificate("broker.example", t))
+    resp, err := Get(client, url + "/generate_pricing", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 200, t)
+    resp, err = Get(client, url + "/admin/config/versions", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 403, t)
+  })
+  tp.Run("TestUnmappedClientCertificateFallsBackOnCredentials", func(t *testing.T) {
+    client := NewClient(ca, ca.ClientCertificate("unknown.example", t))
+    resp, err := Get(client, url + "/generate_pricing", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 401, t)
+    header := http.Header{}
+    header.Set(auth.APIKeyHeader, "ops-key")
+    resp, err = Get(client, url + "/generate_pricing", header)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 200, t)
+  })
+  tp.Run("TestClientCertificateOfAnotherCAIsNotAnIdentity", func(t *testing.T) {
+    other := NewAuthority("Other CA", t)
+    resp, err := Get(NewClient(ca, other.ClientCertificate("broker.example", t)), url + "/generate_pricing", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 401, t)
+  })
+}
+
+func TestRequiredClientCertificate(tp *testing.T){
+  ca := NewAuthority("Test CA", tp)
+  options := WriteServerFiles(tp.TempDir(), ca, "pricing-engine", tp)
+  options.ClientAuth = certs.ClientAuthRequire
+  reloader, err := certs.NewReloader(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  url, stop := StartTLSService(reloader, nil, tp)
+  defer stop()
+
+  tp.Run("TestHandshakeFailsWithoutCertificate", func(t *testing.T) {
+    _, err := Get(NewClient(ca), url + "/healthz", nil)
+    util.AssertTrue(err != nil, t)
+  })
+  tp.Run("TestHandshakeFailsWithCertificateOfAnotherCA", func(t *testing.T) {
+    other := NewAuthority("Other CA", t)
+    _, err := Get(NewClient(ca, other.ClientCertificate("anyone.example", t)), url + "/healthz", nil)
+    util.AssertTrue(err != nil, t)
+  })
+  tp.Run("TestHandshakeSucceedsWithCertificate", func(t *testing.T) {
+    resp, err := Get(NewClient(ca, ca.ClientCertificate("anyone.example", t)), url + "/healthz", nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 200, t)
+  })
+}
+
+func TestCertificateReload(tp *testing.T){
+  ca := NewAuthority("Test CA", tp)
+  dir := tp.TempDir()
+  reloader, err := certs.NewReloader(WriteServerFiles(dir, ca, "first", tp))
+  if err != nil {
+    tp.Fatal(err)
+  }
+  url, stop := StartTLSService(reloader, nil, tp)
+  defer stop()
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+  go reloader.Watch(ctx, 10 * time.Millisecond)
+
+  servedCommonName := func(t *testing.T) string {
+    resp, err := Get(NewClient(ca), url + "/healthz", nil)
+    if err != nil {
+      t.Fatal(err)
+    }
+    return resp.TLS.PeerCertificates[0].Subject.CommonName
+  }
+  waitFor := func(commonName string, t *testing.T) {
+    for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+      if reloader.Certificate().Subject.CommonName == commonName {
+        return
+      }
+    }
+    t.Fatal("certificate " + commonName + " was not reloaded")
+  }
+
+  tp.Run("TestRenewedCertificateIsServed", func(t *testing.T) {
+    util.AssertEqual(servedCommonName(t), "first", t)
+    WriteServerFiles(dir, ca, "second", t)
+    waitFor("second", t)
+    util.AssertEqual(servedCommonName(t), "second", t)
+  })
+  tp.Run("TestInvalidFilesKeepThePreviousCertificate", func(t *testing.T) {
+    WriteFile(filepath.Join(dir, "server.pem"), []byte("half written"), t)
+    util.AssertTrue(reloader.Reload() != nil, t)
+    time.Sleep(50 * time.Millisecond)
+    util.AssertEqual(servedCommonName(t), "second", t)
+
+    WriteServerFiles(dir, ca, "third", t)
+    waitFor("third", t)
+    util.AssertEqual(servedCommonName(t), "third", t)
+  })
+  tp.Run("TestMissingFiles", func(t *testing.T) {
+    _, err := certs.NewReloader(certs.Options{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: filepath.Join(dir, "server.key")})
+    util.AssertTrue(err != nil, t)
+    _, err = certs.NewReloader(certs.Options{CertFile: filepath.Join(dir, "server.pem"), KeyFile: filepath.Join(dir, "server.key"), ClientCAFile: filepath.Join(dir, "server.key")})
+    util.AssertTrue(err != nil && strings.Contains(err.Error(), "no CA certificate"), t)
+  })
+}
+
+func TestGRPCClientCertificate(tp *testing.T){
+  ca := NewAuthority("Test CA", tp)
+  options := WriteServerFiles(tp.TempDir(), ca, "pricing-engine", tp)
+  options.ClientAuth = certs.ClientAuthRequire
+  reloader, err := certs.NewReloader(options)
+  if err != nil {
+    tp.Fatal(err)
+  }
+  authenticator, err := auth.New(auth.Options{APIKeysFile: WriteFile(filepath.Join(tp.TempDir(), "api-keys.json"), []byte(apiKeys), tp)})
+  if err != nil {
+    tp.Fatal(err)
+  }
+  server := grpcapi.NewServer(&app.App{Cache: config.ConfigCache{TimeToLive: 1, Fetcher: config.ConfigFetcher{Path: "../test_configs"}}}, grpc.Creds(credentials.NewTLS(reloader.Config())))
+  server.Auth = authenticator
+  listener, err := net.Listen("tcp", "127.0.0.1:0")
+  if err != nil {
+    tp.Fatal(err)
+  }
+  go server.Serve(listener)
+  defer server.Stop()
+
+  dial := func(commonName string, t *testing.T) pricingpb.PricingEngineClient {
+    roots := x509.NewCertPool()
+    roots.AddCert(ca.Certificate)
+    creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{ca.ClientCertificate(commonName, t)}})
+    conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(creds))
+    if err != nil {
+      t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return pricingpb.NewPricingEngineClient(conn)
+  }
+
+  tp.Run("TestClientCertificateIdentityGrantsItsScopes", func(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
+    defer cancel()
+    _, err := dial("broker.example", t).GetPricingConfig(ctx, &pricingpb.GetPricingConfigRequest{})
+    util.AssertTrue(err == nil, t)
+  })
+  tp.Run("TestUnmappedClientCertificateIsUnauthenticated", func(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
+    defer cancel()
+    _, err := dial("unknown.example", t).GetPricingConfig(ctx, &pricingpb.GetPricingConfigRequest{})
+    util.AssertEqual(status.Code(err), codes.Unauthenticated, t)
+  })
+}
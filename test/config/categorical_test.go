@@ -0,0 +1,42 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestCategoricalFactorValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestCategoricalRejectsUnknownAttribute": `[{"name":"colour","attribute":"colour","entries":[{"value":"red","is-eligible":true,"factor":1.1}]}]`,
+    "TestCategoricalRejectsEmptyEntries": `[{"name":"fuel","attribute":"fuel_type","entries":[]}]`,
+    "TestCategoricalRejectsTwoWaysOfMatching": `[{"name":"fuel","attribute":"fuel_type","entries":[{"value":"diesel","pattern":"die*","is-eligible":true,"factor":1.1}]}]`,
+    "TestCategoricalRejectsNoWayOfMatching": `[{"name":"fuel","attribute":"fuel_type","entries":[{"is-eligible":true,"factor":1.1}]}]`,
+    "TestCategoricalRejectsTwoDefaults": `[{"name":"fuel","attribute":"fuel_type","entries":[{"default":true,"is-eligible":true,"factor":1},{"default":true,"is-eligible":true,"factor":1}]}]`,
+    "TestCategoricalRejectsEmptyValue": `[{"name":"fuel","attribute":"fuel_type","entries":[{"values":["diesel"," "],"is-eligible":true,"factor":1.1}]}]`,
+    "TestCategoricalRejectsZeroEligibleFactor": `[{"name":"fuel","attribute":"fuel_type","entries":[{"value":"diesel","is-eligible":true,"factor":0}]}]`,
+    "TestCategoricalRejectsDuplicateName": `[{"name":"fuel","attribute":"fuel_type","entries":[{"default":true,"is-eligible":true,"factor":1}]},{"name":"fuel","attribute":"occupation","entries":[{"default":true,"is-eligible":true,"factor":1}]}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateCategoricalFile(config.CategoricalFactorFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestCategoricalNormalisesEntries", func(t *testing.T) {
+    tables, err := config.ValidateCategoricalFile(config.CategoricalFactorFile, []byte(`[{"name":"purpose","attribute":"cover_purpose","entries":[
+      {"values":[" Social ","COMMUTING"],"is-eligible":true,"factor":1},
+      {"pattern":"Bus*","is-eligible":true,"factor":1.2},
+      {"default":true,"is-eligible":false,"factor":0,"label":"Unknown purpose"}]}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(tables[0].Entries), 2, t)
+    util.AssertEqual(tables[0].Entries[0].Values, []string{"social", "commuting"}, t)
+    util.AssertEqual(tables[0].Entries[0].Label, "purpose:social|commuting", t)
+    util.AssertEqual(tables[0].Entries[1].Pattern, "bus*", t)
+    util.AssertEqual(tables[0].Default.Label, "Unknown purpose", t)
+  })
+}
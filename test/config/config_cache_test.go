@@ -2,6 +2,9 @@ package config
 
 import (
   "log"
+  "os"
+  "path/filepath"
+  "strings"
   "testing"
   "time"
 
@@ -13,7 +16,7 @@ import (
 
 func TestConfigCacheScenarios(tp *testing.T){
   cache := config.ConfigCache{
-    Fetcher: config.ConfigFetcher {Path: "/../test_configs/"},
+    Fetcher: config.ConfigFetcher {Path: "../test_configs"},
   }
 
   tp.Run("TestConfigCacheInitOnExistingCache", func(t *testing.T) {
@@ -40,7 +43,7 @@ func TestConfigCacheScenarios(tp *testing.T){
     util.AssertEqual(expected, cache.DriverAgeFactorList, t)
     util.AssertEqual(expected, cache.InsuranceGroupFactorList, t)
     util.AssertEqual(expected, cache.LicenceValidityFactorList, t)
-    log.Printf("List : %+v", cache)
+    log.Printf("List : %+v", &cache)
   })
   tp.Run("TestConfigCacheInitWithNoCacheRefreshButNoTTL", func(t *testing.T) {
     cache.TimeToLive = 0
@@ -51,7 +54,7 @@ func TestConfigCacheScenarios(tp *testing.T){
     util.AssertEqual(len(cache.DriverAgeFactorList), 3, t)
     util.AssertEqual(len(cache.InsuranceGroupFactorList),2 , t)
     util.AssertEqual(len(cache.LicenceValidityFactorList),2 , t)
-    log.Printf("List : %+v", cache)
+    log.Printf("List : %+v", &cache)
   })
   tp.Run("TestConfigCacheInitWithCacheRefreshFlag", func(t *testing.T) {
     now := time.Now().Unix()
@@ -69,7 +72,7 @@ func TestConfigCacheScenarios(tp *testing.T){
     util.AssertEqual(len(cache.DriverAgeFactorList), 3, t)
     util.AssertEqual(len(cache.InsuranceGroupFactorList),2 , t)
     util.AssertEqual(len(cache.LicenceValidityFactorList),2 , t)
-    log.Printf("List : %+v", cache)
+    log.Printf("List : %+v", &cache)
   })
   tp.Run("TestConfigCacheInitWithCacheRefreshAfterTimeout", func(t *testing.T) {
     now := time.Now().Unix()
@@ -95,6 +98,34 @@ func TestConfigCacheScenarios(tp *testing.T){
     util.AssertEqual(len(cache.DriverAgeFactorList), 3, t)
     util.AssertEqual(len(cache.InsuranceGroupFactorList),2 , t)
     util.AssertEqual(len(cache.LicenceValidityFactorList),2 , t)
-    log.Printf("List : %+v", cache)
+    log.Printf("List : %+v", &cache)
+  })
+}
+
+func TestConfigCacheStatus(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  cache := config.ConfigCache{
+    Fetcher: config.ConfigFetcher {Path: path},
+  }
+
+  tp.Run("TestConfigCacheStatusBeforeLoad", func(t *testing.T) {
+    status := cache.Status()
+    util.AssertFalse(status.Ready, t)
+    util.AssertEqual(len(status.Files), 0, t)
+  })
+  tp.Run("TestConfigCacheStatusAfterLoad", func(t *testing.T) {
+    util.AssertEqual(cache.Initialise(1000), nil, t)
+    status := cache.Status()
+    util.AssertTrue(status.Ready, t)
+    util.AssertEqual(status.LastReloadError, "", t)
+    util.AssertEqual(len(status.Files), 4, t)
+  })
+  tp.Run("TestConfigCacheStatusKeepsReadyAfterFailedReload", func(t *testing.T) {
+    os.Remove(filepath.Join(path, config.DriverAgeFactorFile))
+    util.AssertTrue(cache.Initialise(1000) != nil, t)
+    status := cache.Status()
+    util.AssertTrue(status.Ready, t)
+    util.AssertTrue(strings.Contains(status.LastReloadError, config.DriverAgeFactorFile), t)
+    util.AssertEqual(len(status.Files), 4, t)
   })
 }
@@ -0,0 +1,100 @@
+package config
+
+import (
+  "context"
+  "os"
+  "path/filepath"
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestConfigStoreVersioning(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  cache := config.ConfigCache{
+    Fetcher: config.ConfigFetcher {Path: path},
+  }
+  cache.Initialise(1000)
+
+  tp.Run("TestConfigStoreListsBaseVersionWhenNothingUploaded", func(t *testing.T) {
+    versions, err := cache.ListVersions()
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(versions), 1, t)
+    util.AssertEqual(versions[0].Version, 0, t)
+    util.AssertEqual(versions[0].Files, config.FactorFiles, t)
+    util.AssertTrue(versions[0].Active, t)
+    util.AssertEqual(cache.Snapshot().Version, 0, t)
+  })
+  tp.Run("TestConfigStoreRejectsInvalidUpload", func(t *testing.T) {
+    _, err := cache.CreateVersion(context.Background(), map[string][]byte{
+      config.BaseRateFile: []byte(`[{"time":1800,"label":"0.5 hours","rate":273,"currency":"GBP"}]`),
+    }, "unknown field")
+    _, ok := err.(*config.ValidationError)
+    util.AssertTrue(ok, t)
+
+    _, err = cache.CreateVersion(context.Background(), map[string][]byte{
+      config.InsuranceGroupFactorFile: []byte(`[{"group":"one-8","is-eligible":true,"factor":1}]`),
+    }, "bad band")
+    _, ok = err.(*config.ValidationError)
+    util.AssertTrue(ok, t)
+
+    _, err = cache.CreateVersion(context.Background(), map[string][]byte{
+      config.BaseRateFile: []byte(`[]`),
+    }, "empty table")
+    _, ok = err.(*config.ValidationError)
+    util.AssertTrue(ok, t)
+
+    versions, _ := cache.ListVersions()
+    util.AssertEqual(len(versions), 1, t)
+  })
+  tp.Run("TestConfigStoreCreatesAndActivatesNewVersion", func(t *testing.T) {
+    version, err := cache.CreateVersion(context.Background(), map[string][]byte{
+      config.BaseRateFile: []byte(`[{"time":1800,"label":"0.5 hours","rate":300}]`),
+    }, "raise the base rate")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(version.Version, 1, t)
+    util.AssertEqual(version.Parent, 0, t)
+    util.AssertEqual(version.Files, []string{config.BaseRateFile}, t)
+    util.AssertTrue(version.Active, t)
+
+    snapshot := cache.Snapshot()
+    util.AssertEqual(snapshot.Version, 1, t)
+    util.AssertEqual(len(snapshot.BaseRateList), 1, t)
+    util.AssertEqual(snapshot.BaseRateList[0].Value, float64(300), t)
+    // untouched tables are carried over from the previous version
+    util.AssertEqual(len(snapshot.DriverAgeFactorList), 3, t)
+  })
+  tp.Run("TestConfigStoreRollsBackToPriorVersion", func(t *testing.T) {
+    err := cache.ActivateVersion(context.Background(), 0)
+    util.AssertTrue(err == nil, t)
+    snapshot := cache.Snapshot()
+    util.AssertEqual(snapshot.Version, 0, t)
+    util.AssertEqual(len(snapshot.BaseRateList), 2, t)
+
+    // a reload after the TTL keeps serving the activated version
+    cache.InitialiseWithRefresh(true, 1000)
+    util.AssertEqual(cache.Snapshot().Version, 0, t)
+
+    versions, _ := cache.ListVersions()
+    util.AssertEqual(len(versions), 2, t)
+    util.AssertTrue(versions[0].Active, t)
+    util.AssertFalse(versions[1].Active, t)
+  })
+  tp.Run("TestConfigStoreActivateUnknownVersion", func(t *testing.T) {
+    err := cache.ActivateVersion(context.Background(), 42)
+    util.AssertEqual(err, config.ErrVersionNotFound, t)
+    util.AssertEqual(cache.Snapshot().Version, 0, t)
+  })
+}
+
+func TestConfigStoreListsOptionalBaseFiles(t *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", t)
+  os.WriteFile(filepath.Join(path, config.PostcodeFactorFile), []byte(`[]`), 0644)
+  store := config.ConfigStore{Path: path}
+
+  versions, err := store.ListVersions()
+  util.AssertTrue(err == nil, t)
+  util.AssertEqual(versions[0].Files, append(append([]string{}, config.FactorFiles...), config.PostcodeFactorFile), t)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+  "context"
   "log"
   "testing"
   "strings"
@@ -11,10 +12,10 @@ import (
 
 
 func TestConfigFetch(tp *testing.T){
-  fetcher := config.ConfigFetcher {Path: "/../test_configs/"}
+  fetcher := config.ConfigFetcher {Path: "../test_configs"}
   var temp []map[string]interface{}
   tp.Run("TestConfigFetchSuccess", func(t *testing.T) {
-    res, err := fetcher.ReadFileAndGetAsObject("base-rate.json" , temp)
+    res, err := fetcher.ReadFileAndGetAsObject(context.Background(), "base-rate.json" , temp)
     if err != nil {
       log.Println("error reading the config file:", err)
       t.Errorf("got error: %q", err)
@@ -33,7 +34,7 @@ func TestConfigFetch(tp *testing.T){
     log.Printf("List : %+v", result)
   })
   tp.Run("TestConfigFetchFileNotFoundError", func(t *testing.T) {
-     _,err := fetcher.ReadFileAndGetAsObject("some-base-rate.json" , temp)
+     _,err := fetcher.ReadFileAndGetAsObject(context.Background(), "some-base-rate.json" , temp)
     log.Println("Getting result:", err)
     if err == nil || strings.Contains(err.Error(), "error opening file: open"){
       log.Println("Should not come here!")
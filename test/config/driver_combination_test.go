@@ -0,0 +1,35 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/service/model"
+  "pricingengine/test/util"
+  )
+
+
+func TestDriverCombinationValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestDriverCombinationRejectsUnknownRule": `{"rule":"youngest"}`,
+    "TestDriverCombinationRejectsWeightOverOne": `{"rule":"weighted-average","main-weight":1.5}`,
+    "TestDriverCombinationRejectsNegativeWeight": `{"rule":"weighted-average","main-weight":-0.1}`,
+    "TestDriverCombinationRejectsMissingLoading": `{"rule":"main-plus-loading"}`,
+    "TestDriverCombinationRejectsZeroLoading": `{"rule":"main-plus-loading","loading":{"factor":0}}`,
+    "TestDriverCombinationRejectsUnknownField": `{"rule":"highest-risk","max-drivers":4}`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateDriverCombinationFile(config.DriverCombinationFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestDriverCombinationNormalised", func(t *testing.T) {
+    combination, err := config.ValidateDriverCombinationFile(config.DriverCombinationFile, []byte(`{"rule":"Main-Plus-Loading","loading":{"factor":1.15}}`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(*combination.Loading, models.DriverLoading{Factor: 1.15, Label: "Additional driver"}, t)
+    util.AssertEqual(combination.Rule, "main-plus-loading", t)
+  })
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestHistoryLoadingsValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestHistoryRejectsMissingLookback": `{"claims":{"types":[]},"convictions":{"lookback-years":5,"codes":[]}}`,
+    "TestHistoryRejectsCapBelowOne": `{"claims":{"lookback-years":5,"max-loading":0.5,"types":[]},"convictions":{"lookback-years":5,"codes":[]}}`,
+    "TestHistoryRejectsDiscountLoading": `{"claims":{"lookback-years":5,"types":[{"type":"accident","fault-factor":0.9,"non-fault-factor":1}]},"convictions":{"lookback-years":5,"codes":[]}}`,
+    "TestHistoryRejectsRepeatedClaimType": `{"claims":{"lookback-years":5,"types":[{"type":"Accident","fault-factor":1.2,"non-fault-factor":1},{"type":"accident","fault-factor":1.2,"non-fault-factor":1}]},"convictions":{"lookback-years":5,"codes":[]}}`,
+    "TestHistoryRejectsRepeatedConvictionCode": `{"claims":{"lookback-years":5,"types":[]},"convictions":{"lookback-years":5,"codes":[{"code":"sp30","factor":1.1},{"code":"SP30","factor":1.2}]}}`,
+    "TestHistoryRejectsUnknownField": `{"claims":{"lookback-years":5,"types":[]},"convictions":{"lookback-years":5,"codes":[]},"points":3}`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateHistoryLoadingsFile(config.HistoryLoadingsFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestHistoryDeclineNeedsNoLoading", func(t *testing.T) {
+    loadings, err := config.ValidateHistoryLoadingsFile(config.HistoryLoadingsFile, []byte(`{"claims":{"lookback-years":5,"types":[]},"convictions":{"lookback-years":5,"codes":[{"code":"dr10","decline":true}]}}`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(loadings.Convictions.Codes[0].Code, "DR10", t)
+  })
+}
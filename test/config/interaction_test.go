@@ -0,0 +1,75 @@
+package config
+
+import (
+  "context"
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestInteractionFactorValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestInteractionRejectsUnknownDimension": `[{"name":"age-group","dimensions":["driver_age","postcode"],"cells":[{"bands":["16-26","1-8"],"is-eligible":true,"factor":1.2}]}]`,
+    "TestInteractionRejectsSingleDimension": `[{"name":"age","dimensions":["driver_age"],"cells":[{"bands":["16-26"],"is-eligible":true,"factor":1.2}]}]`,
+    "TestInteractionRejectsRepeatedDimension": `[{"name":"age","dimensions":["driver_age","driver_age"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":1.2}]}]`,
+    "TestInteractionRejectsMissingBand": `[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26"],"is-eligible":true,"factor":1.2}]}]`,
+    "TestInteractionRejectsBadBand": `[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","high"],"is-eligible":true,"factor":1.2}]}]`,
+    "TestInteractionRejectsZeroEligibleFactor": `[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":0}]}]`,
+    "TestInteractionRejectsDuplicateName": `[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":1.1}]},{"name":"age-group","dimensions":["driver_age","licence_validity"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":1.1}]}]`,
+    "TestInteractionRejectsUnknownField": `[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":1.1,"weight":2}]}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateInteractionFile(config.InteractionFactorFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestInteractionConvertsBands", func(t *testing.T) {
+    tables, err := config.ValidateInteractionFile(config.InteractionFactorFile, []byte(`[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","*"],"is-eligible":true,"factor":1.1}]}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(tables), 1, t)
+    util.AssertEqual(tables[0].Cells[0].Start[0], 16, t)
+    util.AssertEqual(tables[0].Cells[0].End[0], 26, t)
+    util.AssertTrue(tables[0].Cells[0].Start[1] < 0, t)
+    util.AssertEqual(tables[0].Cells[0].Label, "age-group:driver_age 16-26, insurance_group *", t)
+  })
+}
+
+func TestInteractionFactorVersioning(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  cache := config.ConfigCache{
+    Fetcher: config.ConfigFetcher {Path: path},
+  }
+  cache.Initialise(1000)
+
+  tp.Run("TestInteractionAbsentByDefault", func(t *testing.T) {
+    util.AssertEqual(len(cache.Snapshot().InteractionFactorList), 0, t)
+    util.AssertEqual(len(cache.Status().Files), len(config.FactorFiles), t)
+  })
+  tp.Run("TestInteractionUploadedAndCarriedOver", func(t *testing.T) {
+    _, err := cache.CreateVersion(context.Background(), map[string][]byte{
+      config.InteractionFactorFile: []byte(`[{"name":"age-group","dimensions":["driver_age","insurance_group"],"cells":[{"bands":["16-26","4-8"],"is-eligible":true,"factor":1.5}]}]`),
+    }, "young drivers in high groups")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(cache.Snapshot().InteractionFactorList), 1, t)
+
+    version, err := cache.CreateVersion(context.Background(), map[string][]byte{
+      config.BaseRateFile: []byte(`[{"time":1800,"label":"0.5 hours","rate":300}]`),
+    }, "raise the base rate")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(version.Files, []string{config.BaseRateFile}, t)
+    util.AssertEqual(len(cache.Snapshot().InteractionFactorList), 1, t)
+    util.AssertEqual(len(cache.Status().Files), len(config.FactorFiles)+1, t)
+  })
+  tp.Run("TestInteractionInvalidUploadRejected", func(t *testing.T) {
+    _, err := cache.CreateVersion(context.Background(), map[string][]byte{
+      config.InteractionFactorFile: []byte(`[{"name":"age-group","dimensions":["driver_age"],"cells":[]}]`),
+    }, "bad interaction")
+    _, ok := err.(*config.ValidationError)
+    util.AssertTrue(ok, t)
+  })
+}
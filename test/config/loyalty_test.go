@@ -0,0 +1,38 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/service/model"
+  "pricingengine/test/util"
+  )
+
+
+func TestLoyaltyFactorValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestLoyaltyRejectsUnknownBasis": `{"basis":"years","bands":[{"from":1,"factor":0.9}]}`,
+    "TestLoyaltyRejectsEmptyBands": `{"basis":"policies","bands":[]}`,
+    "TestLoyaltyRejectsNegativeFrom": `{"basis":"policies","bands":[{"from":-1,"factor":0.9}]}`,
+    "TestLoyaltyRejectsRepeatedFrom": `{"basis":"policies","bands":[{"from":1,"factor":0.9},{"from":1,"factor":0.8}]}`,
+    "TestLoyaltyRejectsZeroFactor": `{"basis":"days","bands":[{"from":365,"factor":0}]}`,
+    "TestLoyaltyRejectsUnknownOperation": `{"basis":"days","bands":[{"from":365,"factor":5,"operation":"divide"}]}`,
+    "TestLoyaltyRejectsUnknownField": `{"basis":"days","bands":[{"from":365,"to":730,"factor":0.9}]}`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateLoyaltyFile(config.LoyaltyFactorFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestLoyaltyNormalised", func(t *testing.T) {
+    loyalty, err := config.ValidateLoyaltyFile(config.LoyaltyFactorFile, []byte(`{"basis":" Policies","bands":[{"from":3,"factor":10,"operation":"percent-discount","label":"Loyal customer"},{"from":1,"factor":0.95}]}`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(*loyalty, models.LoyaltyFactor{Basis: "policies", Bands: []models.LoyaltyBand{
+      {From: 1, Factor: 0.95, Label: "Loyalty:1+ policies"},
+      {From: 3, Factor: 10, Label: "Loyal customer", Operation: "percent-discount"},
+    }}, t)
+  })
+}
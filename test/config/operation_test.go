@@ -0,0 +1,37 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestFactorOperationValidation(tp *testing.T){
+  invalid := map[string][2]string{
+    "TestOperationRejectsUnknownOperation": {config.DriverAgeFactorFile, `[{"label":"Driver Age:16-26","age":26,"is-eligible":true,"factor":1.5,"operation":"divide"}]`},
+    "TestOperationRejectsDiscountOverHundred": {config.InsuranceGroupFactorFile, `[{"label":"group","group":"1-8","is-eligible":true,"factor":120,"operation":"percent-discount"}]`},
+    "TestOperationRejectsZeroOverride": {config.LicenceValidityFactorFile, `[{"length":"0-1","factor":0,"operation":"override"}]`},
+    "TestOperationRejectsNegativeLoading": {config.PostcodeFactorFile, `[{"prefix":"E1","is-eligible":true,"factor":-5,"operation":"add"}]`},
+    "TestOperationRejectsUnknownCellOperation": {config.InteractionFactorFile, `[{"name":"young","dimensions":["driver_age"],"cells":[{"bands":["16-25"],"is-eligible":true,"factor":5,"operation":"plus"}]}]`},
+  }
+  for name, document := range invalid {
+    document := document
+    tp.Run(name, func(t *testing.T) {
+      err := config.ValidateDocument(document[0], []byte(document[1]))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestOperationAllowsZeroLoading", func(t *testing.T) {
+    ranges, err := config.ValidateFactorFile(config.LicenceValidityFactorFile, []byte(`[{"length":"0-1","factor":5,"operation":"add"},{"length":"1-100","factor":0,"operation":"add"}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(ranges[0].Operation, "add", t)
+  })
+  tp.Run("TestOperationCarriedToCategoricalEntries", func(t *testing.T) {
+    tables, err := config.ValidateCategoricalFile(config.CategoricalFactorFile, []byte(`[{"name":"fuel","attribute":"fuel_type","entries":[{"value":"electric","is-eligible":true,"factor":10,"operation":"percent-discount"}]}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(tables[0].Entries[0].Operation, "percent-discount", t)
+  })
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestPostcodeFactorValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestPostcodeRejectsEmptyFactor": `[]`,
+    "TestPostcodeRejectsBadPrefix": `[{"prefix":"1SW","is-eligible":true,"factor":1.1}]`,
+    "TestPostcodeRejectsRepeatedPrefix": `[{"prefix":"sw1a","is-eligible":true,"factor":1.1},{"prefix":"SW1A","is-eligible":true,"factor":1.2}]`,
+    "TestPostcodeRejectsZeroEligibleFactor": `[{"prefix":"SW","is-eligible":true,"factor":0}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidatePostcodeFile(config.PostcodeFactorFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+}
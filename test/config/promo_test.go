@@ -0,0 +1,38 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestPromoCodeValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestPromoRejectsEmptyCode": `[{"code":" ","type":"subtract","value":5}]`,
+    "TestPromoRejectsUnknownType": `[{"code":"SPRING10","type":"multiply","value":0.9}]`,
+    "TestPromoRejectsZeroValue": `[{"code":"SPRING10","type":"subtract","value":0}]`,
+    "TestPromoRejectsDiscountOverHundred": `[{"code":"SPRING10","type":"percent-discount","value":110}]`,
+    "TestPromoRejectsInvalidDate": `[{"code":"SPRING10","type":"subtract","value":5,"valid-to":"31/05/2026"}]`,
+    "TestPromoRejectsInvertedDates": `[{"code":"SPRING10","type":"subtract","value":5,"valid-from":"2026-06-01","valid-to":"2026-05-31"}]`,
+    "TestPromoRejectsNegativeLimit": `[{"code":"SPRING10","type":"subtract","value":5,"max-uses":-1}]`,
+    "TestPromoRejectsRepeatedCode": `[{"code":"spring 10","type":"subtract","value":5},{"code":"SPRING10","type":"subtract","value":6}]`,
+    "TestPromoRejectsUnknownField": `[{"code":"SPRING10","type":"subtract","value":5,"customer":"42"}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidatePromoCodeFile(config.PromoCodeFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestPromoNormalised", func(t *testing.T) {
+    promos, err := config.ValidatePromoCodeFile(config.PromoCodeFile, []byte(`[{"code":"spring 10","type":"percent-discount","value":10,"durations":["0.5  Hours"]}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(promos[0].Code, "SPRING10", t)
+    util.AssertEqual(promos[0].Durations, []string{"0.5 hours"}, t)
+    util.AssertEqual(promos[0].Label, "Promo:SPRING10", t)
+  })
+}
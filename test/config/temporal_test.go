@@ -0,0 +1,57 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestTemporalFactorsValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestTemporalRejectsUnknownTimezone": `{"timezone":"Europe/Atlantis","hours":[],"days":[]}`,
+    "TestTemporalRejectsHourOutOfDay": `{"timezone":"UTC","hours":[{"from":22,"to":25,"is-eligible":true,"factor":1.2}],"days":[]}`,
+    "TestTemporalRejectsEmptyHourBand": `{"timezone":"UTC","hours":[{"from":6,"to":6,"is-eligible":true,"factor":1.2}],"days":[]}`,
+    "TestTemporalRejectsUnknownDay": `{"timezone":"UTC","hours":[],"days":[{"days":["funday"],"is-eligible":true,"factor":1.1}]}`,
+    "TestTemporalRejectsRepeatedDay": `{"timezone":"UTC","hours":[],"days":[{"days":["Friday"],"is-eligible":true,"factor":1.1},{"days":["friday"],"is-eligible":true,"factor":1.2}]}`,
+    "TestTemporalRejectsZeroFactor": `{"timezone":"UTC","hours":[],"days":[],"holiday":{"is-eligible":true,"factor":0}}`,
+    "TestTemporalRejectsUnknownField": `{"timezone":"UTC","hours":[],"days":[],"months":[]}`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateTemporalFile(config.TemporalFactorFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestTemporalNormalised", func(t *testing.T) {
+    factors, err := config.ValidateTemporalFile(config.TemporalFactorFile, []byte(`{"timezone":"Europe/London","hours":[{"from":22,"to":6,"is-eligible":true,"factor":1.2}],"days":[{"days":[" Saturday "],"is-eligible":true,"factor":1.1}]}`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(factors.Hours[0].Label, "Cover start:22-06", t)
+    util.AssertEqual(factors.Days[0].Days, []string{"saturday"}, t)
+    util.AssertEqual(factors.Days[0].Label, "Cover start:saturday", t)
+  })
+}
+
+func TestHolidayValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestHolidayRejectsInvalidDate": `[{"date":"25/12/2026","name":"Christmas Day"}]`,
+    "TestHolidayRejectsRepeatedDate": `[{"date":"2026-12-25","name":"Christmas Day"},{"date":"2026-12-25","name":"Christmas"}]`,
+    "TestHolidayRejectsUnknownField": `[{"date":"2026-12-25","name":"Christmas Day","region":"uk"}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateHolidayFile(config.HolidayFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestHolidayCalendar", func(t *testing.T) {
+    holidays, err := config.ValidateHolidayFile(config.HolidayFile, []byte(`[{"date":"2026-12-25","name":"Christmas Day"},{"date":"2026-12-26","name":"Boxing Day"}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(holidays), 2, t)
+  })
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+  "testing"
+
+  "pricingengine/service/config"
+  "pricingengine/test/util"
+  )
+
+
+func TestVehicleFileValidation(tp *testing.T){
+  invalid := map[string]string{
+    "TestVehicleRejectsEmptyTable": `[]`,
+    "TestVehicleRejectsMissingIdentifier": `[{"insurance-group":7,"value":1000,"registered":"2020-01-01"}]`,
+    "TestVehicleRejectsRepeatedRegistration": `[{"registration":"AB12 CDE","insurance-group":7,"value":1000,"registered":"2020-01-01"},{"registration":"ab12cde","insurance-group":8,"value":1000,"registered":"2020-01-01"}]`,
+    "TestVehicleRejectsMissingGroup": `[{"registration":"AB12 CDE","value":1000,"registered":"2020-01-01"}]`,
+    "TestVehicleRejectsBadDate": `[{"registration":"AB12 CDE","insurance-group":7,"value":1000,"registered":"01/01/2020"}]`,
+    "TestVehicleRejectsUnknownField": `[{"registration":"AB12 CDE","insurance-group":7,"value":1000,"registered":"2020-01-01","colour":"red"}]`,
+  }
+  for name, data := range invalid {
+    data := data
+    tp.Run(name, func(t *testing.T) {
+      _, err := config.ValidateVehicleFile(config.VehicleFile, []byte(data))
+      _, ok := err.(*config.ValidationError)
+      util.AssertTrue(ok, t)
+    })
+  }
+  tp.Run("TestVehicleNormalisesIdentifiers", func(t *testing.T) {
+    vehicles, err := config.ValidateVehicleFile(config.VehicleFile, []byte(`[{"registration":"ab12 cde","abi-code":"32120101","insurance-group":7,"value":1000,"registered":"2020-01-01"}]`))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(vehicles[0].Registration, "AB12CDE", t)
+  })
+}
@@ -0,0 +1,52 @@
+package customers
+
+import (
+  "errors"
+  "testing"
+
+  "pricingengine"
+  "pricingengine/service/customers"
+  "pricingengine/test/util"
+)
+
+
+func TestCustomerHistoryStore(tp *testing.T){
+  tp.Run("TestUnknownCustomerHasAnEmptyHistory", func(t *testing.T) {
+    store := customers.Store{Dir: t.TempDir()}
+    history, err := store.Load("C-1042")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(*history, pricingengine.CustomerHistory{CustomerID: "C-1042", Policies: []pricingengine.CustomerPolicy{}, Claims: []pricingengine.Claim{}}, t)
+  })
+  tp.Run("TestRecordsSurviveARestart", func(t *testing.T) {
+    dir := t.TempDir()
+    first := customers.Store{Dir: dir}
+    first.RecordPolicy("C-1042", pricingengine.CustomerPolicy{BoundOn: "2024-03-01", Reference: "POL-1", Premium: 259.349})
+    history, err := first.RecordClaim("C-1042", pricingengine.Claim{Date: "2024-09-14", Type: "accident", Fault: true})
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(history.Policies), 1, t)
+
+    restarted := customers.Store{Dir: dir}
+    history, err = restarted.Load("C-1042")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(history.Policies, []pricingengine.CustomerPolicy{{BoundOn: "2024-03-01", Reference: "POL-1", Premium: 259.349}}, t)
+    util.AssertEqual(history.Claims, []pricingengine.Claim{{Date: "2024-09-14", Type: "accident", Fault: true}}, t)
+  })
+  tp.Run("TestInvalidCustomerIDIsRejected", func(t *testing.T) {
+    store := customers.Store{Dir: t.TempDir()}
+    _, err := store.Load("../config")
+    util.AssertTrue(errors.Is(err, customers.ErrInvalidCustomerID), t)
+    _, err = store.RecordPolicy("", pricingengine.CustomerPolicy{BoundOn: "2024-03-01"})
+    util.AssertTrue(errors.Is(err, customers.ErrInvalidCustomerID), t)
+  })
+  tp.Run("TestInvalidRecordsAreRejected", func(t *testing.T) {
+    store := customers.Store{Dir: t.TempDir()}
+    _, err := store.RecordPolicy("C-1042", pricingengine.CustomerPolicy{BoundOn: "01/03/2024"})
+    util.AssertTrue(errors.Is(err, customers.ErrInvalidRecord), t)
+    _, err = store.RecordClaim("C-1042", pricingengine.Claim{Date: "2024-09-14"})
+    util.AssertTrue(errors.Is(err, customers.ErrInvalidRecord), t)
+    _, err = store.RecordClaim("C-1042", pricingengine.Claim{Date: "2999-01-01", Type: "theft"})
+    util.AssertTrue(errors.Is(err, customers.ErrInvalidRecord), t)
+    history, _ := store.Load("C-1042")
+    util.AssertEqual(len(history.Policies)+len(history.Claims), 0, t)
+  })
+}
@@ -0,0 +1,151 @@
+package grpcapi
+
+import (
+  "context"
+  "net"
+  "testing"
+  "time"
+
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/grpcapi"
+  "pricingengine/service/grpcapi/pricingpb"
+  "pricingengine/test/util"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials/insecure"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/reflection/grpc_reflection_v1"
+  "google.golang.org/grpc/status"
+  "google.golang.org/grpc/test/bufconn"
+)
+
+
+func TestGRPCServerWithActualConfigs(tp *testing.T){
+  listener := bufconn.Listen(1024 * 1024)
+  server := grpcapi.Server{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+  }
+  go server.Serve(listener)
+  defer server.Stop()
+
+  conn, err := grpc.NewClient("passthrough:///bufnet",
+    grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+      return listener.DialContext(ctx)
+    }),
+    grpc.WithTransportCredentials(insecure.NewCredentials()))
+  if err != nil {
+    tp.Fatal(err)
+  }
+  defer conn.Close()
+  client := pricingpb.NewPricingEngineClient(conn)
+
+  now := time.Now()
+  dob := now.AddDate(-20, 0, 0).Format("2006-01-02") //20 years before now
+  licence := now.AddDate(-7, 0, 0).Format("2006-01-02") //7 years before now
+
+  tp.Run("TestGRPCGeneratePricing-SuccessScenario", func(t *testing.T) {
+    resp, err := client.GeneratePricing(context.Background(), &pricingpb.GeneratePricingRequest{
+      DateOfBirth: dob,
+      InsuranceGroup: 7,
+      LicenseHeldSince: licence,
+    })
+    util.AssertTrue(err == nil, t)
+    util.AssertTrue(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "Success", t)
+    util.AssertEqual(len(resp.Pricing), 2, t)
+    util.AssertEqual(resp.Pricing[0].Premium, 259.349, t)
+    util.AssertEqual(resp.Pricing[0].FareGroup, "0.5 hours, Driver Age:16-26, Insurance Group:1-8, Licence Validity:6", t)
+    util.AssertEqual(resp.Input.DateOfBirth, dob, t)
+  })
+  tp.Run("TestGRPCGeneratePricing-FailureScenario", func(t *testing.T) {
+    resp, err := client.GeneratePricing(context.Background(), &pricingpb.GeneratePricingRequest{})
+    util.AssertTrue(err == nil, t)
+    util.AssertFalse(resp.IsEligible, t)
+    util.AssertEqual(resp.Message, "DateOfBirth cannot be empty", t)
+  })
+  tp.Run("TestGRPCGenerateBatchPricing", func(t *testing.T) {
+    resp, err := client.GenerateBatchPricing(context.Background(), &pricingpb.GenerateBatchPricingRequest{
+      Requests: []*pricingpb.GeneratePricingRequest{
+        &pricingpb.GeneratePricingRequest{DateOfBirth: dob, InsuranceGroup: 7, LicenseHeldSince: licence},
+        &pricingpb.GeneratePricingRequest{DateOfBirth: dob, InsuranceGroup: 20, LicenseHeldSince: licence},
+      },
+    })
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(resp.Responses), 2, t)
+    util.AssertTrue(resp.Responses[0].IsEligible, t)
+    util.AssertFalse(resp.Responses[1].IsEligible, t)
+    util.AssertEqual(resp.Responses[1].Message, "Declined due to :Insurance Group:8", t)
+  })
+  tp.Run("TestGRPCGetPricingConfig", func(t *testing.T) {
+    resp, err := client.GetPricingConfig(context.Background(), &pricingpb.GetPricingConfigRequest{})
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(len(resp.BaseRate), 2, t)
+    util.AssertEqual(len(resp.DriverAgeFactor), 3, t)
+    util.AssertEqual(len(resp.InsuranceGroupFactor), 2, t)
+    util.AssertEqual(len(resp.LicenceValidityFactor), 2, t)
+    util.AssertEqual(resp.BaseRate[0].Label, "0.5 hours", t)
+  })
+  tp.Run("TestGRPCReflectionListsPricingService", func(t *testing.T) {
+    stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+    util.AssertTrue(err == nil, t)
+    stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+      MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+    })
+    resp, err := stream.Recv()
+    util.AssertTrue(err == nil, t)
+    services := []string{}
+    for _, service := range resp.GetListServicesResponse().GetService() {
+      services = append(services, service.Name)
+    }
+    util.AssertTrue(len(services) > 0, t)
+    found := false
+    for _, name := range services {
+      found = found || name == "pricingengine.v1.PricingEngine"
+    }
+    util.AssertTrue(found, t)
+  })
+  tp.Run("TestGRPCEchoesRequestID", func(t *testing.T) {
+    header := metadata.MD{}
+    ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "quote-42")
+    _, err := client.GetPricingConfig(ctx, &pricingpb.GetPricingConfigRequest{}, grpc.Header(&header))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(header.Get("x-request-id"), []string{"quote-42"}, t)
+
+    _, err = client.GetPricingConfig(context.Background(), &pricingpb.GetPricingConfigRequest{}, grpc.Header(&header))
+    util.AssertTrue(err == nil, t)
+    util.AssertTrue(len(header.Get("x-request-id")[0]) > 0, t)
+  })
+}
+
+func TestGRPCServerRecoversFromPanics(tp *testing.T){
+  listener := bufconn.Listen(1024 * 1024)
+  // a server without an App panics on every call
+  server := grpcapi.Server{}
+  go server.Serve(listener)
+  defer server.Stop()
+
+  conn, err := grpc.NewClient("passthrough:///bufnet",
+    grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+      return listener.DialContext(ctx)
+    }),
+    grpc.WithTransportCredentials(insecure.NewCredentials()))
+  if err != nil {
+    tp.Fatal(err)
+  }
+  defer conn.Close()
+  client := pricingpb.NewPricingEngineClient(conn)
+
+  _, err = client.GeneratePricing(context.Background(), &pricingpb.GeneratePricingRequest{})
+  util.AssertEqual(status.Code(err), codes.Internal, tp)
+  _, err = client.GetPricingConfig(context.Background(), &pricingpb.GetPricingConfigRequest{})
+  util.AssertEqual(status.Code(err), codes.Internal, tp)
+}
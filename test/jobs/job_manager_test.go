@@ -0,0 +1,263 @@
+package jobs
+
+import (
+  "bytes"
+  "context"
+  "bufio"
+  "encoding/json"
+  "errors"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/jobs"
+  "pricingengine/test/util"
+)
+
+
+func NewTestManager(dir string, workers int) *jobs.Manager {
+  return &jobs.Manager{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+    Store: &jobs.Store{Dir: dir},
+    Workers: workers,
+  }
+}
+
+func WaitForJob(manager *jobs.Manager, id string, t *testing.T) *pricingengine.RepricingJob {
+  for i := 0; i < 500; i++ {
+    job, _ := manager.Status("", id)
+    if job.Status != jobs.StatusQueued && job.Status != jobs.StatusRunning {
+      return job
+    }
+    time.Sleep(10 * time.Millisecond)
+  }
+  t.Fatalf("job %s did not finish", id)
+  return nil
+}
+
+func ReadResults(manager *jobs.Manager, id string) []pricingengine.GeneratePricingResponse {
+  buffer := bytes.Buffer{}
+  manager.Results("", id, &buffer)
+  results := []pricingengine.GeneratePricingResponse{}
+  scanner := bufio.NewScanner(&buffer)
+  for scanner.Scan() {
+    result := pricingengine.GeneratePricingResponse{}
+    json.Unmarshal(scanner.Bytes(), &result)
+    results = append(results, result)
+  }
+  return results
+}
+
+func TestRepricingJobManager(tp *testing.T){
+  now := time.Now()
+  valid := pricingengine.GeneratePricingRequest{
+    DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+    InsuranceGroup: 7,
+    LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+  }
+  declined := valid
+  declined.InsuranceGroup = 20
+
+  tp.Run("TestRepricingJobCompletesWithProgressCounts", func(t *testing.T) {
+    manager := NewTestManager(t.TempDir(), 2)
+    manager.Start()
+    defer manager.Stop()
+
+    job, err := manager.Submit("", []pricingengine.GeneratePricingRequest{valid, declined, valid}, nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(job.Total, 3, t)
+    job = WaitForJob(manager, job.ID, t)
+    util.AssertEqual(job.Status, jobs.StatusCompleted, t)
+    util.AssertEqual(job.Processed, 3, t)
+    util.AssertEqual(job.Priced, 2, t)
+    util.AssertEqual(job.Declined, 1, t)
+    util.AssertEqual(job.Failed, 0, t)
+
+    results := ReadResults(manager, job.ID)
+    util.AssertEqual(len(results), 3, t)
+    util.AssertTrue(results[0].IsEligible, t)
+    util.AssertEqual(results[1].Message, "Declined due to :Insurance Group:8", t)
+  })
+  tp.Run("TestRepricingJobCountsUndecodableLinesAsFailed", func(t *testing.T) {
+    manager := NewTestManager(t.TempDir(), 1)
+    manager.Start()
+    defer manager.Stop()
+
+    line, _ := json.Marshal(valid)
+    job, err := manager.SubmitFile("", strings.NewReader(string(line) + "\n\nnot json\n"), nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(job.Total, 2, t)
+    job = WaitForJob(manager, job.ID, t)
+    util.AssertEqual(job.Priced, 1, t)
+    util.AssertEqual(job.Failed, 1, t)
+  })
+  tp.Run("TestRepricingJobDecodesLinesStrictly", func(t *testing.T) {
+    manager := NewTestManager(t.TempDir(), 1)
+    manager.Start()
+    defer manager.Stop()
+
+    line, _ := json.Marshal(valid)
+    unknown := strings.Replace(string(line), "{", "{\"insurance_grup\":7,", 1)
+    job, err := manager.SubmitFile("", strings.NewReader(string(line) + "\n" + unknown + "\nnull\n" + string(line) + " {}\n"), nil)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(job.Total, 4, t)
+    job = WaitForJob(manager, job.ID, t)
+    util.AssertEqual(job.Priced, 1, t)
+    util.AssertEqual(job.Failed, 3, t)
+
+    results := ReadResults(manager, job.ID)
+    util.AssertEqual(results[1].Message, "Invalid request: invalid request body: json: unknown field \"insurance_grup\"", t)
+    util.AssertEqual(results[2].Message, "Invalid request: request body cannot be null", t)
+    util.AssertTrue(strings.HasPrefix(results[3].Message, "Invalid request: invalid request body: unexpected data after the JSON object"), t)
+  })
+  tp.Run("TestRepricingJobResumesAfterRestart", func(t *testing.T) {
+    dir := t.TempDir()
+    store := jobs.Store{Dir: dir}
+    line, _ := json.Marshal(valid)
+    job := &pricingengine.RepricingJob{ID: "interrupted", Status: jobs.StatusRunning}
+    store.Create(job, strings.NewReader(strings.Repeat(string(line) + "\n", 3)))
+    // one result was checkpointed, a second one was written but never checkpointed
+    job.Processed = 1
+    job.Priced = 1
+    store.Save(job)
+    os.WriteFile(filepath.Join(dir, "interrupted", "results.ndjson"), []byte("{\"message\":\"first\"}\n{\"message\":\"lost\"}\n"), 0644)
+
+    manager := NewTestManager(dir, 1)
+    manager.Start()
+    defer manager.Stop()
+    job = WaitForJob(manager, "interrupted", t)
+    util.AssertEqual(job.Status, jobs.StatusCompleted, t)
+    util.AssertEqual(job.Processed, 3, t)
+    util.AssertEqual(job.Priced, 3, t)
+    results := ReadResults(manager, "interrupted")
+    util.AssertEqual(len(results), 3, t)
+    util.AssertEqual(results[0].Message, "first", t)
+    util.AssertEqual(results[1].Message, "Success", t)
+  })
+  tp.Run("TestRepricingJobIsOnlyVisibleToItsOwner", func(t *testing.T) {
+    dir := t.TempDir()
+    manager := NewTestManager(dir, 1)
+    manager.Start()
+
+    job, _ := manager.Submit("broker", []pricingengine.GeneratePricingRequest{valid}, nil)
+    util.AssertEqual(job.Owner, "broker", t)
+    _, err := manager.Status("partner", job.ID)
+    util.AssertEqual(err, jobs.ErrJobNotFound, t)
+    _, err = manager.Status("", job.ID)
+    util.AssertEqual(err, jobs.ErrJobNotFound, t)
+    util.AssertEqual(manager.Results("partner", job.ID, &bytes.Buffer{}), jobs.ErrJobNotFound, t)
+    _, err = manager.Cancel("partner", job.ID)
+    util.AssertEqual(err, jobs.ErrJobNotFound, t)
+    manager.Stop()
+
+    // the owner is stored with the job and still applies after a restart
+    manager = NewTestManager(dir, 1)
+    manager.Start()
+    defer manager.Stop()
+    _, err = manager.Status("partner", job.ID)
+    util.AssertEqual(err, jobs.ErrJobNotFound, t)
+    job, err = manager.Status("broker", job.ID)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(job.Owner, "broker", t)
+  })
+  tp.Run("TestRepricingJobNotAdmittedIsDropped", func(t *testing.T) {
+    dir := t.TempDir()
+    manager := NewTestManager(dir, 1)
+    manager.Start()
+    defer manager.Stop()
+
+    line, _ := json.Marshal(valid)
+    admitted := 0
+    _, err := manager.SubmitFile("", strings.NewReader(strings.Repeat(string(line) + "\n", 3)), func(total int) error {
+      admitted = total
+      return errors.New("over the limit")
+    })
+    util.AssertEqual(err.Error(), "over the limit", t)
+    util.AssertEqual(admitted, 3, t)
+    util.AssertEqual(manager.QueueDepth(), 0, t)
+    stored, _ := (&jobs.Store{Dir: dir}).LoadAll()
+    util.AssertEqual(len(stored), 0, t)
+  })
+  tp.Run("TestRepricingJobCancel", func(t *testing.T) {
+    manager := NewTestManager(t.TempDir(), 1)
+    manager.Start()
+    defer manager.Stop()
+
+    requests := []pricingengine.GeneratePricingRequest{}
+    for i := 0; i < 500; i++ {
+      requests = append(requests, valid)
+    }
+    first, _ := manager.Submit("", requests, nil)
+    second, _ := manager.Submit("", requests, nil)
+    cancelled, err := manager.Cancel("", second.ID)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(cancelled.Status, jobs.StatusCancelled, t)
+    cancelled = WaitForJob(manager, second.ID, t)
+    util.AssertEqual(cancelled.Status, jobs.StatusCancelled, t)
+    util.AssertTrue(cancelled.Processed < 500, t)
+
+    WaitForJob(manager, first.ID, t)
+    _, err = manager.Cancel("", first.ID)
+    util.AssertEqual(err, jobs.ErrJobFinished, t)
+    _, err = manager.Cancel("", "unknown")
+    util.AssertEqual(err, jobs.ErrJobNotFound, t)
+  })
+  tp.Run("TestRepricingJobShutdownLetsRunningJobFinish", func(t *testing.T) {
+    manager := NewTestManager(t.TempDir(), 1)
+    manager.Start()
+
+    requests := []pricingengine.GeneratePricingRequest{}
+    for i := 0; i < 50; i++ {
+      requests = append(requests, valid)
+    }
+    running, _ := manager.Submit("", requests, nil)
+    queued, _ := manager.Submit("", requests, nil)
+    for job, _ := manager.Status("", running.ID); job.Status == jobs.StatusQueued; job, _ = manager.Status("", running.ID) {
+      time.Sleep(time.Millisecond)
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    util.AssertEqual(manager.Shutdown(ctx), nil, t)
+
+    job, _ := manager.Status("", running.ID)
+    util.AssertEqual(job.Status, jobs.StatusCompleted, t)
+    util.AssertEqual(job.Processed, 50, t)
+    job, _ = manager.Status("", queued.ID)
+    util.AssertEqual(job.Status, jobs.StatusQueued, t)
+  })
+  tp.Run("TestRepricingJobShutdownCheckpointsAfterTimeout", func(t *testing.T) {
+    dir := t.TempDir()
+    manager := NewTestManager(dir, 1)
+    manager.Start()
+
+    requests := []pricingengine.GeneratePricingRequest{}
+    for i := 0; i < 5000; i++ {
+      requests = append(requests, valid)
+    }
+    running, _ := manager.Submit("", requests, nil)
+    for job, _ := manager.Status("", running.ID); job.Processed == 0; job, _ = manager.Status("", running.ID) {
+      time.Sleep(time.Millisecond)
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+    defer cancel()
+    util.AssertEqual(manager.Shutdown(ctx), context.DeadlineExceeded, t)
+
+    job, _ := manager.Status("", running.ID)
+    util.AssertEqual(job.Status, jobs.StatusQueued, t)
+    util.AssertTrue(job.Processed > 0 && job.Processed < 5000, t)
+    stored, _ := (&jobs.Store{Dir: dir}).LoadAll()
+    util.AssertEqual(stored[0].Processed, job.Processed, t)
+  })
+}
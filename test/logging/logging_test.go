@@ -0,0 +1,130 @@
+package logging
+
+import (
+  "bufio"
+  "bytes"
+  "context"
+  "encoding/json"
+  "log/slog"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/logging"
+  "pricingengine/service/metrics"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/tracing"
+  "pricingengine/test/util"
+
+  "github.com/go-chi/chi/middleware"
+)
+
+
+// CaptureLogs installs a JSON logger writing to the returned buffer for the duration of the test
+func CaptureLogs(level string, t *testing.T) *bytes.Buffer {
+  previous := slog.Default()
+  t.Cleanup(func() { slog.SetDefault(previous) })
+  buffer := &bytes.Buffer{}
+  if err := logging.Setup(buffer, level); err != nil {
+    t.Fatal(err)
+  }
+  return buffer
+}
+
+// ReadLogs decodes every JSON log line of the buffer
+func ReadLogs(buffer *bytes.Buffer) []map[string]interface{} {
+  logs := []map[string]interface{}{}
+  scanner := bufio.NewScanner(buffer)
+  for scanner.Scan() {
+    entry := map[string]interface{}{}
+    json.Unmarshal(scanner.Bytes(), &entry)
+    logs = append(logs, entry)
+  }
+  return logs
+}
+
+func TestStructuredLogging(tp *testing.T){
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+  }
+  router := service.NewRouter(&rpc, settings.Default())
+  request, _ := json.Marshal(&pricingengine.GeneratePricingRequest{DateOfBirth: "2001-01-02", InsuranceGroup: 20, LicenseHeldSince: "2006-01-02"})
+
+  tp.Run("TestSetupRejectsUnknownLevel", func(t *testing.T) {
+    util.AssertTrue(logging.Setup(&bytes.Buffer{}, "loud") != nil, t)
+  })
+  tp.Run("TestRequestIDIsEchoedAndPropagated", func(t *testing.T) {
+    logs := CaptureLogs("debug", t)
+    req := httptest.NewRequest(http.MethodPost, "/generate_pricing", bytes.NewReader(request))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set(logging.RequestIDHeader, "quote-42")
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, req)
+    util.AssertEqual(recorder.Header().Get(logging.RequestIDHeader), "quote-42", t)
+
+    messages := map[string]bool{}
+    for _, entry := range ReadLogs(logs) {
+      util.AssertEqual(entry["request_id"], "quote-42", t)
+      messages[entry["msg"].(string)] = true
+    }
+    util.AssertTrue(messages["Entering GeneratePricing"], t) // app
+    util.AssertTrue(messages["Checking the driver factor"], t) // strategy
+    util.AssertTrue(messages["Initialising ConfigCache"], t) // config
+    util.AssertTrue(messages["request served"], t)
+  })
+  tp.Run("TestRequestIDIsGeneratedWhenMissing", func(t *testing.T) {
+    CaptureLogs("info", t)
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+    util.AssertTrue(len(recorder.Header().Get(logging.RequestIDHeader)) > 0, t)
+  })
+  tp.Run("TestDebugLogsAreDroppedAtInfoLevel", func(t *testing.T) {
+    logs := CaptureLogs("info", t)
+    req := httptest.NewRequest(http.MethodPost, "/generate_pricing", bytes.NewReader(request))
+    req.Header.Set("Content-Type", "application/json")
+    router.ServeHTTP(httptest.NewRecorder(), req)
+    entries := ReadLogs(logs)
+    util.AssertTrue(len(entries) > 0, t)
+    for _, entry := range entries {
+      util.AssertTrue(entry["level"] != "DEBUG", t)
+    }
+  })
+  tp.Run("TestMiddlewaresShareOneResponseWriter", func(t *testing.T) {
+    logs := CaptureLogs("info", t)
+    var outer, inner http.ResponseWriter
+    handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      inner = w
+      w.Write([]byte("served"))
+    })
+    wrapped := middleware.RequestID(tracing.Middleware(logging.Middleware(metrics.Middleware(handler))))
+    recorder := httptest.NewRecorder()
+    http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      outer = middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+      wrapped.ServeHTTP(outer, r)
+    }).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+    util.AssertTrue(inner == outer, t)
+    util.AssertEqual(recorder.Body.String(), "served", t)
+    util.AssertEqual(ReadLogs(logs)[0]["bytes"], float64(6), t)
+  })
+  tp.Run("TestContextLoggerCarriesAttributes", func(t *testing.T) {
+    logs := CaptureLogs("info", t)
+    ctx := logging.With(logging.WithRequestID(context.Background(), "abc"), "job_id", "j1")
+    util.AssertEqual(logging.RequestID(ctx), "abc", t)
+    logging.FromContext(ctx).Info("hello")
+    entry := ReadLogs(logs)[0]
+    util.AssertEqual(entry["request_id"], "abc", t)
+    util.AssertEqual(entry["job_id"], "j1", t)
+  })
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+  "context"
+  "io/ioutil"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/metrics"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+
+  "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+
+func TestPricingMetrics(tp *testing.T){
+  pricingApp := &app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: "../test_configs",
+      },
+    },
+  }
+  now := time.Now()
+  valid := pricingengine.GeneratePricingRequest{
+    DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+    InsuranceGroup: 7,
+    LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+  }
+
+  tp.Run("TestQuotesPricedIsCountedByTheApp", func(t *testing.T) {
+    before := testutil.ToFloat64(metrics.QuotesPriced)
+    pricingApp.GeneratePricing(context.Background(), &valid)
+    util.AssertEqual(testutil.ToFloat64(metrics.QuotesPriced), before + 1, t)
+  })
+  tp.Run("TestDeclinesAreCountedByFactorAndBand", func(t *testing.T) {
+    declined := valid
+    declined.InsuranceGroup = 20
+    counter := metrics.QuotesDeclined.WithLabelValues("insurance_group", "Insurance Group:8")
+    before := testutil.ToFloat64(counter)
+    pricingApp.GeneratePricing(context.Background(), &declined)
+    util.AssertEqual(testutil.ToFloat64(counter), before + 1, t)
+  })
+  tp.Run("TestValidationFailuresAreCountedByField", func(t *testing.T) {
+    counter := metrics.ValidationFailures.WithLabelValues("date_of_birth")
+    before := testutil.ToFloat64(counter)
+    pricingApp.GeneratePricing(context.Background(), &pricingengine.GeneratePricingRequest{})
+    invalid := valid
+    invalid.DateOfBirth = "01/02/2000"
+    pricingApp.GeneratePricing(context.Background(), &invalid)
+    util.AssertEqual(testutil.ToFloat64(counter), before + 2, t)
+  })
+}
+
+func TestConfigCacheMetrics(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  cache := config.ConfigCache{
+    Fetcher: config.ConfigFetcher {Path: path},
+  }
+  successes := metrics.ConfigReloads.WithLabelValues(metrics.ReloadSuccess)
+  failures := metrics.ConfigReloads.WithLabelValues(metrics.ReloadFailure)
+
+  tp.Run("TestConfigReloadsAreCounted", func(t *testing.T) {
+    before := testutil.ToFloat64(successes)
+    cache.Initialise(1000)
+    util.AssertEqual(testutil.ToFloat64(successes), before + 1, t)
+    util.AssertEqual(testutil.ToFloat64(metrics.ConfigVersion.WithLabelValues("0")), float64(1), t)
+  })
+  tp.Run("TestConfigReloadFailuresAreCounted", func(t *testing.T) {
+    os.Remove(filepath.Join(path, config.BaseRateFile))
+    before := testutil.ToFloat64(failures)
+    cache.Initialise(1000)
+    util.AssertEqual(testutil.ToFloat64(failures), before + 1, t)
+  })
+}
+
+func TestMetricsEndpoint(tp *testing.T){
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+  }
+  router := service.NewRouter(&rpc, settings.Default())
+
+  tp.Run("TestMetricsEndpointExposesRequestLatencyPerRoute", func(t *testing.T) {
+    router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/generate_pricing", nil))
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    body, _ := ioutil.ReadAll(recorder.Body)
+    util.AssertTrue(strings.Contains(string(body), `pricingengine_request_duration_seconds_count{method="GET",route="/generate_pricing",status="200"}`), t)
+    util.AssertTrue(strings.Contains(string(body), "pricingengine_quotes_priced_total"), t)
+    util.AssertTrue(strings.Contains(string(body), "pricingengine_jobs_queue_depth"), t)
+  })
+}
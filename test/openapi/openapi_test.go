@@ -0,0 +1,208 @@
+package openapi
+
+import (
+  "encoding/json"
+  "net/http"
+  "net/http/httptest"
+  "reflect"
+  "sort"
+  "strings"
+  "testing"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/model"
+  "pricingengine/service/openapi"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+
+  "github.com/go-chi/chi"
+)
+
+
+// documentedTypes maps the component schemas of the spec to the Go types they describe
+var documentedTypes = map[string]reflect.Type{
+  "GeneratePricingRequest": reflect.TypeOf(pricingengine.GeneratePricingRequest{}),
+  "GeneratePricingResponse": reflect.TypeOf(pricingengine.GeneratePricingResponse{}),
+  "PricingItem": reflect.TypeOf(pricingengine.PricingItem{}),
+  "Vehicle": reflect.TypeOf(pricingengine.Vehicle{}),
+  "Promo": reflect.TypeOf(pricingengine.Promo{}),
+  "PromoCode": reflect.TypeOf(models.PromoCode{}),
+  "PricingStep": reflect.TypeOf(pricingengine.PricingStep{}),
+  "Claim": reflect.TypeOf(pricingengine.Claim{}),
+  "Conviction": reflect.TypeOf(pricingengine.Conviction{}),
+  "Driver": reflect.TypeOf(pricingengine.Driver{}),
+  "DriverCombination": reflect.TypeOf(models.DriverCombination{}),
+  "DriverLoading": reflect.TypeOf(models.DriverLoading{}),
+  "CustomerHistory": reflect.TypeOf(pricingengine.CustomerHistory{}),
+  "CustomerPolicy": reflect.TypeOf(pricingengine.CustomerPolicy{}),
+  "LoyaltyFactor": reflect.TypeOf(models.LoyaltyFactor{}),
+  "LoyaltyBand": reflect.TypeOf(models.LoyaltyBand{}),
+  "HistoryLoadings": reflect.TypeOf(models.HistoryLoadings{}),
+  "ClaimLoadings": reflect.TypeOf(models.ClaimLoadings{}),
+  "ClaimLoading": reflect.TypeOf(models.ClaimLoading{}),
+  "ConvictionLoadings": reflect.TypeOf(models.ConvictionLoadings{}),
+  "ConvictionLoading": reflect.TypeOf(models.ConvictionLoading{}),
+  "TemporalFactors": reflect.TypeOf(models.TemporalFactors{}),
+  "HourFactor": reflect.TypeOf(models.HourFactor{}),
+  "DayFactor": reflect.TypeOf(models.DayFactor{}),
+  "HolidayFactor": reflect.TypeOf(models.HolidayFactor{}),
+  "Holiday": reflect.TypeOf(models.Holiday{}),
+  "RangeConfig": reflect.TypeOf(models.RangeConfig{}),
+  "InteractionTable": reflect.TypeOf(models.InteractionTable{}),
+  "InteractionRange": reflect.TypeOf(models.InteractionRange{}),
+  "CategoricalTable": reflect.TypeOf(models.CategoricalTable{}),
+  "PostcodeRange": reflect.TypeOf(models.PostcodeRange{}),
+  "CategoricalRange": reflect.TypeOf(models.CategoricalRange{}),
+  "ConfigVersion": reflect.TypeOf(models.ConfigVersion{}),
+  "ConfigUploadRequest": reflect.TypeOf(pricingengine.ConfigUploadRequest{}),
+  "RepricingJob": reflect.TypeOf(pricingengine.RepricingJob{}),
+  "RepricingJobRequest": reflect.TypeOf(pricingengine.RepricingJobRequest{}),
+  "ConfigStatus": reflect.TypeOf(models.ConfigStatus{}),
+  "ConfigFileStatus": reflect.TypeOf(models.ConfigFileStatus{}),
+  "AuthError": reflect.TypeOf(pricingengine.AuthError{}),
+  "ErrorResponse": reflect.TypeOf(pricingengine.ErrorResponse{}),
+}
+
+// schemaType returns the JSON schema type, or the referenced component, a Go type is encoded as
+func schemaType(t reflect.Type) string {
+  if t == reflect.TypeOf(json.RawMessage{}) {
+    return "array"
+  }
+  switch t.Kind() {
+  case reflect.Ptr:
+    return schemaType(t.Elem())
+  case reflect.String:
+    return "string"
+  case reflect.Bool:
+    return "boolean"
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    return "integer"
+  case reflect.Float32, reflect.Float64:
+    return "number"
+  case reflect.Slice, reflect.Array:
+    return "array"
+  case reflect.Struct:
+    return "#/components/schemas/" + t.Name()
+  }
+  return "object"
+}
+
+// jsonFields returns the encoded JSON name of every field of the struct with its schema type
+func jsonFields(t reflect.Type) map[string]string {
+  result := map[string]string{}
+  for i := 0; i < t.NumField(); i++ {
+    field := t.Field(i)
+    name := strings.Split(field.Tag.Get("json"), ",")[0]
+    if name == "-" || len(field.PkgPath) > 0 {
+      continue
+    }
+    if len(name) == 0 {
+      name = field.Name
+    }
+    result[name] = schemaType(field.Type)
+  }
+  return result
+}
+
+func TestOpenAPISpecMatchesGoTypes(tp *testing.T){
+  spec, err := openapi.Load()
+  if err != nil {
+    tp.Fatal(err)
+  }
+  for name, goType := range documentedTypes {
+    tp.Run("TestOpenAPISchemaMatches-" + name, func(t *testing.T) {
+      schema := spec.Schema(name)
+      properties, _ := schema["properties"].(map[string]interface{})
+      documented := map[string]string{}
+      for property, value := range properties {
+        propertySchema := value.(map[string]interface{})
+        if ref, ok := propertySchema["$ref"].(string); ok {
+          documented[property] = ref
+        } else {
+          documented[property], _ = propertySchema["type"].(string)
+        }
+      }
+      util.AssertEqual(documented, jsonFields(goType), t)
+    })
+  }
+}
+
+func TestOpenAPISpecDocumentsEveryRoute(tp *testing.T){
+  spec, _ := openapi.Load()
+  paths := spec.Document["paths"].(map[string]interface{})
+  routes := []string{}
+  documented := []string{}
+  chi.Walk(service.NewRouter(&rpc.RPC{}, settings.Default()), func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+    route = strings.TrimSuffix(route, "/")
+    routes = append(routes, method + " " + route)
+    if item, ok := paths[route].(map[string]interface{}); ok && item[strings.ToLower(method)] != nil {
+      documented = append(documented, method + " " + route)
+    }
+    return nil
+  })
+  sort.Strings(routes)
+  sort.Strings(documented)
+  util.AssertEqual(documented, routes, tp)
+
+  operations := 0
+  for _, item := range paths {
+    for method := range item.(map[string]interface{}) {
+      if method != "parameters" {
+        operations++
+      }
+    }
+  }
+  util.AssertEqual(operations, len(routes), tp)
+}
+
+func TestOpenAPIValidationMiddleware(tp *testing.T){
+  spec, _ := openapi.Load()
+  handled := false
+  handler := spec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    handled = true
+    w.WriteHeader(http.StatusOK)
+  }))
+  serve := func(method string, target string, body string, contentType string) *httptest.ResponseRecorder {
+    handled = false
+    request := httptest.NewRequest(method, target, strings.NewReader(body))
+    request.Header.Set("Content-Type", contentType)
+    recorder := httptest.NewRecorder()
+    handler.ServeHTTP(recorder, request)
+    return recorder
+  }
+
+  tp.Run("TestOpenAPIValidationAcceptsMatchingBody", func(t *testing.T) {
+    recorder := serve(http.MethodPost, "/generate_pricing", `{"date_of_birth":"1970-12-04","insurance_group":12}`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertTrue(handled, t)
+  })
+  tp.Run("TestOpenAPIValidationRejectsWrongTypesAndUnknownFields", func(t *testing.T) {
+    recorder := serve(http.MethodPost, "/generate_pricing", `{"insurance_group":"12","age":40}`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertFalse(handled, t)
+    util.AssertEqual(recorder.Body.String(), "request body does not match the schema: body.age is not a known field; body.insurance_group should be an integer", t)
+  })
+  tp.Run("TestOpenAPIValidationRejectsNestedProblems", func(t *testing.T) {
+    recorder := serve(http.MethodPost, "/jobs", `{"requests":[{"insurance_group":1.5}]}`, "application/json; charset=utf-8")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertEqual(recorder.Body.String(), "request body does not match the schema: body.requests[0].insurance_group should be an integer", t)
+    recorder = serve(http.MethodPost, "/admin/config/versions", `{"comment":"no files"}`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertEqual(recorder.Body.String(), "request body does not match the schema: body.files is required", t)
+  })
+  tp.Run("TestOpenAPIValidationSkipsOtherContentTypes", func(t *testing.T) {
+    recorder := serve(http.MethodPost, "/generate_pricing/stream", "not json\n", "application/x-ndjson")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertTrue(handled, t)
+  })
+  tp.Run("TestOpenAPIDocumentIsServed", func(t *testing.T) {
+    recorder := httptest.NewRecorder()
+    service.NewRouter(&rpc.RPC{}, settings.Default()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    document := map[string]interface{}{}
+    util.AssertTrue(json.Unmarshal(recorder.Body.Bytes(), &document) == nil, t)
+    util.AssertEqual(document["openapi"], "3.0.3", t)
+  })
+}
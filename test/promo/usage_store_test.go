@@ -0,0 +1,47 @@
+package promo
+
+import (
+  "path/filepath"
+  "testing"
+
+  "pricingengine/service/promo"
+  "pricingengine/test/util"
+)
+
+
+func TestPromoUsageStore(tp *testing.T){
+  tp.Run("TestRedeemUpToTheLimit", func(t *testing.T) {
+    store := promo.UsageStore{}
+    for i := 0; i < 2; i++ {
+      redeemed, err := store.Redeem("SPRING10", 2)
+      util.AssertTrue(err == nil, t)
+      util.AssertTrue(redeemed, t)
+    }
+    redeemed, err := store.Redeem("SPRING10", 2)
+    util.AssertTrue(err == nil, t)
+    util.AssertFalse(redeemed, t)
+    uses, _ := store.Uses("SPRING10")
+    util.AssertEqual(uses, 2, t)
+  })
+  tp.Run("TestRedeemWithoutLimit", func(t *testing.T) {
+    store := promo.UsageStore{}
+    for i := 0; i < 5; i++ {
+      redeemed, _ := store.Redeem("FIVEOFF", 0)
+      util.AssertTrue(redeemed, t)
+    }
+    uses, _ := store.Uses("FIVEOFF")
+    util.AssertEqual(uses, 5, t)
+  })
+  tp.Run("TestUsesSurviveARestart", func(t *testing.T) {
+    file := filepath.Join(t.TempDir(), "promo-usage.json")
+    first := promo.UsageStore{File: file}
+    first.Redeem("LIMITED", 1)
+
+    restarted := promo.UsageStore{File: file}
+    uses, err := restarted.Uses("LIMITED")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(uses, 1, t)
+    redeemed, _ := restarted.Redeem("LIMITED", 1)
+    util.AssertFalse(redeemed, t)
+  })
+}
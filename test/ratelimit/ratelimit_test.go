@@ -0,0 +1,322 @@
+package ratelimit
+
+import (
+  "context"
+  "io/ioutil"
+  "net"
+  "net/http"
+  "net/http/httptest"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/auth"
+  "pricingengine/service/config"
+  "pricingengine/service/grpcapi"
+  "pricingengine/service/grpcapi/pricingpb"
+  "pricingengine/service/jobs"
+  "pricingengine/service/metrics"
+  "pricingengine/service/ratelimit"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+
+  "github.com/prometheus/client_golang/prometheus/testutil"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials/insecure"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "google.golang.org/grpc/test/bufconn"
+)
+
+
+const quote = `{"date_of_birth":"1990-01-01","insurance_group":7,"license_held_since":"2010-01-01"}`
+
+// FakeClock returns a clock standing still until it is advanced
+func FakeClock() (func() time.Time, func(time.Duration)) {
+  now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+  return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func NewLimiter(config ratelimit.Config, t *testing.T) *ratelimit.Limiter {
+  limiter, err := ratelimit.New(config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  return limiter
+}
+
+func NewApp() *app.App {
+  return &app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: "../test_configs",
+      },
+    },
+  }
+}
+
+func Serve(router http.Handler, method string, target string, body string, remoteAddr string, apiKey string) *httptest.ResponseRecorder {
+  return ServeWithContentType(router, method, target, body, "application/json", remoteAddr, apiKey)
+}
+
+func ServeWithContentType(router http.Handler, method string, target string, body string, contentType string, remoteAddr string, apiKey string) *httptest.ResponseRecorder {
+  req := httptest.NewRequest(method, target, strings.NewReader(body))
+  req.Header.Set("Content-Type", contentType)
+  req.RemoteAddr = remoteAddr
+  if len(apiKey) > 0 {
+    req.Header.Set(auth.APIKeyHeader, apiKey)
+  }
+  recorder := httptest.NewRecorder()
+  router.ServeHTTP(recorder, req)
+  return recorder
+}
+
+func TestLimiter(tp *testing.T){
+  tp.Run("TestTokenBucketRefillsAtItsRate", func(t *testing.T) {
+    limiter := NewLimiter(ratelimit.Config{Default: ratelimit.Limits{ratelimit.ClassQuote: {Rate: 2, Burst: 2}}}, t)
+    now, advance := FakeClock()
+    limiter.SetClock(now)
+
+    util.AssertEqual(limiter.Allow("broker", ratelimit.ClassQuote, 1), ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}, t)
+    util.AssertTrue(limiter.Allow("broker", ratelimit.ClassQuote, 1).Allowed, t)
+    util.AssertEqual(limiter.Allow("broker", ratelimit.ClassQuote, 1), ratelimit.Result{Limit: 2, Remaining: 0, Reset: time.Second, RetryAfter: 500 * time.Millisecond}, t)
+    advance(250 * time.Millisecond)
+    util.AssertEqual(limiter.Allow("broker", ratelimit.ClassQuote, 1).RetryAfter, 250 * time.Millisecond, t)
+    advance(250 * time.Millisecond)
+    util.AssertTrue(limiter.Allow("broker", ratelimit.ClassQuote, 1).Allowed, t)
+    advance(time.Hour)
+    util.AssertEqual(limiter.Allow("broker", ratelimit.ClassQuote, 1).Remaining, 1, t)
+  })
+  tp.Run("TestBucketsAreKeptPerClientAndClass", func(t *testing.T) {
+    limiter := NewLimiter(ratelimit.Config{
+      Default: ratelimit.Limits{ratelimit.ClassQuote: {Rate: 1, Burst: 1}, ratelimit.ClassConfigRead: {Rate: 1, Burst: 1}},
+      Clients: map[string]ratelimit.Limits{"partner": {ratelimit.ClassQuote: {Rate: 100, Burst: 50}}},
+    }, t)
+    util.AssertTrue(limiter.Allow("broker", ratelimit.ClassQuote, 1).Allowed, t)
+    util.AssertFalse(limiter.Allow("broker", ratelimit.ClassQuote, 1).Allowed, t)
+    util.AssertTrue(limiter.Allow("broker", ratelimit.ClassConfigRead, 1).Allowed, t)
+    util.AssertTrue(limiter.Allow("ip:10.0.0.1", ratelimit.ClassQuote, 1).Allowed, t)
+    util.AssertEqual(limiter.Allow("partner", ratelimit.ClassQuote, 1).Limit, 50, t)
+    util.AssertTrue(limiter.Allow("partner", ratelimit.ClassConfigRead, 1).Allowed, t)
+    util.AssertFalse(limiter.Allow("partner", ratelimit.ClassConfigRead, 1).Allowed, t)
+  })
+  tp.Run("TestClassesLeftOutAreNotLimited", func(t *testing.T) {
+    limiter := NewLimiter(ratelimit.Config{}, t)
+    result := limiter.Allow("broker", ratelimit.ClassBatchItem, 1000)
+    util.AssertTrue(result.Allowed, t)
+    util.AssertFalse(result.Limited(), t)
+  })
+  tp.Run("TestCostAboveTheBurstIsNeverAllowed", func(t *testing.T) {
+    limiter := NewLimiter(ratelimit.Config{Default: ratelimit.Limits{ratelimit.ClassBatchItem: {Rate: 10, Burst: 5}}}, t)
+    result := limiter.Allow("broker", ratelimit.ClassBatchItem, 6)
+    util.AssertFalse(result.Allowed, t)
+    util.AssertEqual(result.RetryAfter, time.Duration(0), t)
+    util.AssertEqual(result.Remaining, 5, t)
+    err := limiter.Wait(context.Background(), "broker", ratelimit.ClassBatchItem, 6)
+    util.AssertEqual(err.Error(), "6 batch_item tokens exceed the burst of 5", t)
+  })
+  tp.Run("TestWaitStopsWithTheContext", func(t *testing.T) {
+    limiter := NewLimiter(ratelimit.Config{Default: ratelimit.Limits{ratelimit.ClassBatchItem: {Rate: 0.001, Burst: 1}}}, t)
+    util.AssertTrue(limiter.Wait(context.Background(), "broker", ratelimit.ClassBatchItem, 1) == nil, t)
+    ctx, cancel := context.WithTimeout(context.Background(), 20 * time.Millisecond)
+    defer cancel()
+    util.AssertEqual(limiter.Wait(ctx, "broker", ratelimit.ClassBatchItem, 1), context.DeadlineExceeded, t)
+  })
+  tp.Run("TestInvalidConfigs", func(t *testing.T) {
+    _, err := ratelimit.New(ratelimit.Config{
+      Default: ratelimit.Limits{"quotes": {Rate: 1, Burst: 1}},
+      Clients: map[string]ratelimit.Limits{"broker": {ratelimit.ClassQuote: {Rate: 0, Burst: 1}, ratelimit.ClassBatchItem: {Rate: 1}}},
+    })
+    util.AssertTrue(err != nil, t)
+    for _, problem := range []string{"default.quotes: unknown class", "clients.broker.quote.rate: should be positive", "clients.broker.batch_item.burst: should be at least 1"} {
+      util.AssertTrue(strings.Contains(err.Error(), problem), t)
+    }
+  })
+  tp.Run("TestLoadReadsTheJSONFile", func(t *testing.T) {
+    filename := filepath.Join(t.TempDir(), "rate-limits.json")
+    ioutil.WriteFile(filename, []byte(`{"default": {"quote": {"rate": 5, "burst": 10}}, "clients": {"partner": {"batch_item": {"rate": 100, "burst": 500}}}}`), 0644)
+    limiter, err := ratelimit.Load(filename)
+    util.AssertTrue(err == nil, t)
+    limit, ok := limiter.LimitFor("partner", ratelimit.ClassBatchItem)
+    util.AssertTrue(ok, t)
+    util.AssertEqual(limit, ratelimit.Limit{Rate: 100, Burst: 500}, t)
+    limit, _ = limiter.LimitFor("partner", ratelimit.ClassQuote)
+    util.AssertEqual(limit.Burst, 10, t)
+
+    ioutil.WriteFile(filename, []byte(`{"defaults": {}}`), 0644)
+    _, err = ratelimit.Load(filename)
+    util.AssertTrue(err != nil, t)
+  })
+}
+
+func TestRateLimitedEndpoints(tp *testing.T){
+  dir := tp.TempDir()
+  keysFile := filepath.Join(dir, "api-keys.json")
+  ioutil.WriteFile(keysFile, []byte(`{"keys": [
+    {"client_id": "broker", "key": "broker-key", "scopes": ["pricing:quote", "config:read"]},
+    {"client_id": "partner", "key": "partner-key", "scopes": ["pricing:quote"]},
+    {"client_id": "ops", "key": "ops-key", "scopes": ["config:admin"]}
+  ]}`), 0600)
+  authenticator, err := auth.New(auth.Options{APIKeysFile: keysFile})
+  if err != nil {
+    tp.Fatal(err)
+  }
+  limiter := NewLimiter(ratelimit.Config{
+    Default: ratelimit.Limits{
+      ratelimit.ClassQuote: {Rate: 0.001, Burst: 2},
+      ratelimit.ClassConfigRead: {Rate: 0.001, Burst: 1},
+      ratelimit.ClassBatchItem: {Rate: 50, Burst: 1},
+      ratelimit.ClassConfigAdmin: {Rate: 0.001, Burst: 1},
+    },
+    Clients: map[string]ratelimit.Limits{"partner": {ratelimit.ClassQuote: {Rate: 0.001, Burst: 3}}},
+  }, tp)
+  router := service.NewRouter(&rpc.RPC{App: NewApp(), Auth: authenticator, Limiter: limiter}, settings.Default())
+
+  tp.Run("TestQuotesOverTheLimitAreAnswered429", func(t *testing.T) {
+    rejected := metrics.RateLimited.WithLabelValues(ratelimit.ClassQuote)
+    before := testutil.ToFloat64(rejected)
+    recorder := Serve(router, http.MethodPost, "/generate_pricing", quote, "10.0.0.1:5000", "broker-key")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(recorder.Header().Get("RateLimit-Limit"), "2", t)
+    util.AssertEqual(recorder.Header().Get("RateLimit-Remaining"), "1", t)
+    Serve(router, http.MethodPost, "/generate_pricing", quote, "10.0.0.1:5000", "broker-key")
+
+    recorder = Serve(router, http.MethodPost, "/generate_pricing", quote, "10.0.0.1:5000", "broker-key")
+    util.AssertEqual(recorder.Code, http.StatusTooManyRequests, t)
+    util.AssertEqual(recorder.Header().Get("RateLimit-Remaining"), "0", t)
+    util.AssertEqual(recorder.Header().Get("Retry-After"), "1000", t)
+    util.AssertEqual(recorder.Body.String(), "rate limit of quote requests exceeded, retry in 1000s", t)
+    util.AssertEqual(testutil.ToFloat64(rejected), before + 1, t)
+  })
+  tp.Run("TestClientsSharingAnAddressHaveTheirOwnLimits", func(t *testing.T) {
+    recorder := Serve(router, http.MethodPost, "/generate_pricing", quote, "10.0.0.1:5000", "partner-key")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(recorder.Header().Get("RateLimit-Limit"), "3", t)
+  })
+  tp.Run("TestConfigReadsHaveTheirOwnLimit", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/generate_pricing", "", "10.0.0.1:5000", "broker-key").Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/debug/config-status", "", "10.0.0.1:5000", "broker-key").Code, http.StatusTooManyRequests, t)
+  })
+  tp.Run("TestAdminCallsHaveTheirOwnLimit", func(t *testing.T) {
+    util.AssertEqual(Serve(router, http.MethodGet, "/admin/config/versions", "", "10.0.0.6:5000", "ops-key").Code, http.StatusOK, t)
+    util.AssertEqual(Serve(router, http.MethodGet, "/admin/config/versions", "", "10.0.0.6:5000", "ops-key").Code, http.StatusTooManyRequests, t)
+  })
+  tp.Run("TestAnonymousCallersAreLimitedByAddress", func(t *testing.T) {
+    anonymous := service.NewRouter(&rpc.RPC{App: NewApp(), Limiter: limiter}, settings.Default())
+    util.AssertEqual(Serve(anonymous, http.MethodGet, "/generate_pricing", "", "10.0.0.2:5000", "").Code, http.StatusOK, t)
+    util.AssertEqual(Serve(anonymous, http.MethodGet, "/generate_pricing", "", "10.0.0.2:6000", "").Code, http.StatusTooManyRequests, t)
+    util.AssertEqual(Serve(anonymous, http.MethodGet, "/generate_pricing", "", "10.0.0.3:5000", "").Code, http.StatusOK, t)
+  })
+  tp.Run("TestStreamLinesWaitForTheirToken", func(t *testing.T) {
+    start := time.Now()
+    recorder := ServeWithContentType(router, http.MethodPost, "/generate_pricing/stream", quote + "\n" + quote + "\n" + quote + "\n", "application/x-ndjson", "10.0.0.4:5000", "partner-key")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(strings.Count(recorder.Body.String(), "\n"), 3, t)
+    util.AssertTrue(time.Since(start) >= 30 * time.Millisecond, t)
+
+    limiter.Allow("partner", ratelimit.ClassBatchItem, 1)
+    recorder = ServeWithContentType(router, http.MethodPost, "/generate_pricing/stream", quote + "\n", "application/x-ndjson", "10.0.0.4:5000", "partner-key")
+    util.AssertEqual(recorder.Code, http.StatusTooManyRequests, t)
+  })
+  tp.Run("TestProbesAreNotLimited", func(t *testing.T) {
+    for i := 0; i < 5; i++ {
+      recorder := Serve(router, http.MethodGet, "/healthz", "", "10.0.0.1:5000", "")
+      util.AssertEqual(recorder.Code, http.StatusOK, t)
+      util.AssertEqual(recorder.Header().Get("RateLimit-Limit"), "", t)
+    }
+  })
+}
+
+func TestRateLimitedRepricingJobs(tp *testing.T){
+  pricingApp := NewApp()
+  manager := jobs.Manager{App: pricingApp, Store: &jobs.Store{Dir: tp.TempDir()}}
+  manager.Start()
+  defer manager.Stop()
+  limiter := NewLimiter(ratelimit.Config{
+    Default: ratelimit.Limits{
+      ratelimit.ClassQuote: {Rate: 0.001, Burst: 4},
+      ratelimit.ClassBatchItem: {Rate: 0.001, Burst: 2},
+    },
+  }, tp)
+  router := service.NewRouter(&rpc.RPC{App: pricingApp, Jobs: &manager, Limiter: limiter}, settings.Default())
+  submit := func(count int) *httptest.ResponseRecorder {
+    return Serve(router, http.MethodPost, "/jobs", `{"requests":[` + strings.TrimSuffix(strings.Repeat(quote + ",", count), ",") + `]}`, "10.0.0.5:5000", "")
+  }
+
+  tp.Run("TestJobTakesABatchItemTokenPerRequest", func(t *testing.T) {
+    recorder := submit(2)
+    util.AssertEqual(recorder.Code, http.StatusAccepted, t)
+    util.AssertEqual(recorder.Header().Get("RateLimit-Remaining"), "0", t)
+
+    recorder = submit(1)
+    util.AssertEqual(recorder.Code, http.StatusTooManyRequests, t)
+    util.AssertEqual(recorder.Header().Get("Retry-After"), "1000", t)
+  })
+  tp.Run("TestJobOverTheBurstIsTooLarge", func(t *testing.T) {
+    // the job could never be admitted, it is not answered with a 429 to retry
+    recorder := submit(3)
+    util.AssertEqual(recorder.Code, http.StatusRequestEntityTooLarge, t)
+    util.AssertEqual(recorder.Body.String(), "a job of 3 requests is over the batch_item burst of 2, split it into smaller jobs", t)
+    util.AssertEqual(recorder.Header().Get("Retry-After"), "", t)
+
+    stored, _ := manager.Store.LoadAll()
+    recorder = ServeWithContentType(router, http.MethodPost, "/jobs", strings.Repeat(quote + "\n", 3), "application/x-ndjson", "10.0.0.5:5000", "")
+    util.AssertEqual(recorder.Code, http.StatusRequestEntityTooLarge, t)
+    // the stored requests of the job are removed again
+    after, _ := manager.Store.LoadAll()
+    util.AssertEqual(len(after), len(stored), t)
+  })
+  tp.Run("TestJobReadsAreLimited", func(t *testing.T) {
+    // the four submissions above took the four quote tokens
+    util.AssertEqual(Serve(router, http.MethodGet, "/jobs/unknown/results", "", "10.0.0.5:5000", "").Code, http.StatusTooManyRequests, t)
+    util.AssertEqual(Serve(router, http.MethodPost, "/jobs/unknown/cancel", "", "10.0.0.5:5000", "").Code, http.StatusTooManyRequests, t)
+  })
+}
+
+func TestRateLimitedGRPCServer(tp *testing.T){
+  listener := bufconn.Listen(1024 * 1024)
+  server := grpcapi.Server{
+    App: NewApp(),
+    Limiter: NewLimiter(ratelimit.Config{Default: ratelimit.Limits{ratelimit.ClassBatchItem: {Rate: 0.001, Burst: 3}}}, tp),
+  }
+  go server.Serve(listener)
+  defer server.Stop()
+
+  conn, err := grpc.NewClient("passthrough:///bufnet",
+    grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+      return listener.DialContext(ctx)
+    }),
+    grpc.WithTransportCredentials(insecure.NewCredentials()))
+  if err != nil {
+    tp.Fatal(err)
+  }
+  defer conn.Close()
+  client := pricingpb.NewPricingEngineClient(conn)
+  item := &pricingpb.GeneratePricingRequest{DateOfBirth: "1990-01-01", InsuranceGroup: 7, LicenseHeldSince: "2010-01-01"}
+
+  tp.Run("TestBatchTakesATokenPerItem", func(t *testing.T) {
+    var header metadata.MD
+    _, err := client.GenerateBatchPricing(context.Background(), &pricingpb.GenerateBatchPricingRequest{Requests: []*pricingpb.GeneratePricingRequest{item, item}}, grpc.Header(&header))
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(header.Get("ratelimit-remaining"), []string{"1"}, t)
+
+    _, err = client.GenerateBatchPricing(context.Background(), &pricingpb.GenerateBatchPricingRequest{Requests: []*pricingpb.GeneratePricingRequest{item, item}}, grpc.Header(&header))
+    util.AssertEqual(status.Code(err), codes.ResourceExhausted, t)
+    util.AssertEqual(header.Get("retry-after"), []string{"1000"}, t)
+  })
+  tp.Run("TestBatchAboveTheBurstIsRejected", func(t *testing.T) {
+    _, err := client.GenerateBatchPricing(context.Background(), &pricingpb.GenerateBatchPricingRequest{Requests: []*pricingpb.GeneratePricingRequest{item, item, item, item}})
+    util.AssertEqual(status.Code(err), codes.ResourceExhausted, t)
+    util.AssertEqual(status.Convert(err).Message(), "4 batch_item items exceed the burst of 3", t)
+  })
+  tp.Run("TestUnlimitedClassesAreServed", func(t *testing.T) {
+    _, err := client.GeneratePricing(context.Background(), item)
+    util.AssertTrue(err == nil, t)
+  })
+}
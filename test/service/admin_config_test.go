@@ -0,0 +1,101 @@
+package service
+
+import (
+  "testing"
+  "net/http"
+  "net/http/httptest"
+  "encoding/json"
+  "strings"
+
+  "pricingengine/service"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/model"
+
+  "pricingengine/test/util"
+)
+
+
+func TestAdminConfigEndpoints(tp *testing.T){
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: util.CopyConfigsToTempDir("../test_configs", tp),
+        },
+      },
+    },
+    AdminToken: "secret",
+  }
+  router := service.NewRouter(&rpc, settings.Default())
+
+  tp.Run("TestAdminEndpointRejectsMissingToken", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodGet, "/admin/config/versions", "", "")
+    util.AssertEqual(recorder.Code, http.StatusUnauthorized, t)
+    recorder = MakeAdminRequest(router, http.MethodGet, "/admin/config/versions", "", "wrong")
+    util.AssertEqual(recorder.Code, http.StatusUnauthorized, t)
+  })
+  tp.Run("TestAdminEndpointOnlyTakesABearerToken", func(t *testing.T) {
+    for _, authorization := range []string{"secret", "Basic secret", "Token secret", "Bearer"} {
+      request := httptest.NewRequest(http.MethodGet, "/admin/config/versions", nil)
+      request.Header.Set("Authorization", authorization)
+      recorder := httptest.NewRecorder()
+      router.ServeHTTP(recorder, request)
+      util.AssertEqual(recorder.Code, http.StatusUnauthorized, t)
+    }
+    request := httptest.NewRequest(http.MethodGet, "/admin/config/versions", nil)
+    request.Header.Set("Authorization", "bearer secret")
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, request)
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+  })
+  tp.Run("TestAdminEndpointUploadsFactorTable", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPut, "/admin/config/factors/licence-validity-factor?comment=flat",
+      `[{"length":"0","factor":1.5}]`, "secret")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    version := models.ConfigVersion{}
+    json.Unmarshal(recorder.Body.Bytes(), &version)
+    util.AssertEqual(version.Version, 1, t)
+    util.AssertEqual(version.Comment, "flat", t)
+    util.AssertTrue(version.Active, t)
+    util.AssertEqual(rpc.App.Cache.Snapshot().LicenceValidityFactorList[0].Value, 1.5, t)
+  })
+  tp.Run("TestAdminEndpointRejectsInvalidConfigSet", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/admin/config/versions",
+      `{"comment":"broken","files":{"base-rate.json":[{"time":"soon"}]}}`, "secret")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertTrue(strings.Contains(recorder.Body.String(), "base-rate.json"), t)
+    util.AssertEqual(rpc.App.Cache.Snapshot().Version, 1, t)
+  })
+  tp.Run("TestAdminEndpointRollsBackAndListsHistory", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/admin/config/versions/0/activate", "", "secret")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(rpc.App.Cache.Snapshot().Version, 0, t)
+
+    recorder = MakeAdminRequest(router, http.MethodPost, "/admin/config/versions/7/activate", "", "secret")
+    util.AssertEqual(recorder.Code, http.StatusNotFound, t)
+
+    recorder = MakeAdminRequest(router, http.MethodGet, "/admin/config/versions", "", "secret")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    versions := []models.ConfigVersion{}
+    json.Unmarshal(recorder.Body.Bytes(), &versions)
+    util.AssertEqual(len(versions), 2, t)
+    util.AssertTrue(versions[0].Active, t)
+  })
+}
+
+func MakeAdminRequest(handler http.Handler, method string, target string, body string, token string) *httptest.ResponseRecorder {
+  request := httptest.NewRequest(method, target, strings.NewReader(body))
+  if len(body) > 0 {
+    request.Header.Set("Content-Type", "application/json")
+  }
+  if len(token) > 0 {
+    request.Header.Set("Authorization", "Bearer " + token)
+  }
+  responseRecorder := httptest.NewRecorder()
+  handler.ServeHTTP(responseRecorder, request)
+  return responseRecorder
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+  "encoding/json"
+  "net/http"
+  "testing"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/app"
+  "pricingengine/service/customers"
+
+  "pricingengine/test/util"
+)
+
+
+func TestCustomerHistoryEndpoints(tp *testing.T){
+  router := service.NewRouter(&rpc.RPC{App: &app.App{Customers: &customers.Store{Dir: tp.TempDir()}}}, settings.Default())
+
+  tp.Run("TestCustomerHistoryEndpointRecordsPoliciesAndClaims", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/customers/C-1042/policies", `{"bound_on":"2024-03-01","reference":"POL-1"}`, "")
+    util.AssertEqual(recorder.Code, http.StatusCreated, t)
+    recorder = MakeAdminRequest(router, http.MethodPost, "/customers/C-1042/claims", `{"date":"2024-09-14","type":"accident","fault":true}`, "")
+    util.AssertEqual(recorder.Code, http.StatusCreated, t)
+
+    recorder = MakeAdminRequest(router, http.MethodGet, "/customers/C-1042", "", "")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    history := pricingengine.CustomerHistory{}
+    json.Unmarshal(recorder.Body.Bytes(), &history)
+    util.AssertEqual(history.Policies, []pricingengine.CustomerPolicy{{BoundOn: "2024-03-01", Reference: "POL-1"}}, t)
+    util.AssertEqual(history.Claims, []pricingengine.Claim{{Date: "2024-09-14", Type: "accident", Fault: true}}, t)
+  })
+  tp.Run("TestCustomerHistoryEndpointRejectsInvalidRecords", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/customers/C-1042/policies", `{"bound_on":"2999-01-01"}`, "")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertEqual(recorder.Body.String(), "invalid customer record: bound_on cannot be in the future", t)
+    recorder = MakeAdminRequest(router, http.MethodGet, "/customers/C.1042", "", "")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+  })
+  tp.Run("TestCustomerHistoryEndpointWithoutStore", func(t *testing.T) {
+    withoutStore := service.NewRouter(&rpc.RPC{App: &app.App{}}, settings.Default())
+    recorder := MakeAdminRequest(withoutStore, http.MethodGet, "/customers/C-1042", "", "")
+    util.AssertEqual(recorder.Code, http.StatusNotFound, t)
+  })
+}
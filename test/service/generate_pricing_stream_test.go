@@ -0,0 +1,103 @@
+package service
+
+import (
+  "bufio"
+  "context"
+  "encoding/json"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+
+  "pricingengine/test/util"
+)
+
+
+func TestGeneratePricingStreamEndpoint(tp *testing.T){
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+  }
+  server := httptest.NewServer(service.NewRouter(&rpc, settings.Default()))
+  defer server.Close()
+
+  now := time.Now()
+  dob := now.AddDate(-20, 0, 0).Format("2006-01-02") //20 years before now
+  licence := now.AddDate(-7, 0, 0).Format("2006-01-02") //7 years before now
+  valid := `{"date_of_birth":"` + dob + `","insurance_group":7,"license_held_since":"` + licence + `"}`
+
+  tp.Run("TestGeneratePricingStreamWritesOneLinePerInput", func(t *testing.T) {
+    body := valid + "\n\n" + `{"date_of_birth":` + "\n" + `{"insurance_group":7}` + "\n"
+    resp, err := http.Post(server.URL + "/generate_pricing/stream", "application/x-ndjson", strings.NewReader(body))
+    util.AssertTrue(err == nil, t)
+    defer resp.Body.Close()
+    util.AssertEqual(resp.Header.Get("Content-Type"), "application/x-ndjson", t)
+
+    results := []pricingengine.GeneratePricingResponse{}
+    scanner := bufio.NewScanner(resp.Body)
+    for scanner.Scan() {
+      result := pricingengine.GeneratePricingResponse{}
+      json.Unmarshal(scanner.Bytes(), &result)
+      results = append(results, result)
+    }
+    util.AssertEqual(len(results), 3, t)
+    util.AssertTrue(results[0].IsEligible, t)
+    util.AssertEqual(len(results[0].PricingList), 2, t)
+    util.AssertFalse(results[1].IsEligible, t)
+    util.AssertTrue(strings.HasPrefix(results[1].Message, "Invalid request:"), t)
+    util.AssertEqual(results[2].Message, "DateOfBirth cannot be empty", t)
+  })
+  tp.Run("TestGeneratePricingStreamFlushesPerRecordAndStopsOnCancel", func(t *testing.T) {
+    reader, writer := io.Pipe()
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    request, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL + "/generate_pricing/stream", reader)
+    request.Header.Set("Content-Type", "application/x-ndjson")
+    go writer.Write([]byte(valid + "\n"))
+
+    resp, err := http.DefaultClient.Do(request)
+    util.AssertTrue(err == nil, t)
+    defer resp.Body.Close()
+    lines := bufio.NewReader(resp.Body)
+    // the first record is answered while the request body is still open
+    line, err := lines.ReadBytes('\n')
+    util.AssertTrue(err == nil, t)
+    result := pricingengine.GeneratePricingResponse{}
+    json.Unmarshal(line, &result)
+    util.AssertTrue(result.IsEligible, t)
+
+    cancel()
+    writer.Close()
+    _, err = lines.ReadBytes('\n')
+    util.AssertTrue(err != nil, t)
+  })
+  tp.Run("TestGeneratePricingStreamRejectsOtherContentTypes", func(t *testing.T) {
+    for _, contentType := range []string{"application/json", "text/plain", ""} {
+      resp, err := http.Post(server.URL + "/generate_pricing/stream", contentType, strings.NewReader(valid + "\n"))
+      util.AssertTrue(err == nil, t)
+      body, _ := io.ReadAll(resp.Body)
+      resp.Body.Close()
+      util.AssertEqual(resp.StatusCode, http.StatusUnsupportedMediaType, t)
+      util.AssertEqual(string(body), "Content-Type should be application/x-ndjson", t)
+    }
+    resp, err := http.Post(server.URL + "/generate_pricing/stream", "application/x-ndjson; charset=utf-8", strings.NewReader(valid + "\n"))
+    util.AssertTrue(err == nil, t)
+    resp.Body.Close()
+    util.AssertEqual(resp.StatusCode, http.StatusOK, t)
+  })
+}
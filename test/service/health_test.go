@@ -0,0 +1,89 @@
+package service
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "net/http"
+  "net/http/httptest"
+  "path/filepath"
+  "strings"
+  "testing"
+
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/model"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+
+  "pricingengine/test/util"
+)
+
+
+func GetConfigStatus(router http.Handler, target string) (*httptest.ResponseRecorder, models.ConfigStatus) {
+  recorder := httptest.NewRecorder()
+  router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+  status := models.ConfigStatus{}
+  json.Unmarshal(recorder.Body.Bytes(), &status)
+  return recorder, status
+}
+
+func TestHealthEndpoints(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  baseRate := filepath.Join(path, config.BaseRateFile)
+  valid, _ := ioutil.ReadFile(baseRate)
+  ioutil.WriteFile(baseRate, []byte(`[{"time":1800,"label":"0.5 hours"`), 0644)
+
+  rpc := rpc.RPC{
+    App: &app.App{ConfigPath: path, CacheTTL: 1000},
+  }
+  router := service.NewRouter(&rpc, settings.Default())
+
+  tp.Run("TestHealthzAnswersWhileConfigIsNotLoaded", func(t *testing.T) {
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(strings.TrimSpace(recorder.Body.String()), `{"status":"ok"}`, t)
+  })
+  tp.Run("TestReadyzDoesNotLoadConfig", func(t *testing.T) {
+    recorder, status := GetConfigStatus(router, "/readyz")
+    util.AssertEqual(recorder.Code, http.StatusServiceUnavailable, t)
+    util.AssertFalse(status.Ready, t)
+    util.AssertEqual(status.LastReloadAt, "", t)
+  })
+  tp.Run("TestReadyzFailsUntilConfigIsLoaded", func(t *testing.T) {
+    _, status := GetConfigStatus(router, "/debug/config-status")
+    util.AssertEqual(len(status.Files), 0, t)
+
+    recorder, status := GetConfigStatus(router, "/readyz")
+    util.AssertEqual(recorder.Code, http.StatusServiceUnavailable, t)
+    util.AssertFalse(status.Ready, t)
+    util.AssertTrue(strings.Contains(status.LastReloadError, config.BaseRateFile), t)
+    util.AssertTrue(len(status.LastReloadAt) > 0, t)
+
+    // the probe keeps reporting the failed load until something loads the fixed document
+    ioutil.WriteFile(baseRate, valid, 0644)
+    recorder, _ = GetConfigStatus(router, "/readyz")
+    util.AssertEqual(recorder.Code, http.StatusServiceUnavailable, t)
+  })
+  tp.Run("TestReadyzSucceedsOnceConfigIsLoaded", func(t *testing.T) {
+    GetConfigStatus(router, "/debug/config-status")
+    recorder, status := GetConfigStatus(router, "/readyz")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertTrue(status.Ready, t)
+    util.AssertEqual(status.Version, 0, t)
+    util.AssertEqual(status.LastReloadError, "", t)
+    util.AssertEqual(len(status.Files), 0, t)
+  })
+  tp.Run("TestConfigStatusListsEveryFile", func(t *testing.T) {
+    recorder, status := GetConfigStatus(router, "/debug/config-status")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertTrue(status.Ready, t)
+    util.AssertEqual(len(status.Files), len(config.FactorFiles), t)
+    for i, file := range status.Files {
+      util.AssertEqual(file.File, config.FactorFiles[i], t)
+      util.AssertTrue(len(file.LoadedAt) > 0, t)
+    }
+    util.AssertTrue(len(status.LoadedAt) > 0, t)
+  })
+}
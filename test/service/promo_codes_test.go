@@ -0,0 +1,65 @@
+package service
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "net/http"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/promo"
+
+  "pricingengine/test/util"
+)
+
+
+func TestRedeemPromoCodeEndpoint(tp *testing.T){
+  path := util.CopyConfigsToTempDir("../test_configs", tp)
+  if err := ioutil.WriteFile(filepath.Join(path, config.PromoCodeFile), []byte(`[{"code": "ONCE", "type": "subtract", "value": 1, "max-uses": 1}]`), 0644); err != nil {
+    tp.Fatal(err)
+  }
+  pricingApp := &app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: path,
+      },
+    },
+    PromoUsage: &promo.UsageStore{File: filepath.Join(tp.TempDir(), "promo-usage.json")},
+  }
+  router := service.NewRouter(&rpc.RPC{App: pricingApp}, settings.Default())
+
+  now := time.Now()
+  quote := `{"request":{"date_of_birth":"` + now.AddDate(-20, 0, 0).Format("2006-01-02") + `","insurance_group":7,"license_held_since":"` + now.AddDate(-7, 0, 0).Format("2006-01-02") + `"},"duration":"0.5 hours"}`
+
+  tp.Run("TestRedeemPromoCodeOnce", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/promo_codes/once/redeem", quote, "")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    redeemed := pricingengine.Promo{}
+    json.Unmarshal(recorder.Body.Bytes(), &redeemed)
+    util.AssertEqual(redeemed, pricingengine.Promo{Code: "ONCE", Applied: true}, t)
+
+    recorder = MakeAdminRequest(router, http.MethodPost, "/promo_codes/ONCE/redeem", quote, "")
+    util.AssertEqual(recorder.Code, http.StatusConflict, t)
+    util.AssertEqual(recorder.Body.String(), "Promo code ONCE has reached its usage limit", t)
+  })
+  tp.Run("TestRedeemUnknownPromoCode", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/promo_codes/NOPE/redeem", quote, "")
+    util.AssertEqual(recorder.Code, http.StatusNotFound, t)
+    util.AssertEqual(recorder.Body.String(), "Promo code NOPE is not known", t)
+  })
+  tp.Run("TestRedeemPromoCodeNeedsAQuote", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/promo_codes/ONCE/redeem", "", "")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    recorder = MakeAdminRequest(router, http.MethodPost, "/promo_codes/ONCE/redeem", `{"request":{"insurance_group":7},"duration":"0.5 hours"}`, "")
+    util.AssertEqual(recorder.Code, http.StatusUnprocessableEntity, t)
+    util.AssertEqual(recorder.Body.String(), "Quote can not be priced: DateOfBirth cannot be empty", t)
+  })
+}
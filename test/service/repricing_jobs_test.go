@@ -0,0 +1,101 @@
+package service
+
+import (
+  "encoding/json"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/jobs"
+
+  "pricingengine/test/util"
+)
+
+
+func TestRepricingJobEndpoints(tp *testing.T){
+  pricingApp := &app.App{
+    Cache: config.ConfigCache{
+      TimeToLive : 1,
+      Fetcher: config.ConfigFetcher{
+        Path: "../test_configs",
+      },
+    },
+  }
+  manager := jobs.Manager{App: pricingApp, Store: &jobs.Store{Dir: tp.TempDir()}}
+  manager.Start()
+  defer manager.Stop()
+  rpc := rpc.RPC{App: pricingApp, Jobs: &manager}
+  router := service.NewRouter(&rpc, settings.Default())
+
+  now := time.Now()
+  valid := `{"date_of_birth":"` + now.AddDate(-20, 0, 0).Format("2006-01-02") + `","insurance_group":7,"license_held_since":"` + now.AddDate(-7, 0, 0).Format("2006-01-02") + `"}`
+
+  pollJob := func(id string, t *testing.T) pricingengine.RepricingJob {
+    job := pricingengine.RepricingJob{}
+    for i := 0; i < 500; i++ {
+      recorder := MakeAdminRequest(router, http.MethodGet, "/jobs/" + id, "", "")
+      util.AssertEqual(recorder.Code, http.StatusOK, t)
+      json.Unmarshal(recorder.Body.Bytes(), &job)
+      if job.Status == jobs.StatusCompleted {
+        break
+      }
+      time.Sleep(10 * time.Millisecond)
+    }
+    return job
+  }
+
+  tp.Run("TestRepricingJobEndpointSubmitListAndDownloadResults", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodPost, "/jobs", `{"requests":[` + valid + `,{}]}`, "")
+    util.AssertEqual(recorder.Code, http.StatusAccepted, t)
+    job := pricingengine.RepricingJob{}
+    json.Unmarshal(recorder.Body.Bytes(), &job)
+    util.AssertEqual(job.Total, 2, t)
+
+    job = pollJob(job.ID, t)
+    util.AssertEqual(job.Status, jobs.StatusCompleted, t)
+    util.AssertEqual(job.Priced, 1, t)
+    util.AssertEqual(job.Declined, 1, t)
+
+    recorder = MakeAdminRequest(router, http.MethodGet, "/jobs/" + job.ID + "/results", "", "")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+    util.AssertEqual(recorder.Header().Get("Content-Type"), "application/x-ndjson", t)
+    lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+    util.AssertEqual(len(lines), 2, t)
+    util.AssertTrue(strings.Contains(lines[1], "DateOfBirth cannot be empty"), t)
+
+    recorder = MakeAdminRequest(router, http.MethodPost, "/jobs/" + job.ID + "/cancel", "", "")
+    util.AssertEqual(recorder.Code, http.StatusConflict, t)
+  })
+  tp.Run("TestRepricingJobEndpointSubmitNDJSONFile", func(t *testing.T) {
+    request := strings.NewReader(valid + "\n" + valid + "\n")
+    recorder := MakeRequestWithContentType(router, http.MethodPost, "/jobs", request, "application/x-ndjson")
+    util.AssertEqual(recorder.Code, http.StatusAccepted, t)
+    job := pricingengine.RepricingJob{}
+    json.Unmarshal(recorder.Body.Bytes(), &job)
+    util.AssertEqual(job.Total, 2, t)
+    util.AssertEqual(pollJob(job.ID, t).Priced, 2, t)
+  })
+  tp.Run("TestRepricingJobEndpointUnknownJob", func(t *testing.T) {
+    recorder := MakeAdminRequest(router, http.MethodGet, "/jobs/unknown", "", "")
+    util.AssertEqual(recorder.Code, http.StatusNotFound, t)
+    recorder = MakeAdminRequest(router, http.MethodPost, "/jobs", `{"requests":[]}`, "")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+  })
+}
+
+func MakeRequestWithContentType(handler http.Handler, method string, target string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+  request := httptest.NewRequest(method, target, body)
+  request.Header.Set("Content-Type", contentType)
+  responseRecorder := httptest.NewRecorder()
+  handler.ServeHTTP(responseRecorder, request)
+  return responseRecorder
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+  "encoding/json"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/logging"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+
+  "github.com/go-chi/chi/middleware"
+
+  "pricingengine/test/util"
+)
+
+
+func PostPricing(router http.Handler, body string, contentType string) *httptest.ResponseRecorder {
+  request := httptest.NewRequest(http.MethodPost, "/generate_pricing", strings.NewReader(body))
+  if len(contentType) > 0 {
+    request.Header.Set("Content-Type", contentType)
+  }
+  recorder := httptest.NewRecorder()
+  router.ServeHTTP(recorder, request)
+  return recorder
+}
+
+func TestRequestDecoding(tp *testing.T){
+  rpc := rpc.RPC{
+    App: &app.App{
+      Cache: config.ConfigCache{
+        TimeToLive : 1,
+        Fetcher: config.ConfigFetcher{
+          Path: "../test_configs",
+        },
+      },
+    },
+    MaxBodyBytes: 2048,
+  }
+  // the schema validation is left out to exercise the decoding of the handlers themselves
+  configured := settings.Default()
+  configured.Features.SchemaValidation = false
+  router := service.NewRouter(&rpc, configured)
+  valid := `{"date_of_birth":"1990-01-01","insurance_group":7,"license_held_since":"2015-01-01"}`
+
+  tp.Run("TestValidBody", func(t *testing.T) {
+    recorder := PostPricing(router, valid, "application/json; charset=utf-8")
+    util.AssertEqual(recorder.Code, http.StatusOK, t)
+  })
+  tp.Run("TestNullAndEmptyBodies", func(t *testing.T) {
+    recorder := PostPricing(router, "null", "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertEqual(recorder.Body.String(), "request body cannot be null", t)
+    recorder = PostPricing(router, " \n", "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertEqual(recorder.Body.String(), "request body cannot be empty", t)
+  })
+  tp.Run("TestUnknownFieldsAndTrailingData", func(t *testing.T) {
+    recorder := PostPricing(router, `{"date_of_birth":"1990-01-01","insurance_grp":7}`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertTrue(strings.Contains(recorder.Body.String(), `unknown field "insurance_grp"`), t)
+    recorder = PostPricing(router, valid + `{"insurance_group":1}`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+    util.AssertTrue(strings.Contains(recorder.Body.String(), "unexpected data after the JSON object"), t)
+    recorder = PostPricing(router, `[` + valid + `]`, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusBadRequest, t)
+  })
+  tp.Run("TestContentTypeIsRequired", func(t *testing.T) {
+    for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+      recorder := PostPricing(router, valid, contentType)
+      util.AssertEqual(recorder.Code, http.StatusUnsupportedMediaType, t)
+    }
+  })
+  tp.Run("TestBodySizeIsCapped", func(t *testing.T) {
+    padded := `{"date_of_birth":"1990-01-01` + strings.Repeat(" ", 4096) + `"}`
+    recorder := PostPricing(router, padded, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusRequestEntityTooLarge, t)
+    util.AssertEqual(recorder.Body.String(), "request body should be at most 2048 bytes", t)
+
+    // the schema validation reads the body first and answers the same way
+    validating := service.NewRouter(&rpc, settings.Default())
+    recorder = PostPricing(validating, padded, "application/json")
+    util.AssertEqual(recorder.Code, http.StatusRequestEntityTooLarge, t)
+  })
+  tp.Run("TestPanicsAreAnsweredWithAStructured500", func(t *testing.T) {
+    handler := middleware.RequestID(logging.Middleware(rpc.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      var request *pricingengine.GeneratePricingRequest
+      w.Write([]byte(request.DateOfBirth))
+    }))))
+    recorder := httptest.NewRecorder()
+    handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+    util.AssertEqual(recorder.Code, http.StatusInternalServerError, t)
+    util.AssertEqual(recorder.Header().Get("Content-Type"), "application/json", t)
+    body := pricingengine.ErrorResponse{}
+    util.AssertEqual(json.Unmarshal(recorder.Body.Bytes(), &body), nil, t)
+    util.AssertEqual(body.Code, "internal_error", t)
+    util.AssertEqual(body.RequestID, recorder.Header().Get(logging.RequestIDHeader), t)
+    util.AssertTrue(len(body.RequestID) > 0, t)
+  })
+  tp.Run("TestNilRequestIsDeclined", func(t *testing.T) {
+    res, err := rpc.App.GeneratePricing(httptest.NewRequest(http.MethodGet, "/", nil).Context(), nil)
+    util.AssertEqual(err, nil, t)
+    util.AssertFalse(res.IsEligible, t)
+    util.AssertEqual(res.Message, "request cannot be empty", t)
+  })
+}
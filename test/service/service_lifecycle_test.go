@@ -0,0 +1,166 @@
+package service
+
+import (
+  "bufio"
+  "context"
+  "io"
+  "net"
+  "net/http"
+  "testing"
+  "time"
+
+  "pricingengine/service"
+  "pricingengine/service/settings"
+
+  "pricingengine/test/util"
+)
+
+
+func NewTestService(t *testing.T) *service.Service {
+  config := settings.Default()
+  config.ListenAddress = "127.0.0.1:0"
+  config.ConfigDir = "../test_configs"
+  config.JobsDir = t.TempDir()
+  return &service.Service{Settings: config}
+}
+
+// StartTestService serves the service on a random port until the returned context is cancelled
+// returns the base URL along with the channel receiving the result of Serve
+func StartTestService(s *service.Service, t *testing.T) (context.CancelFunc, string, chan error) {
+  listener, err := net.Listen("tcp", "127.0.0.1:0")
+  if err != nil {
+    t.Fatal(err)
+  }
+  ctx, cancel := context.WithCancel(context.Background())
+  served := make(chan error, 1)
+  go func() {
+    served <- s.Serve(ctx, listener)
+  }()
+  return cancel, "http://" + listener.Addr().String(), served
+}
+
+func WaitForServe(served chan error, t *testing.T) error {
+  select {
+  case err := <-served:
+    return err
+  case <-time.After(10 * time.Second):
+    t.Fatal("service did not stop")
+    return nil
+  }
+}
+
+func TestServiceLifecycle(tp *testing.T){
+
+  tp.Run("TestServiceStartReturnsListenError", func(t *testing.T) {
+    listener, _ := net.Listen("tcp", "127.0.0.1:0")
+    defer listener.Close()
+    s := NewTestService(t)
+    s.Settings.ListenAddress = listener.Addr().String()
+    err := s.Start(context.Background())
+    util.AssertTrue(err != nil, t)
+  })
+
+  tp.Run("TestServiceStopsWhenContextIsCancelled", func(t *testing.T) {
+    cancel, url, served := StartTestService(NewTestService(t), t)
+    resp, err := http.Get(url + "/generate_pricing")
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(resp.StatusCode, 200, t)
+    resp.Body.Close()
+
+    cancel()
+    util.AssertEqual(WaitForServe(served, t), nil, t)
+    _, err = http.Get(url + "/generate_pricing")
+    util.AssertTrue(err != nil, t)
+  })
+
+  tp.Run("TestServiceStopsOnStop", func(t *testing.T) {
+    s := NewTestService(t)
+    cancel, _, served := StartTestService(s, t)
+    defer cancel()
+    time.Sleep(50 * time.Millisecond)
+    s.Stop()
+    util.AssertEqual(WaitForServe(served, t), nil, t)
+  })
+
+  tp.Run("TestServiceDrainsInFlightRequests", func(t *testing.T) {
+    cancel, url, served := StartTestService(NewTestService(t), t)
+    body, input := io.Pipe()
+    request, _ := http.NewRequest(http.MethodPost, url + "/generate_pricing/stream", body)
+    request.Header.Set("Content-Type", "application/x-ndjson")
+    responses := make(chan *http.Response, 1)
+    go func() {
+      resp, _ := http.DefaultClient.Do(request)
+      responses <- resp
+    }()
+    input.Write([]byte(`{"insurance_group":7}` + "\n"))
+    resp := <-responses
+    lines := bufio.NewScanner(resp.Body)
+    util.AssertTrue(lines.Scan(), t)
+
+    // the stream is still open when the shutdown starts, it is allowed to complete
+    cancel()
+    time.Sleep(50 * time.Millisecond)
+    input.Write([]byte(`{"insurance_group":7}` + "\n"))
+    input.Close()
+    util.AssertTrue(lines.Scan(), t)
+    util.AssertFalse(lines.Scan(), t)
+    resp.Body.Close()
+    util.AssertEqual(WaitForServe(served, t), nil, t)
+  })
+
+  tp.Run("TestServiceClosesRequestsAfterShutdownTimeout", func(t *testing.T) {
+    s := NewTestService(t)
+    s.Settings.ShutdownTimeout = settings.Duration{Duration: 100 * time.Millisecond}
+    cancel, url, served := StartTestService(s, t)
+    body, input := io.Pipe()
+    defer input.Close()
+    request, _ := http.NewRequest(http.MethodPost, url + "/generate_pricing/stream", body)
+    request.Header.Set("Content-Type", "application/x-ndjson")
+    responses := make(chan *http.Response, 1)
+    go func() {
+      resp, _ := http.DefaultClient.Do(request)
+      responses <- resp
+    }()
+    input.Write([]byte(`{"insurance_group":7}` + "\n"))
+    resp := <-responses
+    defer resp.Body.Close()
+    util.AssertTrue(bufio.NewScanner(resp.Body).Scan(), t)
+
+    // the stream never completes, its connection is closed once the timeout is over
+    cancel()
+    util.AssertEqual(WaitForServe(served, t), context.DeadlineExceeded, t)
+  })
+
+  tp.Run("TestServiceDrainsEverythingUnderOneDeadline", func(t *testing.T) {
+    s := NewTestService(t)
+    s.Settings.ShutdownTimeout = settings.Duration{Duration: 200 * time.Millisecond}
+    drained := make(chan time.Duration, 1)
+    var stopping time.Time
+    s.Drains = append(s.Drains, func(ctx context.Context) {
+      // e.g. a gRPC server whose calls never complete
+      <-ctx.Done()
+      drained <- time.Since(stopping)
+    })
+    cancel, url, served := StartTestService(s, t)
+    body, input := io.Pipe()
+    defer input.Close()
+    request, _ := http.NewRequest(http.MethodPost, url + "/generate_pricing/stream", body)
+    request.Header.Set("Content-Type", "application/x-ndjson")
+    responses := make(chan *http.Response, 1)
+    go func() {
+      resp, _ := http.DefaultClient.Do(request)
+      responses <- resp
+    }()
+    input.Write([]byte(`{"insurance_group":7}` + "\n"))
+    resp := <-responses
+    defer resp.Body.Close()
+    util.AssertTrue(bufio.NewScanner(resp.Body).Scan(), t)
+
+    // the stream and the drain both wait for the whole timeout, side by side rather than one after the other
+    stopping = time.Now()
+    cancel()
+    util.AssertEqual(WaitForServe(served, t), context.DeadlineExceeded, t)
+    util.AssertTrue(time.Since(stopping) < 400 * time.Millisecond, t)
+    util.AssertTrue(<-drained < 400 * time.Millisecond, t)
+  })
+}
@@ -0,0 +1,139 @@
+package settings
+
+import (
+  "io/ioutil"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine/service/settings"
+  "pricingengine/test/util"
+)
+
+
+func FakeEnv(values map[string]string) func(string) string {
+  return func(name string) string {
+    return values[name]
+  }
+}
+
+func WriteSettingsFile(name string, content string, t *testing.T) string {
+  filename := filepath.Join(t.TempDir(), name)
+  if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+    t.Fatal(err)
+  }
+  return filename
+}
+
+func TestLoadSettings(tp *testing.T) {
+
+  tp.Run("Defaults", func(t *testing.T) {
+    config, err := settings.Load([]string{"-config-dir", "../test_configs"}, FakeEnv(nil))
+    util.AssertEqual(err, nil, t)
+    util.AssertEqual(config.ListenAddress, ":3000", t)
+    util.AssertEqual(config.GRPCListenAddress, ":3001", t)
+    util.AssertEqual(config.CacheTTL.Duration, 100000*time.Second, t)
+    util.AssertEqual(config.RequestTimeout.Duration, 5*time.Second, t)
+    util.AssertEqual(config.ShutdownTimeout.Duration, 30*time.Second, t)
+    util.AssertEqual(config.MaxCoverStartAhead.Duration, 30*24*time.Hour, t)
+    util.AssertEqual(config.PromoUsageFile, "promo-usage.json", t)
+    util.AssertEqual(config.CustomersDir, "customers", t)
+    util.AssertTrue(config.Features.Customers, t)
+    util.AssertEqual(config.Workers, 2, t)
+    util.AssertTrue(config.Features.Jobs, t)
+    dir, _ := filepath.Abs("../test_configs")
+    util.AssertEqual(config.ConfigPath(), dir, t)
+  })
+
+  tp.Run("YAMLFile", func(t *testing.T) {
+    filename := WriteSettingsFile("settings.yaml", `
+listen_address: ":4000"
+config_dir: ../test_configs
+cache_ttl: 1m
+workers: 4
+features:
+  grpc: false
+`, t)
+    config, err := settings.Load([]string{"-settings-file", filename}, FakeEnv(nil))
+    util.AssertEqual(err, nil, t)
+    util.AssertEqual(config.ListenAddress, ":4000", t)
+    util.AssertEqual(config.CacheTTL.Duration, time.Minute, t)
+    util.AssertEqual(config.Workers, 4, t)
+    util.AssertFalse(config.Features.GRPC, t)
+    util.AssertTrue(config.Features.Admin, t)
+  })
+
+  tp.Run("JSONFileFromEnv", func(t *testing.T) {
+    filename := WriteSettingsFile("settings.json", `{"config_dir": "../test_configs", "request_timeout": "2s"}`, t)
+    config, err := settings.Load(nil, FakeEnv(map[string]string{"PRICING_ENGINE_SETTINGS_FILE": filename}))
+    util.AssertEqual(err, nil, t)
+    util.AssertEqual(config.RequestTimeout.Duration, 2*time.Second, t)
+  })
+
+  tp.Run("Precedence", func(t *testing.T) {
+    filename := WriteSettingsFile("settings.yaml", "config_dir: ../test_configs\nworkers: 4\nlog_level: debug\nlisten_address: \":4000\"\n", t)
+    env := FakeEnv(map[string]string{
+      "PRICING_ENGINE_WORKERS": "6",
+      "PRICING_ENGINE_LISTEN_ADDRESS": ":5000",
+    })
+    config, err := settings.Load([]string{"-settings-file", filename, "-listen-address", ":6000"}, env)
+    util.AssertEqual(err, nil, t)
+    util.AssertEqual(config.LogLevel, "debug", t)
+    util.AssertEqual(config.Workers, 6, t)
+    util.AssertEqual(config.ListenAddress, ":6000", t)
+  })
+
+  tp.Run("UnknownFileField", func(t *testing.T) {
+    filename := WriteSettingsFile("settings.yaml", "config_dir: ../test_configs\nworker: 4\n", t)
+    _, err := settings.Load([]string{"-settings-file", filename}, FakeEnv(nil))
+    util.AssertTrue(err != nil, t)
+  })
+
+  tp.Run("InvalidEnvValue", func(t *testing.T) {
+    _, err := settings.Load([]string{"-config-dir", "../test_configs"}, FakeEnv(map[string]string{"PRICING_ENGINE_CACHE_TTL": "soon"}))
+    util.AssertTrue(err != nil && strings.Contains(err.Error(), "PRICING_ENGINE_CACHE_TTL"), t)
+  })
+
+  tp.Run("ValidationErrors", func(t *testing.T) {
+    _, err := settings.Load([]string{"-config-dir", "missing", "-workers", "0", "-log-level", "loud", "-listen-address", ":3001", "-max-body-bytes", "10", "-max-cover-start-ahead", "0s"}, FakeEnv(nil))
+    util.AssertTrue(err != nil, t)
+    for _, problem := range []string{"config_dir", "workers", "log_level", "grpc_listen_address", "max_body_bytes", "max_cover_start_ahead"} {
+      util.AssertTrue(strings.Contains(err.Error(), problem), t)
+    }
+  })
+
+  tp.Run("Tracing", func(t *testing.T) {
+    filename := WriteSettingsFile("settings.yaml", "config_dir: ../test_configs\ntracing:\n  exporter: otlp\n  endpoint: collector:4317\n", t)
+    config, err := settings.Load([]string{"-settings-file", filename, "-tracing-sample-ratio", "0.25"}, FakeEnv(nil))
+    util.AssertEqual(err, nil, t)
+    util.AssertEqual(config.Tracing.Exporter, "otlp", t)
+    util.AssertEqual(config.Tracing.Endpoint, "collector:4317", t)
+    util.AssertEqual(config.Tracing.SampleRatio, 0.25, t)
+
+    _, err = settings.Load([]string{"-config-dir", "../test_configs", "-tracing-exporter", "zipkin", "-tracing-sample-ratio", "2"}, FakeEnv(nil))
+    util.AssertTrue(err != nil, t)
+    for _, problem := range []string{"tracing.exporter", "tracing.sample_ratio"} {
+      util.AssertTrue(strings.Contains(err.Error(), problem), t)
+    }
+  })
+
+  tp.Run("TLS", func(t *testing.T) {
+    dir := t.TempDir()
+    cert := filepath.Join(dir, "server.pem")
+    ioutil.WriteFile(cert, []byte("certificate"), 0600)
+    config, err := settings.Load([]string{"-config-dir", "../test_configs", "-tls-cert-file", cert, "-tls-key-file", cert, "-tls-client-ca-file", cert, "-tls-client-auth", "require"}, FakeEnv(nil))
+    util.AssertEqual(err, nil, t)
+    util.AssertTrue(config.TLS.Enabled(), t)
+    util.AssertEqual(config.TLS.Options().ClientAuth, "require", t)
+    util.AssertEqual(config.TLS.ReloadInterval.Duration, 10 * time.Second, t)
+
+    _, err = settings.Load([]string{"-config-dir", "../test_configs", "-tls-cert-file", cert, "-tls-client-auth", "optional", "-tls-reload-interval", "10ms"}, FakeEnv(nil))
+    util.AssertTrue(err != nil, t)
+    for _, problem := range []string{"cert_file and key_file", "tls.client_ca_file", "tls.reload_interval"} {
+      util.AssertTrue(strings.Contains(err.Error(), problem), t)
+    }
+    _, err = settings.Load([]string{"-config-dir", "../test_configs", "-tls-client-auth", "always"}, FakeEnv(nil))
+    util.AssertTrue(err != nil && strings.Contains(err.Error(), "tls.client_auth"), t)
+  })
+}
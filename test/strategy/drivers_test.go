@@ -0,0 +1,93 @@
+package strategy
+
+import (
+  "testing"
+
+  "pricingengine"
+	"pricingengine/service/model"
+	"pricingengine/service/strategy"
+  "pricingengine/test/util"
+)
+
+
+func TestPricingStrategyCombineDrivers(tp *testing.T){
+  var strategies = strategy.Strategy{}
+  main := strategy.DriverBands{
+    Driver: strategy.MainDriver,
+    Age: &models.RangeConfig{Start: 26, End: 50, IsEligible: true, Value: 1, Label: "Driver Age:26-50"},
+    Licence: &models.RangeConfig{Start: 6, End: 100, IsEligible: true, Value: 0.9, Label: "Licence Validity:6"},
+  }
+  novice := strategy.DriverBands{
+    Driver: "Jane",
+    Age: &models.RangeConfig{Start: 16, End: 26, IsEligible: true, Value: 1.3, Label: "Driver Age:16-26"},
+    Licence: &models.RangeConfig{Start: 0, End: 6, IsEligible: true, Value: 10, Label: "Licence Validity:0-6", Operation: "add"},
+  }
+
+  tp.Run("TestDriverNames", func(t *testing.T) {
+    util.AssertEqual(strategy.DriverName(0, pricingengine.Driver{Name: " Jane "}), "Jane", t)
+    util.AssertEqual(strategy.DriverName(1, pricingengine.Driver{}), "Driver 3", t)
+  })
+  tp.Run("TestDriverRiskAppliesTheOperations", func(t *testing.T) {
+    util.AssertEqual(strategy.DriverRisk(main, 100), 90.0, t)
+    util.AssertEqual(strategy.DriverRisk(novice, 100), 140.0, t)
+    util.AssertEqual(strategy.DriverRisk(novice, 500), 660.0, t)
+  })
+  tp.Run("TestCombineDriversAloneKeepsTheBands", func(t *testing.T) {
+    age, licence, loadings := strategies.CombineDrivers([]strategy.DriverBands{main}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage}, 100)
+    util.AssertTrue(age == main.Age && licence == main.Licence, t)
+    util.AssertEqual(len(loadings), 0, t)
+  })
+  tp.Run("TestCombineDriversHighestRisk", func(t *testing.T) {
+    age, licence, _ := strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleHighestRisk}, 100)
+    util.AssertEqual(age.Label, "Driver Age:16-26 (Jane)", t)
+    util.AssertEqual(*licence, models.RangeConfig{Start: 0, End: 6, IsEligible: true, Value: 10, Label: "Licence Validity:0-6 (Jane)", Operation: "add"}, t)
+    util.AssertEqual(novice.Age.Label, "Driver Age:16-26", t)
+  })
+  tp.Run("TestCombineDriversWeightedAverage", func(t *testing.T) {
+    age, _, _ := strategies.CombineDrivers([]strategy.DriverBands{main, novice, novice}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage}, 100)
+    util.AssertEqual(*age, models.RangeConfig{Start: 26, End: 50, IsEligible: true, Value: 1.2, Label: "Driver Age:26-50 (weighted average of 3 drivers)"}, t)
+    age, _, _ = strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage, MainWeight: 0.75}, 100)
+    util.AssertEqual(age.Value, 1.075, t)
+  })
+  tp.Run("TestCombineDriversHighestRiskAtThePremium", func(t *testing.T) {
+    // a flat 10 added to 100 is more than 1.09 times 100, but less than 1.09 times 500
+    steady := strategy.DriverBands{
+      Driver: "Joe",
+      Age: &models.RangeConfig{Start: 26, End: 50, IsEligible: true, Value: 1.09, Label: "Driver Age:26-50"},
+      Licence: &models.RangeConfig{Start: 6, End: 100, IsEligible: true, Value: 1, Label: "Licence Validity:6"},
+    }
+    flat := strategy.DriverBands{
+      Driver: "Jane",
+      Age: &models.RangeConfig{Start: 26, End: 50, IsEligible: true, Value: 1, Label: "Driver Age:26-50"},
+      Licence: &models.RangeConfig{Start: 0, End: 6, IsEligible: true, Value: 10, Label: "Licence Validity:0-6", Operation: "add"},
+    }
+    age, _, _ := strategies.CombineDrivers([]strategy.DriverBands{steady, flat}, models.DriverCombination{Rule: strategy.DriverRuleHighestRisk}, 100)
+    util.AssertEqual(age.Label, "Driver Age:26-50 (Jane)", t)
+    age, _, _ = strategies.CombineDrivers([]strategy.DriverBands{steady, flat}, models.DriverCombination{Rule: strategy.DriverRuleHighestRisk}, 500)
+    util.AssertEqual(age.Label, "Driver Age:26-50 (Joe)", t)
+  })
+  tp.Run("TestCombineDriversWeightedAverageOfMixedOperations", func(t *testing.T) {
+    // 0.9 times the premium and 10 added to it average out to 0.95 times the premium plus 5
+    _, licence, loadings := strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage}, 100)
+    util.AssertEqual(*licence, models.RangeConfig{Start: 6, End: 100, IsEligible: true, Value: 0.95, Label: "Licence Validity:6 (weighted average of 2 drivers)", Operation: "multiply"}, t)
+    util.AssertEqual(len(loadings), 1, t)
+    util.AssertEqual(*loadings[0], models.RangeConfig{IsEligible: true, Value: 5, Label: "Licence Validity:6 (weighted average of 2 drivers) flat", Operation: "add"}, t)
+    _, licence, loadings = strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage, MainWeight: 0.75}, 100)
+    util.AssertEqual(licence.Value, 0.925, t)
+    util.AssertEqual(loadings[0].Value, 2.5, t)
+  })
+  tp.Run("TestCombineDriversWeightedAverageOfMixedOperationsAtAnyPremium", func(t *testing.T) {
+    // on a premium of 500 the drivers give 450 and 510, whose average of 480 is 0.95 times 500 plus 5
+    _, licence, loadings := strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleWeightedAverage}, 500)
+    chain := strategies.ChainFactors(&pricingengine.GeneratePricingRequest{}, append([]*models.RangeConfig{licence}, loadings...))
+    item, err := strategies.ApplyBasePricing(&pricingengine.GeneratePricingRequest{}, &models.RangeConfig{Value: 500, Label: "base"}, chain)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(item.Premium, 480.0, t)
+  })
+  tp.Run("TestCombineDriversMainPlusLoading", func(t *testing.T) {
+    loading := &models.DriverLoading{Factor: 1.15, Label: "Additional driver"}
+    age, _, loadings := strategies.CombineDrivers([]strategy.DriverBands{main, novice}, models.DriverCombination{Rule: strategy.DriverRuleMainPlusLoading, Loading: loading}, 100)
+    util.AssertTrue(age == main.Age, t)
+    util.AssertEqual(*loadings[0], models.RangeConfig{IsEligible: true, Value: 1.15, Label: "Additional driver (Jane)"}, t)
+  })
+}
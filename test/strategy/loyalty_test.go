@@ -0,0 +1,51 @@
+package strategy
+
+import (
+  "testing"
+  "time"
+
+  "pricingengine"
+	"pricingengine/service/model"
+	"pricingengine/service/strategy"
+  "pricingengine/test/util"
+)
+
+
+func TestPricingStrategyLoyaltyFactor(tp *testing.T){
+  var strategies = strategy.Strategy{}
+  now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+  history := &pricingengine.CustomerHistory{
+    CustomerID: "C-1042",
+    Policies: []pricingengine.CustomerPolicy{{BoundOn: "2023-06-01"}, {BoundOn: "2024-06-01"}, {BoundOn: "2025-06-01"}},
+    Claims: []pricingengine.Claim{{Date: "2023-09-14", Type: "accident", Fault: true}},
+  }
+
+  tp.Run("TestClaimFreeRunCountsThePoliciesSinceTheLastClaim", func(t *testing.T) {
+    run, err := strategy.ClaimFreeRun(history, nil, strategy.LoyaltyBasisPolicies, now)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(run, 2, t)
+  })
+  tp.Run("TestClaimFreeRunCountsTheDaysSinceTheFirstPolicyAfterTheLastClaim", func(t *testing.T) {
+    run, _ := strategy.ClaimFreeRun(history, nil, strategy.LoyaltyBasisDays, now)
+    util.AssertEqual(run, 730, t)
+  })
+  tp.Run("TestClaimFreeRunRestartsWithTheClaimsOfTheRequest", func(t *testing.T) {
+    run, _ := strategy.ClaimFreeRun(history, []pricingengine.Claim{{Date: "2025-01-10", Type: "theft"}}, strategy.LoyaltyBasisPolicies, now)
+    util.AssertEqual(run, 1, t)
+    run, _ = strategy.ClaimFreeRun(history, []pricingengine.Claim{{Date: "2026-01-10", Type: "theft"}}, strategy.LoyaltyBasisDays, now)
+    util.AssertEqual(run, 0, t)
+  })
+  tp.Run("TestLoyaltyFactorHighestBandReached", func(t *testing.T) {
+    loyalty := models.LoyaltyFactor{Basis: "policies", Bands: []models.LoyaltyBand{
+      {From: 1, Factor: 0.95, Label: "Loyalty:1+ policies"},
+      {From: 2, Factor: 0.9, Label: "Loyalty:2+ policies"},
+      {From: 5, Factor: 0.75, Label: "Loyalty:5+ policies"},
+    }}
+    band, err := strategies.FindMatchingLoyaltyFactor(&pricingengine.GeneratePricingRequest{CustomerID: "C-1042"}, history, loyalty)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(*band, models.RangeConfig{Start: 2, IsEligible: true, Value: 0.9, Label: "Loyalty:2+ policies (2 claim-free policies)"}, t)
+
+    band, _ = strategies.FindMatchingLoyaltyFactor(&pricingengine.GeneratePricingRequest{}, history, loyalty)
+    util.AssertTrue(band == nil, t)
+  })
+}
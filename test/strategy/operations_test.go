@@ -0,0 +1,76 @@
+package strategy
+
+import (
+  "testing"
+
+  "pricingengine"
+	"pricingengine/service/model"
+	"pricingengine/service/strategy"
+  "pricingengine/test/util"
+)
+
+
+func TestPricingStrategyOperations(tp *testing.T){
+  var strategies = strategy.Strategy{}
+  request := pricingengine.GeneratePricingRequest{
+    DateOfBirth: "2001-01-02",
+    InsuranceGroup: 7,
+    LicenseHeldSince: "2006-01-02",
+  }
+  baseRate := models.RangeConfig{IsEligible: true, Value: 200, Label: "Base Fare Range"}
+  price := func(factors ...models.RangeConfig) *pricingengine.PricingItem {
+    chain := []*models.RangeConfig{}
+    for i := range factors {
+      chain = append(chain, &factors[i])
+    }
+    resp, err := strategies.ApplyBasePricing(&request, &baseRate, strategies.ChainFactors(&request, chain))
+    if err != nil {
+      tp.Fatal(err)
+    }
+    return resp
+  }
+
+  tp.Run("TestOperationsInTheirOrder", func(t *testing.T) {
+    resp := price(
+      models.RangeConfig{IsEligible: true, Value: 10, Label: "Online", Operation: strategy.OperationPercentDiscount},
+      models.RangeConfig{IsEligible: true, Value: 5, Label: "New driver", Operation: strategy.OperationAdd},
+      models.RangeConfig{IsEligible: true, Value: 1.5, Label: "Driver Age:16-26"},
+      models.RangeConfig{IsEligible: true, Value: 20, Label: "Garaged", Operation: strategy.OperationSubtract},
+    )
+
+    util.AssertEqual(resp.FareGroup, "Base Fare Range, Driver Age:16-26, New driver, Garaged, Online", t)
+    util.AssertEqual(resp.Breakdown, []pricingengine.PricingStep{
+      {Label: "Base Fare Range", Premium: 200},
+      {Label: "Driver Age:16-26", Factor: 1.5, Premium: 300},
+      {Label: "New driver", Factor: 5, Operation: "add", Premium: 305},
+      {Label: "Garaged", Factor: 20, Operation: "subtract", Premium: 285},
+      {Label: "Online", Factor: 10, Operation: "percent-discount", Premium: 256.5},
+    }, t)
+    util.AssertEqual(resp.Premium, 256.5, t)
+  })
+  tp.Run("TestOperationsOverrideLast", func(t *testing.T) {
+    resp := price(
+      models.RangeConfig{IsEligible: true, Value: 99.5, Label: "Staff", Operation: strategy.OperationOverride},
+      models.RangeConfig{IsEligible: true, Value: 2, Label: "Insurance Group:9-12", Operation: strategy.OperationMultiply},
+      models.RangeConfig{IsEligible: true, Value: 5, Label: "New driver", Operation: strategy.OperationAdd},
+    )
+
+    util.AssertEqual(resp.FareGroup, "Base Fare Range, Insurance Group:9-12, New driver, Staff", t)
+    util.AssertEqual(resp.Breakdown[2].Premium, 405.0, t)
+    util.AssertEqual(resp.Premium, 99.5, t)
+  })
+  tp.Run("TestOperationsSubtractNotBelowZero", func(t *testing.T) {
+    resp := price(models.RangeConfig{IsEligible: true, Value: 250, Label: "Voucher", Operation: strategy.OperationSubtract})
+
+    util.AssertEqual(resp.Premium, 0.0, t)
+  })
+  tp.Run("TestOperationsKeepMultipliersInOrder", func(t *testing.T) {
+    resp := price(
+      models.RangeConfig{IsEligible: true, Value: 1.1, Label: "First"},
+      models.RangeConfig{IsEligible: true, Value: 1.2, Label: "Second"},
+    )
+
+    util.AssertEqual(resp.FareGroup, "Base Fare Range, First, Second", t)
+    util.AssertEqual(resp.Premium, 264.0, t)
+  })
+}
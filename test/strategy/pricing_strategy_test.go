@@ -318,3 +318,18 @@ func TestPricingStrategyVariedConfigs(tp *testing.T){
     util.AssertTrue(driver_factor_range == nil, t)
   })
 }
+
+func TestParseCoverStart(tp *testing.T){
+  tp.Run("TestParseCoverStartWithOrWithoutOffset", func(t *testing.T) {
+    start, err := strategy.ParseCoverStart("2026-06-01T09:30:00+02:00", time.UTC)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(start.UTC().Hour(), 7, t)
+    start, err = strategy.ParseCoverStart("2026-06-01T09:30:00", time.UTC)
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(start.Hour(), 9, t)
+  })
+  tp.Run("TestParseCoverStartReportsTheLocalTimeError", func(t *testing.T) {
+    _, err := strategy.ParseCoverStart("2026-06-01 09:30", time.UTC)
+    util.AssertEqual(err.Error(), "Error wile Parsing CoverStart. Error: neither an RFC 3339 timestamp nor a local time: parsing time \"2026-06-01 09:30\" as \"2006-01-02T15:04:05\": cannot parse \" 09:30\" as \"T\"", t)
+  })
+}
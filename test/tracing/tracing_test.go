@@ -0,0 +1,171 @@
+package tracing
+
+import (
+  "bytes"
+  "context"
+  "encoding/json"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+
+  "pricingengine"
+  "pricingengine/service"
+  "pricingengine/service/app"
+  "pricingengine/service/config"
+  "pricingengine/service/rpc"
+  "pricingengine/service/settings"
+  "pricingengine/service/tracing"
+  "pricingengine/test/util"
+
+  "go.opentelemetry.io/otel"
+  "go.opentelemetry.io/otel/codes"
+  "go.opentelemetry.io/otel/propagation"
+  sdktrace "go.opentelemetry.io/otel/sdk/trace"
+  "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+
+// RecordSpans installs a tracer provider recording every span in memory for the duration of the test
+func RecordSpans(t *testing.T) *tracetest.SpanRecorder {
+  previous := otel.GetTracerProvider()
+  recorder := tracetest.NewSpanRecorder()
+  otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+  otel.SetTextMapPropagator(propagation.TraceContext{})
+  t.Cleanup(func() { otel.SetTracerProvider(previous) })
+  return recorder
+}
+
+// SpansByName groups the ended spans of the recorder by their name
+func SpansByName(recorder *tracetest.SpanRecorder) map[string][]sdktrace.ReadOnlySpan {
+  result := map[string][]sdktrace.ReadOnlySpan{}
+  for _, span := range recorder.Ended() {
+    result[span.Name()] = append(result[span.Name()], span)
+  }
+  return result
+}
+
+func Attribute(span sdktrace.ReadOnlySpan, key string) interface{} {
+  for _, attribute := range span.Attributes() {
+    if string(attribute.Key) == key {
+      return attribute.Value.AsInterface()
+    }
+  }
+  return nil
+}
+
+func PostGeneratePricing(router http.Handler, request *pricingengine.GeneratePricingRequest, header http.Header) {
+  body, _ := json.Marshal(request)
+  req := httptest.NewRequest(http.MethodPost, "/generate_pricing", bytes.NewReader(body))
+  req.Header.Set("Content-Type", "application/json")
+  for name := range header {
+    req.Header.Set(name, header.Get(name))
+  }
+  router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestPricingSpans(tp *testing.T){
+  now := time.Now()
+  valid := pricingengine.GeneratePricingRequest{
+    DateOfBirth: now.AddDate(-20, 0, 0).Format("2006-01-02"),
+    InsuranceGroup: 7,
+    LicenseHeldSince: now.AddDate(-7, 0, 0).Format("2006-01-02"),
+  }
+
+  tp.Run("TestQuoteIsTracedFromHandlerToConfigFetches", func(t *testing.T) {
+    recorder := RecordSpans(t)
+    rpc := rpc.RPC{App: &app.App{ConfigPath: "../test_configs", CacheTTL: 1000}}
+    PostGeneratePricing(service.NewRouter(&rpc, settings.Default()), &valid, nil)
+
+    spans := SpansByName(recorder)
+    util.AssertEqual(len(spans["POST /generate_pricing"]), 1, t)
+    util.AssertEqual(len(spans["app.GeneratePricing"]), 1, t)
+    util.AssertEqual(len(spans["app.ValidateRequest"]), 1, t)
+    util.AssertEqual(len(spans["config.Initialise"]), 1, t)
+    util.AssertEqual(len(spans["config.FetchAndConvert"]), len(config.FactorFiles), t)
+    util.AssertEqual(len(spans["strategy.FindMatchingDriverAgeFactor"]), 1, t)
+    util.AssertEqual(len(spans["strategy.FindMatchingInsuranceGroupFactor"]), 1, t)
+    util.AssertEqual(len(spans["strategy.FindMatchingLicenceValidityFactor"]), 1, t)
+    util.AssertEqual(len(spans["app.PricingChain"]), 2, t)
+
+    handler := spans["POST /generate_pricing"][0]
+    quote := spans["app.GeneratePricing"][0]
+    initialise := spans["config.Initialise"][0]
+    util.AssertEqual(quote.Parent().SpanID(), handler.SpanContext().SpanID(), t)
+    util.AssertEqual(initialise.Parent().SpanID(), quote.SpanContext().SpanID(), t)
+    for _, fetch := range spans["config.FetchAndConvert"] {
+      util.AssertEqual(fetch.Parent().SpanID(), initialise.SpanContext().SpanID(), t)
+    }
+    for _, chain := range spans["app.PricingChain"] {
+      util.AssertEqual(chain.Parent().SpanID(), quote.SpanContext().SpanID(), t)
+    }
+    util.AssertEqual(Attribute(spans["app.PricingChain"][0], "pricing.base_rate"), "0.5 hours", t)
+    util.AssertEqual(Attribute(handler, "http.route"), "/generate_pricing", t)
+  })
+  tp.Run("TestDeclineIsRecordedOnTheFactorLookup", func(t *testing.T) {
+    recorder := RecordSpans(t)
+    rpc := rpc.RPC{App: &app.App{ConfigPath: "../test_configs", CacheTTL: 1000}}
+    declined := valid
+    declined.InsuranceGroup = 20
+    PostGeneratePricing(service.NewRouter(&rpc, settings.Default()), &declined, nil)
+
+    spans := SpansByName(recorder)
+    lookup := spans["strategy.FindMatchingInsuranceGroupFactor"][0]
+    util.AssertEqual(Attribute(lookup, "pricing.band"), "Insurance Group:8", t)
+    util.AssertEqual(Attribute(lookup, "pricing.eligible"), false, t)
+    util.AssertEqual(lookup.Status().Code, codes.Unset, t)
+    util.AssertEqual(len(spans["app.PricingChain"]), 0, t)
+  })
+  tp.Run("TestInvalidFieldIsRecordedOnTheValidation", func(t *testing.T) {
+    recorder := RecordSpans(t)
+    rpc := rpc.RPC{App: &app.App{ConfigPath: "../test_configs", CacheTTL: 1000}}
+    PostGeneratePricing(service.NewRouter(&rpc, settings.Default()), &pricingengine.GeneratePricingRequest{InsuranceGroup: 7}, nil)
+
+    validation := SpansByName(recorder)["app.ValidateRequest"][0]
+    util.AssertEqual(Attribute(validation, "pricing.invalid_field"), "date_of_birth", t)
+  })
+  tp.Run("TestFailedConfigFetchIsRecorded", func(t *testing.T) {
+    recorder := RecordSpans(t)
+    rpc := rpc.RPC{App: &app.App{ConfigPath: "../missing_configs", CacheTTL: 1000}}
+    PostGeneratePricing(service.NewRouter(&rpc, settings.Default()), &valid, nil)
+
+    spans := SpansByName(recorder)
+    util.AssertEqual(spans["config.FetchAndConvert"][0].Status().Code, codes.Error, t)
+    util.AssertEqual(spans["config.Initialise"][0].Status().Code, codes.Error, t)
+  })
+  tp.Run("TestIncomingTraceIsContinued", func(t *testing.T) {
+    recorder := RecordSpans(t)
+    rpc := rpc.RPC{App: &app.App{ConfigPath: "../test_configs", CacheTTL: 1000}}
+    header := http.Header{}
+    header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    PostGeneratePricing(service.NewRouter(&rpc, settings.Default()), &valid, header)
+
+    handler := SpansByName(recorder)["POST /generate_pricing"][0]
+    util.AssertEqual(handler.SpanContext().TraceID().String(), "4bf92f3577b34da6a3ce929d0e0e4736", t)
+    util.AssertEqual(handler.Parent().SpanID().String(), "00f067aa0ba902b7", t)
+  })
+}
+
+func TestTracingSetup(tp *testing.T){
+  tp.Run("TestStdoutExporterWritesSpans", func(t *testing.T) {
+    previous := otel.GetTracerProvider()
+    defer otel.SetTracerProvider(previous)
+    output := &bytes.Buffer{}
+    shutdown, err := tracing.Setup(context.Background(), tracing.Options{Exporter: "stdout", SampleRatio: 1, Writer: output})
+    util.AssertTrue(err == nil, t)
+    _, span := tracing.Start(context.Background(), "test.Span")
+    span.End()
+    shutdown(context.Background())
+    util.AssertTrue(strings.Contains(output.String(), `"Name":"test.Span"`), t)
+  })
+  tp.Run("TestNoneExporterLeavesTracingDisabled", func(t *testing.T) {
+    shutdown, err := tracing.Setup(context.Background(), tracing.Options{Exporter: "none"})
+    util.AssertTrue(err == nil, t)
+    util.AssertEqual(shutdown(context.Background()), nil, t)
+  })
+  tp.Run("TestUnknownExporterIsRejected", func(t *testing.T) {
+    _, err := tracing.Setup(context.Background(), tracing.Options{Exporter: "zipkin"})
+    util.AssertTrue(err != nil, t)
+  })
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+  "io/ioutil"
+  "path/filepath"
+  "testing"
+)
+
+// CopyConfigsToTempDir copies the factor documents under the given directory to a
+// fresh temporary directory and returns its absolute path
+func CopyConfigsToTempDir(source string, t *testing.T) string {
+  tmp := t.TempDir()
+  files, err := filepath.Glob(filepath.Join(source, "*.json"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, file := range files {
+    data, err := ioutil.ReadFile(file)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if err = ioutil.WriteFile(filepath.Join(tmp, filepath.Base(file)), data, 0644); err != nil {
+      t.Fatal(err)
+    }
+  }
+  return tmp
+}
@@ -0,0 +1,50 @@
+package util
+import (
+  "testing"
+
+	"pricingengine/service/util"
+)
+
+
+func TestPostcodeNormalisation(tp *testing.T){
+  tp.Run("TestNormalisePostcodeFormatsValidPostcodes", func(t *testing.T) {
+    for input, expected := range map[string]string{
+      "sw1a1aa": "SW1A 1AA",
+      " SW1A  1AA ": "SW1A 1AA",
+      "m1 1ae": "M1 1AE",
+      "B33 8TH": "B33 8TH",
+      "cr26xh": "CR2 6XH",
+      "DN551PT": "DN55 1PT",
+    } {
+      postcode, ok := util.NormalisePostcode(input)
+      AssertTrue(ok, t)
+      AssertEqual(postcode, expected, t)
+    }
+  })
+  tp.Run("TestNormalisePostcodeRejectsInvalidPostcodes", func(t *testing.T) {
+    for _, input := range []string{"", "SW1A", "12345", "SW1A 1A", "SWW1A 1AA", "SW1A 1AAA", "1W1A 1AA"} {
+      _, ok := util.NormalisePostcode(input)
+      AssertFalse(ok, t)
+    }
+  })
+  tp.Run("TestNormalisePostcodePrefix", func(t *testing.T) {
+    for input, expected := range map[string]string{
+      "sw": "SW",
+      "sw1a": "SW1A",
+      "sw1a  1": "SW1A 1",
+      "sw1a1aa": "SW1A 1AA",
+    } {
+      prefix, ok := util.NormalisePostcodePrefix(input)
+      AssertTrue(ok, t)
+      AssertEqual(prefix, expected, t)
+    }
+    for _, input := range []string{"", "*", "1SW", "SW1A 1A"} {
+      _, ok := util.NormalisePostcodePrefix(input)
+      AssertFalse(ok, t)
+    }
+  })
+  tp.Run("TestPostcodePrefixesLongestFirst", func(t *testing.T) {
+    AssertEqual(util.PostcodePrefixes("SW1A 1AA"), []string{"SW1A 1AA", "SW1A 1", "SW1A", "SW"}, t)
+    AssertEqual(util.PostcodePrefixes("E10 5NP"), []string{"E10 5NP", "E10 5", "E10", "E"}, t)
+  })
+}